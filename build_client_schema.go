@@ -0,0 +1,453 @@
+package graphql
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+// errClientSchemaExecution is returned by every resolver of a client schema.
+var errClientSchemaExecution = errors.New("Client schemas built from an introspection result cannot be executed.")
+
+// BuildClientSchema builds a Schema from the result of an introspection query,
+// such as testutil.IntrospectionQuery, given as the "data" of the response
+// (i.e. a map holding the "__schema" key).
+//
+// The schema holds no Go code of the service it describes, so it can be used
+// to validate queries but not to execute them: every field resolves to an error.
+func BuildClientSchema(introspection map[string]interface{}) (Schema, error) {
+	schemaIntrospection, ok := introspection["__schema"].(map[string]interface{})
+	if !ok {
+		return Schema{}, fmt.Errorf(`Invalid or incomplete introspection result. Ensure that you `+
+			`are passing "data" property of introspection response and no "errors" `+
+			`was returned alongside: %v.`, introspection)
+	}
+
+	b := &clientSchemaBuilder{
+		typeIntrospections: map[string]map[string]interface{}{},
+		types:              map[string]Type{},
+	}
+	for _, ttype := range specifiedScalarTypes {
+		b.types[ttype.Name()] = ttype
+	}
+	// Introspection types are always added by the schema itself.
+	for _, ttype := range []Type{SchemaType, DirectiveType, TypeType, FieldType, InputValueType, EnumValueType, TypeKindEnumType, DirectiveLocationEnumType} {
+		b.types[ttype.Name()] = ttype
+	}
+	typeNames := []string{}
+	for _, value := range listValue(schemaIntrospection["types"]) {
+		typeIntrospection, ok := value.(map[string]interface{})
+		name, _ := typeIntrospection["name"].(string)
+		if !ok || name == "" {
+			return Schema{}, fmt.Errorf("Invalid or incomplete schema, unknown type: %v.", value)
+		}
+		b.typeIntrospections[name] = typeIntrospection
+		typeNames = append(typeNames, name)
+	}
+	if err := invariant(len(typeNames) > 0, "Invalid or incomplete schema, missing types."); err != nil {
+		return Schema{}, err
+	}
+
+	types := []Type{}
+	for _, name := range typeNames {
+		if strings.HasPrefix(name, "__") {
+			continue
+		}
+		ttype, err := b.namedType(name)
+		if err != nil {
+			return Schema{}, err
+		}
+		types = append(types, ttype)
+	}
+
+	config := SchemaConfig{Types: types}
+	var err error
+	if config.Query, err = b.rootType(schemaIntrospection, "queryType"); err != nil {
+		return Schema{}, err
+	}
+	if config.Mutation, err = b.rootType(schemaIntrospection, "mutationType"); err != nil {
+		return Schema{}, err
+	}
+	if config.Subscription, err = b.rootType(schemaIntrospection, "subscriptionType"); err != nil {
+		return Schema{}, err
+	}
+
+	if directives, ok := schemaIntrospection["directives"]; ok && directives != nil {
+		config.Directives = []*Directive{}
+		for _, value := range listValue(directives) {
+			directive, err := b.buildDirective(value)
+			if err != nil {
+				return Schema{}, err
+			}
+			config.Directives = append(config.Directives, directive)
+		}
+	}
+
+	schema, err := NewSchema(config)
+	// The fields of the types are built by thunks evaluated within NewSchema,
+	// their errors are more telling than the ones of the incomplete types.
+	if b.err != nil {
+		return Schema{}, b.err
+	}
+	if err != nil {
+		return Schema{}, err
+	}
+	return schema, nil
+}
+
+type clientSchemaBuilder struct {
+	typeIntrospections map[string]map[string]interface{}
+	types              map[string]Type
+
+	// err is the first error met while building types within thunks.
+	err error
+}
+
+func listValue(value interface{}) []interface{} {
+	list, _ := value.([]interface{})
+	return list
+}
+
+func stringValue(value interface{}) string {
+	s, _ := value.(string)
+	return s
+}
+
+func (b *clientSchemaBuilder) namedType(name string) (Type, error) {
+	if ttype, ok := b.types[name]; ok {
+		return ttype, nil
+	}
+	typeIntrospection, ok := b.typeIntrospections[name]
+	if !ok {
+		return nil, fmt.Errorf(`Invalid or incomplete schema, unknown type: %v. Ensure that a full `+
+			`introspection query is used in order to build a client schema.`, name)
+	}
+
+	var ttype Type
+	var err error
+	switch kind := stringValue(typeIntrospection["kind"]); kind {
+	case TypeKindScalar:
+		ttype = b.buildScalar(typeIntrospection)
+	case TypeKindObject:
+		ttype = b.buildObject(typeIntrospection)
+	case TypeKindInterface:
+		ttype = b.buildInterface(typeIntrospection)
+	case TypeKindUnion:
+		ttype = b.buildUnion(typeIntrospection)
+	case TypeKindEnum:
+		ttype, err = b.buildEnum(typeIntrospection)
+	case TypeKindInputObject:
+		ttype = b.buildInputObject(typeIntrospection)
+	default:
+		err = fmt.Errorf(`Invalid or incomplete introspection result. Ensure that a full `+
+			`introspection query is used in order to build a client schema: %v.`, typeIntrospection)
+	}
+	if err != nil {
+		return nil, err
+	}
+	b.types[name] = ttype
+	return ttype, nil
+}
+
+// typeRef returns the type referenced by the given introspection type reference.
+func (b *clientSchemaBuilder) typeRef(value interface{}) (Type, error) {
+	typeRef, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Decorated type deeper than introspection query.")
+	}
+	switch stringValue(typeRef["kind"]) {
+	case TypeKindList:
+		ofType, err := b.typeRef(typeRef["ofType"])
+		if err != nil {
+			return nil, err
+		}
+		return NewList(ofType), nil
+	case TypeKindNonNull:
+		ofType, err := b.typeRef(typeRef["ofType"])
+		if err != nil {
+			return nil, err
+		}
+		return NewNonNull(ofType), nil
+	}
+	name := stringValue(typeRef["name"])
+	if name == "" {
+		return nil, fmt.Errorf("Unknown type reference: %v.", typeRef)
+	}
+	return b.namedType(name)
+}
+
+// thunkTypeRef is typeRef for use within thunks, recording the first error.
+func (b *clientSchemaBuilder) thunkTypeRef(value interface{}) Type {
+	ttype, err := b.typeRef(value)
+	if err != nil && b.err == nil {
+		b.err = err
+	}
+	return ttype
+}
+
+func (b *clientSchemaBuilder) rootType(schemaIntrospection map[string]interface{}, key string) (*Object, error) {
+	typeRef, ok := schemaIntrospection[key].(map[string]interface{})
+	if !ok {
+		if key == "queryType" {
+			return nil, fmt.Errorf("Invalid or incomplete schema, missing query type.")
+		}
+		return nil, nil
+	}
+	ttype, err := b.namedType(stringValue(typeRef["name"]))
+	if err != nil {
+		return nil, err
+	}
+	object, ok := ttype.(*Object)
+	if !ok {
+		return nil, fmt.Errorf("Root type %v must be an Object type.", ttype)
+	}
+	return object, nil
+}
+
+func (b *clientSchemaBuilder) buildScalar(typeIntrospection map[string]interface{}) *Scalar {
+	return NewScalar(ScalarConfig{
+		Name:        stringValue(typeIntrospection["name"]),
+		Description: stringValue(typeIntrospection["description"]),
+		Serialize: func(value interface{}) interface{} {
+			return value
+		},
+		ParseValue: func(value interface{}) interface{} {
+			return value
+		},
+		ParseLiteral: func(valueAST ast.Value) interface{} {
+			return valueFromASTUntyped(valueAST, nil)
+		},
+	})
+}
+
+func (b *clientSchemaBuilder) buildObject(typeIntrospection map[string]interface{}) *Object {
+	return NewObject(ObjectConfig{
+		Name:        stringValue(typeIntrospection["name"]),
+		Description: stringValue(typeIntrospection["description"]),
+		Interfaces: InterfacesThunk(func() []*Interface {
+			return b.buildInterfaces(typeIntrospection)
+		}),
+		Fields: FieldsThunk(func() Fields {
+			return b.buildFields(typeIntrospection)
+		}),
+	})
+}
+
+// buildInterfaces returns the interfaces implemented by an object or interface type.
+func (b *clientSchemaBuilder) buildInterfaces(typeIntrospection map[string]interface{}) []*Interface {
+	interfaces := []*Interface{}
+	for _, typeRef := range listValue(typeIntrospection["interfaces"]) {
+		ttype := b.thunkTypeRef(typeRef)
+		iface, ok := ttype.(*Interface)
+		if !ok {
+			if ttype != nil && b.err == nil {
+				b.err = fmt.Errorf("Expected %v to be an Interface type.", ttype)
+			}
+			continue
+		}
+		interfaces = append(interfaces, iface)
+	}
+	return interfaces
+}
+
+func (b *clientSchemaBuilder) buildInterface(typeIntrospection map[string]interface{}) *Interface {
+	return NewInterface(InterfaceConfig{
+		Name:        stringValue(typeIntrospection["name"]),
+		Description: stringValue(typeIntrospection["description"]),
+		Interfaces: InterfacesThunk(func() []*Interface {
+			return b.buildInterfaces(typeIntrospection)
+		}),
+		Fields: FieldsThunk(func() Fields {
+			return b.buildFields(typeIntrospection)
+		}),
+		ResolveType: resolveClientType,
+	})
+}
+
+func (b *clientSchemaBuilder) buildUnion(typeIntrospection map[string]interface{}) *Union {
+	return NewUnion(UnionConfig{
+		Name:        stringValue(typeIntrospection["name"]),
+		Description: stringValue(typeIntrospection["description"]),
+		Types: UnionTypesThunk(func() []*Object {
+			types := []*Object{}
+			for _, typeRef := range listValue(typeIntrospection["possibleTypes"]) {
+				ttype := b.thunkTypeRef(typeRef)
+				object, ok := ttype.(*Object)
+				if !ok {
+					if ttype != nil && b.err == nil {
+						b.err = fmt.Errorf("Expected %v to be an Object type.", ttype)
+					}
+					continue
+				}
+				types = append(types, object)
+			}
+			return types
+		}),
+		ResolveType: resolveClientType,
+	})
+}
+
+func (b *clientSchemaBuilder) buildEnum(typeIntrospection map[string]interface{}) (*Enum, error) {
+	values := EnumValueConfigMap{}
+	for _, value := range listValue(typeIntrospection["enumValues"]) {
+		valueIntrospection, ok := value.(map[string]interface{})
+		name := stringValue(valueIntrospection["name"])
+		if !ok || name == "" {
+			return nil, fmt.Errorf("Introspection result missing enumValues: %v.", typeIntrospection)
+		}
+		values[name] = &EnumValueConfig{
+			Value:             name,
+			Description:       stringValue(valueIntrospection["description"]),
+			DeprecationReason: introspectedDeprecationReason(valueIntrospection),
+		}
+	}
+	return NewEnum(EnumConfig{
+		Name:        stringValue(typeIntrospection["name"]),
+		Description: stringValue(typeIntrospection["description"]),
+		Values:      values,
+	}), nil
+}
+
+func (b *clientSchemaBuilder) buildInputObject(typeIntrospection map[string]interface{}) *InputObject {
+	return NewInputObject(InputObjectConfig{
+		Name:        stringValue(typeIntrospection["name"]),
+		Description: stringValue(typeIntrospection["description"]),
+		Fields: InputObjectConfigFieldMapThunk(func() InputObjectConfigFieldMap {
+			fields := InputObjectConfigFieldMap{}
+			for _, value := range listValue(typeIntrospection["inputFields"]) {
+				fieldIntrospection, _ := value.(map[string]interface{})
+				ttype := b.thunkTypeRef(fieldIntrospection["type"])
+				if ttype == nil {
+					continue
+				}
+				fields[stringValue(fieldIntrospection["name"])] = &InputObjectFieldConfig{
+					Type:         ttype,
+					Description:  stringValue(fieldIntrospection["description"]),
+					DefaultValue: b.defaultValue(fieldIntrospection, ttype),
+				}
+			}
+			return fields
+		}),
+	})
+}
+
+func (b *clientSchemaBuilder) buildFields(typeIntrospection map[string]interface{}) Fields {
+	fields := Fields{}
+	for _, value := range listValue(typeIntrospection["fields"]) {
+		fieldIntrospection, _ := value.(map[string]interface{})
+		ttype := b.thunkTypeRef(fieldIntrospection["type"])
+		if ttype == nil {
+			continue
+		}
+		name := stringValue(fieldIntrospection["name"])
+		fields[name] = &Field{
+			Name:              name,
+			Type:              ttype,
+			Description:       stringValue(fieldIntrospection["description"]),
+			DeprecationReason: introspectedDeprecationReason(fieldIntrospection),
+			Args:              b.buildArgs(fieldIntrospection["args"]),
+			Resolve: func(p ResolveParams) (interface{}, error) {
+				return nil, errClientSchemaExecution
+			},
+		}
+	}
+	return fields
+}
+
+func (b *clientSchemaBuilder) buildArgs(value interface{}) FieldConfigArgument {
+	args := FieldConfigArgument{}
+	for _, value := range listValue(value) {
+		argIntrospection, _ := value.(map[string]interface{})
+		ttype := b.thunkTypeRef(argIntrospection["type"])
+		if ttype == nil {
+			continue
+		}
+		args[stringValue(argIntrospection["name"])] = &ArgumentConfig{
+			Type:         ttype,
+			Description:  stringValue(argIntrospection["description"]),
+			DefaultValue: b.defaultValue(argIntrospection, ttype),
+		}
+	}
+	return args
+}
+
+// defaultValue parses the GraphQL-formatted default value of an input value.
+func (b *clientSchemaBuilder) defaultValue(inputIntrospection map[string]interface{}, ttype Type) interface{} {
+	defaultValue, ok := inputIntrospection["defaultValue"].(string)
+	if !ok {
+		return nil
+	}
+	valueAST, err := parser.ParseValue(parser.ParseParams{
+		Source:  defaultValue,
+		Options: parser.ParseOptions{NoLocation: true},
+	})
+	if err != nil {
+		if b.err == nil {
+			b.err = err
+		}
+		return nil
+	}
+	return valueFromAST(valueAST, ttype, nil)
+}
+
+func (b *clientSchemaBuilder) buildDirective(value interface{}) (*Directive, error) {
+	directiveIntrospection, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Invalid or incomplete directive: %v.", value)
+	}
+
+	locations := []string{}
+	if locationsValue, ok := directiveIntrospection["locations"]; ok {
+		for _, location := range listValue(locationsValue) {
+			locations = append(locations, stringValue(location))
+		}
+	} else {
+		// Introspection results of older servers describe locations with
+		// the onOperation, onFragment and onField fields.
+		if onOperation, _ := directiveIntrospection["onOperation"].(bool); onOperation {
+			locations = append(locations, DirectiveLocationQuery, DirectiveLocationMutation, DirectiveLocationSubscription)
+		}
+		if onFragment, _ := directiveIntrospection["onFragment"].(bool); onFragment {
+			locations = append(locations, DirectiveLocationFragmentSpread, DirectiveLocationInlineFragment)
+		}
+		if onField, _ := directiveIntrospection["onField"].(bool); onField {
+			locations = append(locations, DirectiveLocationField)
+		}
+	}
+
+	args := b.buildArgs(directiveIntrospection["args"])
+	if b.err != nil {
+		return nil, b.err
+	}
+	isRepeatable, _ := directiveIntrospection["isRepeatable"].(bool)
+	directive := NewDirective(DirectiveConfig{
+		Name:         stringValue(directiveIntrospection["name"]),
+		Description:  stringValue(directiveIntrospection["description"]),
+		Locations:    locations,
+		Args:         args,
+		IsRepeatable: isRepeatable,
+	})
+	if directive.err != nil {
+		return nil, directive.err
+	}
+	return directive, nil
+}
+
+func introspectedDeprecationReason(introspection map[string]interface{}) string {
+	if isDeprecated, _ := introspection["isDeprecated"].(bool); !isDeprecated {
+		return ""
+	}
+	if reason := stringValue(introspection["deprecationReason"]); reason != "" {
+		return reason
+	}
+	return DefaultDeprecationReason
+}
+
+// resolveClientType is the ResolveTypeFn of abstract types in client schemas,
+// which never resolve values.
+func resolveClientType(p ResolveTypeParams) *Object {
+	return nil
+}
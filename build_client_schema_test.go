@@ -0,0 +1,145 @@
+package graphql_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+// introspectionOf returns the JSON decoded introspection result of the schema.
+func introspectionOf(t *testing.T, schema graphql.Schema) map[string]interface{} {
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: testutil.IntrospectionQuery,
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("unexpected introspection errors: %v", result.Errors)
+	}
+	b, err := json.Marshal(result.Data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	introspection := map[string]interface{}{}
+	if err := json.Unmarshal(b, &introspection); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return introspection
+}
+
+func TestBuildClientSchema_RoundTripsStarWarsSchema(t *testing.T) {
+	clientSchema, err := graphql.BuildClientSchema(introspectionOf(t, testutil.StarWarsSchema))
+	if err != nil {
+		t.Fatalf("unexpected error building client schema: %v", err)
+	}
+	expected := graphql.PrintSchema(testutil.StarWarsSchema)
+	if printed := graphql.PrintSchema(clientSchema); printed != expected {
+		t.Fatalf("Unexpected schema, Diff: %v", testutil.Diff(expected, printed))
+	}
+}
+
+func TestBuildClientSchema_RoundTripsDefaultsDeprecationsAndDirectives(t *testing.T) {
+	sdl := `schema {
+  query: Root
+  mutation: Actions
+}
+
+directive @cached(ttl: Int = 60) on FIELD | QUERY
+
+type Actions {
+  save(input: Input!): Boolean
+}
+
+enum Color {
+  BLUE @deprecated(reason: "Use RED")
+  RED
+}
+
+scalar Date
+
+interface Entity {
+  id: ID!
+}
+
+input Input {
+  color: Color = RED
+  names: [String] = ["a", "b"]
+  when: Date
+}
+
+union Result = Thing
+
+type Root {
+  entities: [Entity]
+  old: String @deprecated
+  search(first: Int = 10, term: String = "*"): [Result]
+}
+
+type Thing implements Entity {
+  id: ID!
+}
+`
+	schema := buildSchemaOrFail(t, sdl)
+	clientSchema, err := graphql.BuildClientSchema(introspectionOf(t, schema))
+	if err != nil {
+		t.Fatalf("unexpected error building client schema: %v", err)
+	}
+	if printed := graphql.PrintSchema(clientSchema); printed != sdl {
+		t.Fatalf("Unexpected schema, Diff: %v", testutil.Diff(sdl, printed))
+	}
+}
+
+func TestBuildClientSchema_ValidatesButDoesNotExecute(t *testing.T) {
+	clientSchema, err := graphql.BuildClientSchema(introspectionOf(t, testutil.StarWarsSchema))
+	if err != nil {
+		t.Fatalf("unexpected error building client schema: %v", err)
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:        clientSchema,
+		RequestString: `{ hero { name unknownField } }`,
+	})
+	if len(result.Errors) != 1 || result.Errors[0].Message != `Cannot query field "unknownField" on type "Character".` {
+		t.Fatalf("expected validation error, got: %v", result.Errors)
+	}
+
+	result = graphql.Do(graphql.Params{
+		Schema:        clientSchema,
+		RequestString: `{ hero { name } }`,
+	})
+	if len(result.Errors) != 1 || !strings.Contains(result.Errors[0].Message, "cannot be executed") {
+		t.Fatalf("expected execution error, got: %v", result.Errors)
+	}
+}
+
+func TestBuildClientSchema_ReportsIncompleteIntrospection(t *testing.T) {
+	_, err := graphql.BuildClientSchema(map[string]interface{}{})
+	if err == nil || !strings.HasPrefix(err.Error(), "Invalid or incomplete introspection result.") {
+		t.Fatalf("expected invalid introspection error, got: %v", err)
+	}
+
+	_, err = graphql.BuildClientSchema(map[string]interface{}{
+		"__schema": map[string]interface{}{
+			"queryType": map[string]interface{}{"name": "Query"},
+			"types": []interface{}{
+				map[string]interface{}{
+					"kind": "OBJECT",
+					"name": "Query",
+					"fields": []interface{}{
+						map[string]interface{}{
+							"name": "missing",
+							"args": []interface{}{},
+							"type": map[string]interface{}{"kind": "OBJECT", "name": "Missing"},
+						},
+					},
+					"interfaces": []interface{}{},
+				},
+			},
+		},
+	})
+	if err == nil || !strings.HasPrefix(err.Error(), "Invalid or incomplete schema, unknown type: Missing.") {
+		t.Fatalf("expected unknown type error, got: %v", err)
+	}
+}
@@ -0,0 +1,1038 @@
+package graphql
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// BuildSchemaOptions options for building a Schema from a schema definition document.
+type BuildSchemaOptions struct {
+	// Types are named types implemented in Go (for e.g. custom scalars with
+	// their own serialization) which are used in place of building a type
+	// with the same name from the document.
+	Types []Type
+}
+
+// specifiedScalarTypes are the scalars every schema document may refer to
+// without declaring them.
+var specifiedScalarTypes = []*Scalar{
+	Int,
+	Float,
+	String,
+	Boolean,
+	ID,
+}
+
+// BuildSchema parses the given schema definition language (SDL) text and
+// builds an executable Schema from it. See BuildASTSchema for details.
+func BuildSchema(sdl string, opts BuildSchemaOptions) (Schema, error) {
+	src := source.NewSource(&source.Source{
+		Body: []byte(sdl),
+		Name: "GraphQL SDL",
+	})
+	doc, err := parser.Parse(parser.ParseParams{Source: src})
+	if err != nil {
+		return Schema{}, err
+	}
+	return BuildASTSchema(doc, opts)
+}
+
+// BuildASTSchema builds an executable Schema from a parsed schema definition
+// document.
+//
+// The root operation types are taken from the `schema {}` definition when
+// present, otherwise from the types named Query, Mutation and Subscription.
+// Fields are resolved with DefaultResolveFn, declared scalars pass values
+// through unchanged and abstract types resolve to the Object type named by
+// the `__typename` key of a map value or the Go type name of a struct value,
+// falling back to the isTypeOf functions of their possible types.
+func BuildASTSchema(doc *ast.Document, opts BuildSchemaOptions) (Schema, error) {
+	if err := invariant(doc != nil, "Must provide a schema definition document."); err != nil {
+		return Schema{}, err
+	}
+
+	b := newSchemaBuilder(opts)
+	if err := b.collectDefinitions(doc); err != nil {
+		return Schema{}, err
+	}
+	for _, name := range b.typeDefNames {
+		b.namedType(name)
+	}
+	if err := b.checkDefinitions(); err != nil {
+		return Schema{}, err
+	}
+
+	directives, err := b.buildDirectives(SpecifiedDirectives)
+	if err != nil {
+		return Schema{}, err
+	}
+	config, err := b.buildRootTypes()
+	if err != nil {
+		return Schema{}, err
+	}
+	config.Directives = directives
+	config.Types = b.builtTypes()
+	config.AppliedDirectives = b.schemaAppliedDirectives()
+	b.applyDirectives()
+	return NewSchema(config)
+}
+
+// typeDefinitionNode is implemented by the named type definitions of a document.
+type typeDefinitionNode interface {
+	ast.TypeDefinition
+	GetName() *ast.Name
+}
+
+type schemaBuilder struct {
+	typeDefs         map[string]typeDefinitionNode
+	typeDefNames     []string
+	extensions       map[string][]typeDefinitionNode
+	extensionNames   []string
+	schemaDef        *ast.SchemaDefinition
+	schemaExtensions []*ast.SchemaDefinition
+	directiveDefs    []*ast.DirectiveDefinition
+
+	// types holds the provided types as well as every type built so far.
+	types         map[string]Type
+	providedTypes []Type
+
+	// schema is the schema being extended by ExtendSchema, if any.
+	schema *Schema
+
+	// pendingDirectives set the directives applied to the types built so
+	// far, once every type is built as they may refer to any type.
+	pendingDirectives []func()
+	directiveArgs     map[string][]*Argument
+}
+
+func newSchemaBuilder(opts BuildSchemaOptions) *schemaBuilder {
+	b := &schemaBuilder{
+		typeDefs:      map[string]typeDefinitionNode{},
+		extensions:    map[string][]typeDefinitionNode{},
+		types:         map[string]Type{},
+		directiveArgs: map[string][]*Argument{},
+	}
+	for _, ttype := range specifiedScalarTypes {
+		b.types[ttype.Name()] = ttype
+	}
+	for _, ttype := range opts.Types {
+		if ttype == nil {
+			continue
+		}
+		b.types[ttype.Name()] = ttype
+		b.providedTypes = append(b.providedTypes, ttype)
+	}
+	return b
+}
+
+func newBuildError(message string, node ast.Node) error {
+	return gqlerrors.NewError(message, []ast.Node{node}, "", nil, []int{}, nil)
+}
+
+func (b *schemaBuilder) collectDefinitions(doc *ast.Document) error {
+	for _, def := range doc.Definitions {
+		switch def := def.(type) {
+		case *ast.SchemaDefinition:
+			if b.schema != nil {
+				return newBuildError("Cannot define a new schema within a schema extension.", def)
+			}
+			if b.schemaDef != nil {
+				return newBuildError("Must provide only one schema definition.", def)
+			}
+			b.schemaDef = def
+		case *ast.SchemaExtensionDefinition:
+			if def.Definition != nil {
+				b.schemaExtensions = append(b.schemaExtensions, def.Definition)
+			}
+		case *ast.TypeExtensionDefinition:
+			if def.Definition != nil {
+				b.addExtension(def.Definition)
+			}
+		case *ast.ScalarExtensionDefinition:
+			if def.Definition != nil {
+				b.addExtension(def.Definition)
+			}
+		case *ast.InterfaceExtensionDefinition:
+			if def.Definition != nil {
+				b.addExtension(def.Definition)
+			}
+		case *ast.UnionExtensionDefinition:
+			if def.Definition != nil {
+				b.addExtension(def.Definition)
+			}
+		case *ast.EnumExtensionDefinition:
+			if def.Definition != nil {
+				b.addExtension(def.Definition)
+			}
+		case *ast.InputObjectExtensionDefinition:
+			if def.Definition != nil {
+				b.addExtension(def.Definition)
+			}
+		case *ast.DirectiveDefinition:
+			b.directiveDefs = append(b.directiveDefs, def)
+		case typeDefinitionNode:
+			name := def.GetName().Value
+			if _, ok := b.typeDefs[name]; ok {
+				return newBuildError(fmt.Sprintf(`Type "%v" was defined more than once.`, name), def.GetName())
+			}
+			if b.schema != nil && b.schema.Type(name) != nil {
+				return newBuildError(fmt.Sprintf(`Type "%v" already exists in the schema. It cannot also be defined in this type definition.`, name), def.GetName())
+			}
+			if provided, ok := b.types[name]; ok && !isSameKindOfType(provided, def) {
+				return newBuildError(fmt.Sprintf(`Type "%v" is already provided and cannot be redefined as a different kind of type.`, name), def.GetName())
+			}
+			b.typeDefs[name] = def
+			b.typeDefNames = append(b.typeDefNames, name)
+		default:
+			return newBuildError(fmt.Sprintf(`A schema document cannot contain a %v.`, def.GetKind()), def)
+		}
+	}
+
+	for _, name := range b.extensionNames {
+		for _, extension := range b.extensions[name] {
+			if def, ok := b.typeDefs[name]; ok {
+				if def.GetKind() != extension.GetKind() {
+					return newBuildError(fmt.Sprintf(`Cannot extend non-%v type "%v".`, extensionKind(extension), name), extension.GetName())
+				}
+				continue
+			}
+			if b.schema == nil || b.schema.Type(name) == nil {
+				return newBuildError(fmt.Sprintf(`Cannot extend type "%v" because it is not defined.`, name), extension.GetName())
+			}
+			if !isSameKindOfType(b.schema.Type(name), extension) {
+				return newBuildError(fmt.Sprintf(`Cannot extend non-%v type "%v".`, extensionKind(extension), name), extension.GetName())
+			}
+		}
+	}
+	return nil
+}
+
+func (b *schemaBuilder) addExtension(def typeDefinitionNode) {
+	if def.GetName() == nil {
+		return
+	}
+	name := def.GetName().Value
+	if _, ok := b.extensions[name]; !ok {
+		b.extensionNames = append(b.extensionNames, name)
+	}
+	b.extensions[name] = append(b.extensions[name], def)
+}
+
+// extensionKind names the kind of type a type extension applies to.
+func extensionKind(def ast.Node) string {
+	switch def.(type) {
+	case *ast.ScalarDefinition:
+		return "scalar"
+	case *ast.InterfaceDefinition:
+		return "interface"
+	case *ast.UnionDefinition:
+		return "union"
+	case *ast.EnumDefinition:
+		return "enum"
+	case *ast.InputObjectDefinition:
+		return "input object"
+	}
+	return "object"
+}
+
+// isSameKindOfType reports whether a provided type may stand in for the given definition.
+func isSameKindOfType(ttype Type, def ast.Node) bool {
+	switch def.(type) {
+	case *ast.ScalarDefinition:
+		_, ok := ttype.(*Scalar)
+		return ok
+	case *ast.ObjectDefinition:
+		_, ok := ttype.(*Object)
+		return ok
+	case *ast.InterfaceDefinition:
+		_, ok := ttype.(*Interface)
+		return ok
+	case *ast.UnionDefinition:
+		_, ok := ttype.(*Union)
+		return ok
+	case *ast.EnumDefinition:
+		_, ok := ttype.(*Enum)
+		return ok
+	case *ast.InputObjectDefinition:
+		_, ok := ttype.(*InputObject)
+		return ok
+	}
+	return false
+}
+
+// namedType returns the type with the given name, building it from its
+// definition if needed, or nil if the name is unknown.
+func (b *schemaBuilder) namedType(name string) Type {
+	if ttype, ok := b.types[name]; ok {
+		return ttype
+	}
+	def, ok := b.typeDefs[name]
+	if !ok {
+		if b.schema == nil || b.schema.Type(name) == nil {
+			return nil
+		}
+		ttype := b.extendType(b.schema.Type(name))
+		b.types[name] = ttype
+		return ttype
+	}
+	var ttype Type
+	switch def := def.(type) {
+	case *ast.ScalarDefinition:
+		ttype = b.buildScalar(def)
+	case *ast.ObjectDefinition:
+		ttype = b.buildObject(def)
+	case *ast.InterfaceDefinition:
+		ttype = b.buildInterface(def)
+	case *ast.UnionDefinition:
+		ttype = b.buildUnion(def)
+	case *ast.EnumDefinition:
+		ttype = b.buildEnum(def)
+	case *ast.InputObjectDefinition:
+		ttype = b.buildInputObject(def)
+	}
+	b.types[name] = ttype
+	return ttype
+}
+
+// typeRef returns the type referenced by the given type AST.
+func (b *schemaBuilder) typeRef(typeAST ast.Type) (Type, error) {
+	switch typeAST := typeAST.(type) {
+	case *ast.List:
+		inner, err := b.typeRef(typeAST.Type)
+		if err != nil {
+			return nil, err
+		}
+		return NewList(inner), nil
+	case *ast.NonNull:
+		inner, err := b.typeRef(typeAST.Type)
+		if err != nil {
+			return nil, err
+		}
+		return NewNonNull(inner), nil
+	case *ast.Named:
+		name := ""
+		if typeAST.Name != nil {
+			name = typeAST.Name.Value
+		}
+		ttype := b.namedType(name)
+		if ttype == nil {
+			return nil, newBuildError(fmt.Sprintf(`Unknown type "%v".`, name), typeAST)
+		}
+		return ttype, nil
+	}
+	return nil, fmt.Errorf("Unknown type reference: %v", typeAST)
+}
+
+// mustTypeRef returns the type referenced by an already checked type AST.
+func (b *schemaBuilder) mustTypeRef(typeAST ast.Type) Type {
+	ttype, _ := b.typeRef(typeAST)
+	return ttype
+}
+
+func (b *schemaBuilder) outputTypeRef(typeAST ast.Type, coordinate string) error {
+	ttype, err := b.typeRef(typeAST)
+	if err != nil {
+		return err
+	}
+	if !IsOutputType(ttype) {
+		return newBuildError(fmt.Sprintf(`The type of %v must be Output Type but got: %v.`, coordinate, ttype), typeAST)
+	}
+	return nil
+}
+
+func (b *schemaBuilder) inputTypeRef(typeAST ast.Type, coordinate string) error {
+	ttype, err := b.typeRef(typeAST)
+	if err != nil {
+		return err
+	}
+	if !IsInputType(ttype) {
+		return newBuildError(fmt.Sprintf(`The type of %v must be Input Type but got: %v.`, coordinate, ttype), typeAST)
+	}
+	return nil
+}
+
+// checkDefinitions ensures every type referenced by the document exists and
+// is of a kind allowed at its position, so that the lazily built fields of
+// the types never see an unknown type.
+func (b *schemaBuilder) checkDefinitions() error {
+	for _, name := range b.typeDefNames {
+		var err error
+		switch def := b.typeDefs[name].(type) {
+		case *ast.ObjectDefinition:
+			err = b.checkObject(name, b.objectInterfaces(def), b.objectFields(def), nil)
+		case *ast.InterfaceDefinition:
+			err = b.checkObject(name, b.interfaceInterfaces(def), b.interfaceFields(def), nil)
+		case *ast.UnionDefinition:
+			err = b.checkUnionMembers(name, b.unionMembers(def))
+		case *ast.EnumDefinition:
+			err = checkUniqueNames("Enum value", name, enumValueNames(b.enumValues(def)), nil)
+		case *ast.InputObjectDefinition:
+			err = b.checkInputFields(name, b.inputFields(def), nil)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err := b.checkExtensions(); err != nil {
+		return err
+	}
+	for _, def := range b.directiveDefs {
+		for _, arg := range def.Arguments {
+			if err := b.inputTypeRef(arg.Type, fmt.Sprintf("@%v(%v:)", def.Name.Value, arg.Name.Value)); err != nil {
+				return err
+			}
+		}
+		for _, location := range def.Locations {
+			if !isDirectiveLocation(location.Value) {
+				return newBuildError(fmt.Sprintf(`Unknown directive location "%v".`, location.Value), location)
+			}
+		}
+	}
+	return nil
+}
+
+// checkObject checks the interfaces and fields of an object or interface type.
+// Fields may not be defined twice nor redefine one of the existing fields.
+func (b *schemaBuilder) checkObject(name string, interfaces []*ast.Named, fields []*ast.FieldDefinition, existing map[string]bool) error {
+	for _, named := range interfaces {
+		ttype, err := b.typeRef(named)
+		if err != nil {
+			return err
+		}
+		if _, ok := ttype.(*Interface); !ok {
+			return newBuildError(fmt.Sprintf(`Type %v must only implement Interface types, it cannot implement %v.`, name, ttype), named)
+		}
+	}
+	fieldNames := []*ast.Name{}
+	for _, field := range fields {
+		fieldNames = append(fieldNames, field.Name)
+	}
+	if err := checkUniqueNames("Field", name, fieldNames, existing); err != nil {
+		return err
+	}
+	return b.checkFields(name, fields)
+}
+
+func (b *schemaBuilder) checkFields(typeName string, fields []*ast.FieldDefinition) error {
+	for _, field := range fields {
+		coordinate := typeName + "." + field.Name.Value
+		if err := b.outputTypeRef(field.Type, coordinate); err != nil {
+			return err
+		}
+		for _, arg := range field.Arguments {
+			if err := b.inputTypeRef(arg.Type, fmt.Sprintf("%v(%v:)", coordinate, arg.Name.Value)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (b *schemaBuilder) checkUnionMembers(name string, members []*ast.Named) error {
+	for _, member := range members {
+		ttype, err := b.typeRef(member)
+		if err != nil {
+			return err
+		}
+		if _, ok := ttype.(*Object); !ok {
+			return newBuildError(fmt.Sprintf(`Union type %v can only include Object types, it cannot include %v.`, name, ttype), member)
+		}
+	}
+	return nil
+}
+
+func (b *schemaBuilder) checkInputFields(name string, fields []*ast.InputValueDefinition, existing map[string]bool) error {
+	fieldNames := []*ast.Name{}
+	for _, field := range fields {
+		fieldNames = append(fieldNames, field.Name)
+	}
+	if err := checkUniqueNames("Field", name, fieldNames, existing); err != nil {
+		return err
+	}
+	for _, field := range fields {
+		if err := b.inputTypeRef(field.Type, name+"."+field.Name.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkUniqueNames ensures that each of the field or enum value names of a
+// type is defined once and that none of them is one of the existing names of
+// a type being extended.
+func checkUniqueNames(what string, typeName string, names []*ast.Name, existing map[string]bool) error {
+	seen := map[string]bool{}
+	for _, name := range names {
+		if existing[name.Value] {
+			return newBuildError(fmt.Sprintf(`%v "%v.%v" already exists in the schema. It cannot also be defined in this type extension.`, what, typeName, name.Value), name)
+		}
+		if seen[name.Value] {
+			return newBuildError(fmt.Sprintf(`%v "%v.%v" can only be defined once.`, what, typeName, name.Value), name)
+		}
+		seen[name.Value] = true
+	}
+	return nil
+}
+
+func enumValueNames(values []*ast.EnumValueDefinition) []*ast.Name {
+	names := []*ast.Name{}
+	for _, value := range values {
+		names = append(names, value.Name)
+	}
+	return names
+}
+
+// objectInterfaces returns the interfaces of an object definition and its extensions.
+func (b *schemaBuilder) objectInterfaces(def *ast.ObjectDefinition) []*ast.Named {
+	return append(append([]*ast.Named{}, def.Interfaces...), b.extensionInterfaces(def.Name.Value)...)
+}
+
+// objectFields returns the fields of an object definition and its extensions.
+func (b *schemaBuilder) objectFields(def *ast.ObjectDefinition) []*ast.FieldDefinition {
+	return append(append([]*ast.FieldDefinition{}, def.Fields...), b.extensionFields(def.Name.Value)...)
+}
+
+// interfaceInterfaces returns the interfaces of an interface definition and its extensions.
+func (b *schemaBuilder) interfaceInterfaces(def *ast.InterfaceDefinition) []*ast.Named {
+	return append(append([]*ast.Named{}, def.Interfaces...), b.extensionInterfaces(def.Name.Value)...)
+}
+
+// interfaceFields returns the fields of an interface definition and its extensions.
+func (b *schemaBuilder) interfaceFields(def *ast.InterfaceDefinition) []*ast.FieldDefinition {
+	return append(append([]*ast.FieldDefinition{}, def.Fields...), b.extensionFields(def.Name.Value)...)
+}
+
+// unionMembers returns the member types of a union definition and its extensions.
+func (b *schemaBuilder) unionMembers(def *ast.UnionDefinition) []*ast.Named {
+	return append(append([]*ast.Named{}, def.Types...), b.extensionUnionMembers(def.Name.Value)...)
+}
+
+// enumValues returns the values of an enum definition and its extensions.
+func (b *schemaBuilder) enumValues(def *ast.EnumDefinition) []*ast.EnumValueDefinition {
+	return append(append([]*ast.EnumValueDefinition{}, def.Values...), b.extensionEnumValues(def.Name.Value)...)
+}
+
+// inputFields returns the fields of an input object definition and its extensions.
+func (b *schemaBuilder) inputFields(def *ast.InputObjectDefinition) []*ast.InputValueDefinition {
+	return append(append([]*ast.InputValueDefinition{}, def.Fields...), b.extensionInputFields(def.Name.Value)...)
+}
+
+// extensionInterfaces returns the interfaces added to the named object or interface type by extensions.
+func (b *schemaBuilder) extensionInterfaces(name string) []*ast.Named {
+	interfaces := []*ast.Named{}
+	for _, extension := range b.extensions[name] {
+		switch extension := extension.(type) {
+		case *ast.ObjectDefinition:
+			interfaces = append(interfaces, extension.Interfaces...)
+		case *ast.InterfaceDefinition:
+			interfaces = append(interfaces, extension.Interfaces...)
+		}
+	}
+	return interfaces
+}
+
+// extensionFields returns the fields added to the named object or interface type by extensions.
+func (b *schemaBuilder) extensionFields(name string) []*ast.FieldDefinition {
+	fields := []*ast.FieldDefinition{}
+	for _, extension := range b.extensions[name] {
+		switch extension := extension.(type) {
+		case *ast.ObjectDefinition:
+			fields = append(fields, extension.Fields...)
+		case *ast.InterfaceDefinition:
+			fields = append(fields, extension.Fields...)
+		}
+	}
+	return fields
+}
+
+// extensionUnionMembers returns the member types added to the named union type by extensions.
+func (b *schemaBuilder) extensionUnionMembers(name string) []*ast.Named {
+	members := []*ast.Named{}
+	for _, extension := range b.extensions[name] {
+		if extension, ok := extension.(*ast.UnionDefinition); ok {
+			members = append(members, extension.Types...)
+		}
+	}
+	return members
+}
+
+// extensionEnumValues returns the values added to the named enum type by extensions.
+func (b *schemaBuilder) extensionEnumValues(name string) []*ast.EnumValueDefinition {
+	values := []*ast.EnumValueDefinition{}
+	for _, extension := range b.extensions[name] {
+		if extension, ok := extension.(*ast.EnumDefinition); ok {
+			values = append(values, extension.Values...)
+		}
+	}
+	return values
+}
+
+// extensionInputFields returns the fields added to the named input object type by extensions.
+func (b *schemaBuilder) extensionInputFields(name string) []*ast.InputValueDefinition {
+	fields := []*ast.InputValueDefinition{}
+	for _, extension := range b.extensions[name] {
+		if extension, ok := extension.(*ast.InputObjectDefinition); ok {
+			fields = append(fields, extension.Fields...)
+		}
+	}
+	return fields
+}
+
+func (b *schemaBuilder) buildScalar(def *ast.ScalarDefinition) *Scalar {
+	scalar := NewScalar(ScalarConfig{
+		Name:        def.Name.Value,
+		Description: descriptionValue(def),
+		Serialize: func(value interface{}) interface{} {
+			return value
+		},
+		ParseValue: func(value interface{}) interface{} {
+			return value
+		},
+		ParseLiteral: func(valueAST ast.Value) interface{} {
+			return valueFromASTUntyped(valueAST, nil)
+		},
+	})
+	b.deferDirectives(func() {
+		scalar.scalarConfig.AppliedDirectives = b.typeAppliedDirectives(def.Name.Value, def.Directives)
+	})
+	return scalar
+}
+
+func (b *schemaBuilder) buildObject(def *ast.ObjectDefinition) *Object {
+	object := NewObject(ObjectConfig{
+		Name:        def.Name.Value,
+		Description: descriptionValue(def),
+		Interfaces: InterfacesThunk(func() []*Interface {
+			return b.interfaceRefs(b.objectInterfaces(def))
+		}),
+		Fields: FieldsThunk(func() Fields {
+			return b.buildFields(b.objectFields(def))
+		}),
+	})
+	b.deferDirectives(func() {
+		object.typeConfig.AppliedDirectives = b.typeAppliedDirectives(def.Name.Value, def.Directives)
+	})
+	return object
+}
+
+// interfaceRefs returns the interfaces referenced by already checked type ASTs.
+func (b *schemaBuilder) interfaceRefs(defs []*ast.Named) []*Interface {
+	interfaces := []*Interface{}
+	for _, named := range defs {
+		if iface, ok := b.mustTypeRef(named).(*Interface); ok {
+			interfaces = append(interfaces, iface)
+		}
+	}
+	return interfaces
+}
+
+func (b *schemaBuilder) buildInterface(def *ast.InterfaceDefinition) *Interface {
+	iface := NewInterface(InterfaceConfig{
+		Name:        def.Name.Value,
+		Description: descriptionValue(def),
+		Interfaces: InterfacesThunk(func() []*Interface {
+			return b.interfaceRefs(b.interfaceInterfaces(def))
+		}),
+		Fields: FieldsThunk(func() Fields {
+			return b.buildFields(b.interfaceFields(def))
+		}),
+	})
+	iface.ResolveType = resolveTypeByName(iface)
+	b.deferDirectives(func() {
+		iface.typeConfig.AppliedDirectives = b.typeAppliedDirectives(def.Name.Value, def.Directives)
+	})
+	return iface
+}
+
+func (b *schemaBuilder) buildUnion(def *ast.UnionDefinition) *Union {
+	union := NewUnion(UnionConfig{
+		Name:        def.Name.Value,
+		Description: descriptionValue(def),
+		Types: UnionTypesThunk(func() []*Object {
+			types := []*Object{}
+			for _, named := range b.unionMembers(def) {
+				if object, ok := b.mustTypeRef(named).(*Object); ok {
+					types = append(types, object)
+				}
+			}
+			return types
+		}),
+	})
+	union.ResolveType = resolveTypeByName(union)
+	b.deferDirectives(func() {
+		union.typeConfig.AppliedDirectives = b.typeAppliedDirectives(def.Name.Value, def.Directives)
+	})
+	return union
+}
+
+func (b *schemaBuilder) buildEnum(def *ast.EnumDefinition) *Enum {
+	values := EnumValueConfigMap{}
+	b.addEnumValues(values, b.enumValues(def))
+	enum := NewEnum(EnumConfig{
+		Name:        def.Name.Value,
+		Description: descriptionValue(def),
+		Values:      values,
+	})
+	b.deferDirectives(func() {
+		enum.enumConfig.AppliedDirectives = b.typeAppliedDirectives(def.Name.Value, def.Directives)
+	})
+	b.deferEnumValueDirectives(enum, b.enumValues(def))
+	return enum
+}
+
+func (b *schemaBuilder) addEnumValues(values EnumValueConfigMap, defs []*ast.EnumValueDefinition) {
+	for _, value := range defs {
+		values[value.Name.Value] = &EnumValueConfig{
+			Value:             value.Name.Value,
+			Description:       descriptionValue(value),
+			DeprecationReason: deprecationReason(value.Directives),
+		}
+	}
+}
+
+// deferEnumValueDirectives sets the directives applied to the values of an
+// enum by the given value definitions once every type is built.
+func (b *schemaBuilder) deferEnumValueDirectives(enum *Enum, defs []*ast.EnumValueDefinition) {
+	b.deferDirectives(func() {
+		for _, def := range defs {
+			if value, ok := enum.getNameLookup()[def.Name.Value]; ok && len(def.Directives) > 0 {
+				value.AppliedDirectives = b.appliedDirectives(def.Directives)
+			}
+		}
+	})
+}
+
+func (b *schemaBuilder) buildInputObject(def *ast.InputObjectDefinition) *InputObject {
+	inputObject := NewInputObject(InputObjectConfig{
+		Name:        def.Name.Value,
+		Description: descriptionValue(def),
+		Fields: InputObjectConfigFieldMapThunk(func() InputObjectConfigFieldMap {
+			fields := InputObjectConfigFieldMap{}
+			b.addInputFields(fields, b.inputFields(def))
+			return fields
+		}),
+	})
+	b.deferDirectives(func() {
+		inputObject.typeConfig.AppliedDirectives = b.typeAppliedDirectives(def.Name.Value, def.Directives)
+	})
+	return inputObject
+}
+
+func (b *schemaBuilder) addInputFields(fields InputObjectConfigFieldMap, defs []*ast.InputValueDefinition) {
+	for _, field := range defs {
+		ttype := b.mustTypeRef(field.Type)
+		fields[field.Name.Value] = &InputObjectFieldConfig{
+			Type:              ttype,
+			DefaultValue:      valueFromAST(field.DefaultValue, ttype, nil),
+			Description:       descriptionValue(field),
+			AppliedDirectives: b.appliedDirectives(field.Directives),
+		}
+	}
+}
+
+func (b *schemaBuilder) buildFields(defs []*ast.FieldDefinition) Fields {
+	fields := Fields{}
+	for _, def := range defs {
+		fields[def.Name.Value] = &Field{
+			Name:              def.Name.Value,
+			Type:              b.mustTypeRef(def.Type),
+			Args:              b.buildArgs(def.Arguments),
+			Description:       descriptionValue(def),
+			DeprecationReason: deprecationReason(def.Directives),
+			AppliedDirectives: b.appliedDirectives(def.Directives),
+		}
+	}
+	return fields
+}
+
+func (b *schemaBuilder) buildArgs(defs []*ast.InputValueDefinition) FieldConfigArgument {
+	args := FieldConfigArgument{}
+	for _, def := range defs {
+		ttype := b.mustTypeRef(def.Type)
+		args[def.Name.Value] = &ArgumentConfig{
+			Type:              ttype,
+			DefaultValue:      valueFromAST(def.DefaultValue, ttype, nil),
+			Description:       descriptionValue(def),
+			AppliedDirectives: b.appliedDirectives(def.Directives),
+		}
+	}
+	return args
+}
+
+// buildDirectives returns the given directives along with the directives
+// defined by the document, which replace given directives of the same name.
+func (b *schemaBuilder) buildDirectives(base []*Directive) ([]*Directive, error) {
+	directives := append([]*Directive{}, base...)
+	defined := map[string]bool{}
+	for _, def := range b.directiveDefs {
+		name := def.Name.Value
+		if defined[name] {
+			return nil, newBuildError(fmt.Sprintf(`There can be only one directive named "%v".`, name), def.Name)
+		}
+		defined[name] = true
+
+		locations := []string{}
+		for _, location := range def.Locations {
+			locations = append(locations, location.Value)
+		}
+		directive := NewDirective(DirectiveConfig{
+			Name:         name,
+			Description:  descriptionValue(def),
+			Locations:    locations,
+			Args:         b.buildArgs(def.Arguments),
+			IsRepeatable: def.Repeatable,
+		})
+		if directive.err != nil {
+			return nil, directive.err
+		}
+
+		replaced := false
+		for i, specified := range directives {
+			if specified.Name == name {
+				directives[i] = directive
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			directives = append(directives, directive)
+		}
+	}
+	return directives, nil
+}
+
+func (b *schemaBuilder) buildRootTypes() (SchemaConfig, error) {
+	config := SchemaConfig{}
+	if b.schemaDef == nil {
+		config.Query, _ = b.namedType("Query").(*Object)
+		config.Mutation, _ = b.namedType("Mutation").(*Object)
+		config.Subscription, _ = b.namedType("Subscription").(*Object)
+	} else if err := b.addRootTypes(&config, b.schemaDef.OperationTypes); err != nil {
+		return config, err
+	}
+	for _, extension := range b.schemaExtensions {
+		if err := b.addRootTypes(&config, extension.OperationTypes); err != nil {
+			return config, err
+		}
+	}
+	if config.Query == nil {
+		if b.schemaDef != nil {
+			return config, newBuildError("Must provide schema definition with query type or a type named Query.", b.schemaDef)
+		}
+		return config, invariant(false, "Must provide schema definition with query type or a type named Query.")
+	}
+	return config, nil
+}
+
+// addRootTypes sets the root operation types of the config from the given
+// operation type definitions.
+func (b *schemaBuilder) addRootTypes(config *SchemaConfig, operationTypes []*ast.OperationTypeDefinition) error {
+	for _, operationType := range operationTypes {
+		ttype, err := b.typeRef(operationType.Type)
+		if err != nil {
+			return err
+		}
+		object, ok := ttype.(*Object)
+		if !ok {
+			return newBuildError(fmt.Sprintf(`%v root type must be Object type, it cannot be %v.`, operationType.Operation, ttype), operationType.Type)
+		}
+		var root **Object
+		switch operationType.Operation {
+		case ast.OperationTypeQuery:
+			root = &config.Query
+		case ast.OperationTypeMutation:
+			root = &config.Mutation
+		case ast.OperationTypeSubscription:
+			root = &config.Subscription
+		}
+		if *root != nil {
+			return newBuildError(fmt.Sprintf(`Must provide only one %v type in schema.`, operationType.Operation), operationType)
+		}
+		*root = object
+	}
+	return nil
+}
+
+// builtTypes returns the provided types followed by the types built from the
+// document, sorted by name to keep schema construction deterministic.
+func (b *schemaBuilder) builtTypes() []Type {
+	types := append([]Type{}, b.providedTypes...)
+	names := append([]string{}, b.typeDefNames...)
+	sort.Strings(names)
+	for _, name := range names {
+		types = append(types, b.types[name])
+	}
+	return types
+}
+
+// resolveTypeByName returns the ResolveTypeFn given to abstract types built from
+// a schema document. The runtime type is named by the `__typename` key of a map
+// value or by the Go type name of a struct value; otherwise the isTypeOf
+// functions of the possible types are consulted.
+func resolveTypeByName(abstractType Abstract) ResolveTypeFn {
+	return func(p ResolveTypeParams) *Object {
+		typeName := ""
+		if value, ok := p.Value.(map[string]interface{}); ok {
+			typeName, _ = value["__typename"].(string)
+		} else if t := reflect.TypeOf(p.Value); t != nil {
+			for t.Kind() == reflect.Ptr {
+				t = t.Elem()
+			}
+			if t.Kind() == reflect.Struct {
+				typeName = t.Name()
+			}
+		}
+		if typeName != "" {
+			if object, ok := p.Info.Schema.Type(typeName).(*Object); ok && p.Info.Schema.IsPossibleType(abstractType, object) {
+				return object
+			}
+		}
+		return defaultResolveTypeFn(p, abstractType)
+	}
+}
+
+// descriptionValue returns the description of a definition node, if any.
+func descriptionValue(node ast.DescribableNode) string {
+	if description := node.GetDescription(); description != nil {
+		return description.Value
+	}
+	return ""
+}
+
+// deferDirectives delays setting applied directives until every type is built.
+func (b *schemaBuilder) deferDirectives(f func()) {
+	b.pendingDirectives = append(b.pendingDirectives, f)
+}
+
+// applyDirectives sets the applied directives of the types built so far.
+func (b *schemaBuilder) applyDirectives() {
+	for _, f := range b.pendingDirectives {
+		f()
+	}
+	b.pendingDirectives = nil
+}
+
+// typeAppliedDirectives returns the directives applied to the named type by
+// its definition and its extensions.
+func (b *schemaBuilder) typeAppliedDirectives(name string, directives []*ast.Directive) AppliedDirectives {
+	directives = append([]*ast.Directive{}, directives...)
+	for _, extension := range b.extensions[name] {
+		directives = append(directives, definitionDirectives(extension)...)
+	}
+	return b.appliedDirectives(directives)
+}
+
+// schemaAppliedDirectives returns the directives applied to the schema by its
+// definition and its extensions.
+func (b *schemaBuilder) schemaAppliedDirectives() AppliedDirectives {
+	directives := []*ast.Directive{}
+	if b.schemaDef != nil {
+		directives = append(directives, b.schemaDef.Directives...)
+	}
+	for _, extension := range b.schemaExtensions {
+		directives = append(directives, extension.Directives...)
+	}
+	return b.appliedDirectives(directives)
+}
+
+func definitionDirectives(def ast.Node) []*ast.Directive {
+	switch def := def.(type) {
+	case *ast.ScalarDefinition:
+		return def.Directives
+	case *ast.ObjectDefinition:
+		return def.Directives
+	case *ast.InterfaceDefinition:
+		return def.Directives
+	case *ast.UnionDefinition:
+		return def.Directives
+	case *ast.EnumDefinition:
+		return def.Directives
+	case *ast.InputObjectDefinition:
+		return def.Directives
+	}
+	return nil
+}
+
+// appliedDirectives returns the given directives with their arguments coerced
+// to the types of the directive definitions. @deprecated is left out as it is
+// kept as a deprecation reason.
+func (b *schemaBuilder) appliedDirectives(directives []*ast.Directive) AppliedDirectives {
+	var applied AppliedDirectives
+	for _, directive := range directives {
+		if directive == nil || directive.Name == nil || directive.Name.Value == DeprecatedDirective.Name {
+			continue
+		}
+		name := directive.Name.Value
+		var args map[string]interface{}
+		if argDefs, ok := b.appliedDirectiveArgs(name); ok {
+			args = getArgumentValues(argDefs, directive.Arguments, nil)
+		} else {
+			args = map[string]interface{}{}
+			for _, arg := range directive.Arguments {
+				args[arg.Name.Value] = valueFromASTUntyped(arg.Value, nil)
+			}
+		}
+		applied = append(applied, &AppliedDirective{Name: name, Args: args})
+	}
+	return applied
+}
+
+// appliedDirectiveArgs returns the arguments of the named directive, which is
+// defined by the document, the schema being extended or the specification.
+func (b *schemaBuilder) appliedDirectiveArgs(name string) ([]*Argument, bool) {
+	if args, ok := b.directiveArgs[name]; ok {
+		return args, true
+	}
+	var args []*Argument
+	found := false
+	for _, def := range b.directiveDefs {
+		if def.Name.Value == name {
+			args = []*Argument{}
+			for _, arg := range def.Arguments {
+				ttype := b.mustTypeRef(arg.Type)
+				args = append(args, &Argument{
+					PrivateName:  arg.Name.Value,
+					Type:         ttype,
+					DefaultValue: valueFromAST(arg.DefaultValue, ttype, nil),
+				})
+			}
+			found = true
+			break
+		}
+	}
+	if !found {
+		var directive *Directive
+		if b.schema != nil {
+			directive = b.schema.Directive(name)
+		}
+		for _, specified := range SpecifiedDirectives {
+			if directive == nil && specified.Name == name {
+				directive = specified
+			}
+		}
+		if directive == nil {
+			return nil, false
+		}
+		args = directive.Args
+	}
+	b.directiveArgs[name] = args
+	return args, true
+}
+
+// deprecationReason returns the reason given by a @deprecated directive, if any.
+func deprecationReason(directives []*ast.Directive) string {
+	for _, directive := range directives {
+		if directive == nil || directive.Name == nil || directive.Name.Value != DeprecatedDirective.Name {
+			continue
+		}
+		reason, _ := getArgumentValues(DeprecatedDirective.Args, directive.Arguments, nil)["reason"].(string)
+		return reason
+	}
+	return ""
+}
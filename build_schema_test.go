@@ -0,0 +1,716 @@
+package graphql_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/location"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+func buildSchemaOrFail(t *testing.T, sdl string) graphql.Schema {
+	schema, err := graphql.BuildSchema(sdl, graphql.BuildSchemaOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error building schema: %v", err)
+	}
+	return schema
+}
+
+func TestBuildSchema_ExecutesAgainstRootValue(t *testing.T) {
+	schema := buildSchemaOrFail(t, `
+		type Query {
+			hello: String
+			numbers: [Int!]!
+			user: User
+		}
+
+		type User {
+			id: ID!
+			name: String
+		}
+	`)
+
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ hello numbers user { id name } }`,
+		RootObject: map[string]interface{}{
+			"hello":   "world",
+			"numbers": []interface{}{1, 2, 3},
+			"user": map[string]interface{}{
+				"id":   "1",
+				"name": "Jane",
+			},
+		},
+	})
+	expected := &graphql.Result{
+		Data: map[string]interface{}{
+			"hello":   "world",
+			"numbers": []interface{}{1, 2, 3},
+			"user": map[string]interface{}{
+				"id":   "1",
+				"name": "Jane",
+			},
+		},
+	}
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
+
+func TestBuildSchema_UsesSchemaDefinitionRootTypes(t *testing.T) {
+	schema := buildSchemaOrFail(t, `
+		schema {
+			query: Root
+			mutation: Actions
+		}
+
+		type Root {
+			value: Int
+		}
+
+		type Actions {
+			increment: Int
+		}
+	`)
+	if schema.QueryType().Name() != "Root" {
+		t.Fatalf("expected query type Root, got %v", schema.QueryType())
+	}
+	if schema.MutationType().Name() != "Actions" {
+		t.Fatalf("expected mutation type Actions, got %v", schema.MutationType())
+	}
+	if schema.SubscriptionType() != nil {
+		t.Fatalf("expected no subscription type, got %v", schema.SubscriptionType())
+	}
+}
+
+func TestBuildSchema_DescriptionsDeprecationsAndDefaults(t *testing.T) {
+	schema := buildSchemaOrFail(t, `
+		"The root"
+		type Query {
+			"A greeting"
+			greet(name: String = "you", times: Int = 1): String
+			old: String @deprecated
+			older: String @deprecated(reason: "Use new")
+			color(input: ColorInput): Color
+		}
+
+		enum Color {
+			RED
+			GREEN @deprecated(reason: "Not a color")
+		}
+
+		input ColorInput {
+			color: Color = RED
+			"How bright"
+			brightness: Float = 0.5
+		}
+	`)
+
+	query := schema.QueryType()
+	if query.PrivateDescription != "The root" {
+		t.Fatalf("unexpected type description: %q", query.PrivateDescription)
+	}
+	fields := query.Fields()
+	if fields["greet"].Description != "A greeting" {
+		t.Fatalf("unexpected field description: %q", fields["greet"].Description)
+	}
+	args := map[string]interface{}{}
+	for _, arg := range fields["greet"].Args {
+		args[arg.Name()] = arg.DefaultValue
+	}
+	if !reflect.DeepEqual(args, map[string]interface{}{"name": "you", "times": 1}) {
+		t.Fatalf("unexpected argument defaults: %v", args)
+	}
+	if fields["old"].DeprecationReason != graphql.DefaultDeprecationReason {
+		t.Fatalf("unexpected deprecation reason: %q", fields["old"].DeprecationReason)
+	}
+	if fields["older"].DeprecationReason != "Use new" {
+		t.Fatalf("unexpected deprecation reason: %q", fields["older"].DeprecationReason)
+	}
+
+	color := schema.Type("Color").(*graphql.Enum)
+	for _, value := range color.Values() {
+		if value.Name == "GREEN" && value.DeprecationReason != "Not a color" {
+			t.Fatalf("unexpected enum value deprecation reason: %q", value.DeprecationReason)
+		}
+	}
+	input := schema.Type("ColorInput").(*graphql.InputObject).Fields()
+	if input["color"].DefaultValue != "RED" || input["brightness"].DefaultValue != 0.5 {
+		t.Fatalf("unexpected input field defaults: %v, %v", input["color"].DefaultValue, input["brightness"].DefaultValue)
+	}
+	if input["brightness"].Description() != "How bright" {
+		t.Fatalf("unexpected input field description: %q", input["brightness"].Description())
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ color }`,
+		RootObject: map[string]interface{}{
+			"color": "GREEN",
+		},
+	})
+	expected := &graphql.Result{
+		Data: map[string]interface{}{
+			"color": "GREEN",
+		},
+	}
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
+
+type Photo struct {
+	URL string `json:"url"`
+}
+
+type Article struct {
+	Title string `json:"title"`
+}
+
+func TestBuildSchema_ResolvesAbstractTypes(t *testing.T) {
+	schema := buildSchemaOrFail(t, `
+		type Query {
+			nodes: [Node]
+			results: [SearchResult]
+		}
+
+		interface Node {
+			id: ID!
+		}
+
+		type Photo implements Node {
+			id: ID!
+			url: String
+		}
+
+		type Article implements Node {
+			id: ID!
+			title: String
+		}
+
+		union SearchResult = Photo | Article
+	`)
+
+	result := graphql.Do(graphql.Params{
+		Schema: schema,
+		RequestString: `{
+			nodes { __typename id }
+			results {
+				... on Photo { url }
+				... on Article { title }
+			}
+		}`,
+		RootObject: map[string]interface{}{
+			"nodes": []interface{}{
+				map[string]interface{}{"__typename": "Photo", "id": "1"},
+				map[string]interface{}{"__typename": "Article", "id": "2"},
+			},
+			"results": []interface{}{
+				&Photo{URL: "http://example.com"},
+				Article{Title: "News"},
+			},
+		},
+	})
+	expected := &graphql.Result{
+		Data: map[string]interface{}{
+			"nodes": []interface{}{
+				map[string]interface{}{"__typename": "Photo", "id": "1"},
+				map[string]interface{}{"__typename": "Article", "id": "2"},
+			},
+			"results": []interface{}{
+				map[string]interface{}{"url": "http://example.com"},
+				map[string]interface{}{"title": "News"},
+			},
+		},
+	}
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
+
+func TestBuildSchema_MergesObjectExtensions(t *testing.T) {
+	schema := buildSchemaOrFail(t, `
+		type Query {
+			a: String
+		}
+
+		extend type Query {
+			b: String
+		}
+	`)
+	fields := schema.QueryType().Fields()
+	if _, ok := fields["a"]; !ok {
+		t.Fatalf("expected field a")
+	}
+	if _, ok := fields["b"]; !ok {
+		t.Fatalf("expected field b from extension")
+	}
+}
+
+func TestBuildSchema_MergesExtensionsOfAllKinds(t *testing.T) {
+	schema := buildSchemaOrFail(t, `
+		schema {
+			query: Query
+		}
+
+		type Query {
+			node: Node
+			search: SearchResult
+			color(filter: Filter): Color
+		}
+
+		interface Node {
+			id: ID
+		}
+
+		type Photo implements Node {
+			id: ID
+			url: String
+		}
+
+		type Article {
+			title: String
+		}
+
+		union SearchResult = Photo
+
+		enum Color {
+			RED
+		}
+
+		input Filter {
+			name: String
+		}
+
+		scalar Date
+
+		type Mutation {
+			noop: Boolean
+		}
+
+		extend schema {
+			mutation: Mutation
+		}
+
+		extend interface Node {
+			url: String
+		}
+
+		extend union SearchResult = Article
+
+		extend enum Color {
+			GREEN
+		}
+
+		extend input Filter {
+			limit: Int
+		}
+
+		directive @format on SCALAR
+
+		extend scalar Date @format
+	`)
+	expected := `directive @format on SCALAR
+
+type Article {
+  title: String
+}
+
+enum Color {
+  GREEN
+  RED
+}
+
+scalar Date @format
+
+input Filter {
+  limit: Int
+  name: String
+}
+
+type Mutation {
+  noop: Boolean
+}
+
+interface Node {
+  id: ID
+  url: String
+}
+
+type Photo implements Node {
+  id: ID
+  url: String
+}
+
+type Query {
+  color(filter: Filter): Color
+  node: Node
+  search: SearchResult
+}
+
+union SearchResult = Photo | Article
+`
+	if printed := graphql.PrintSchema(schema); printed != expected {
+		t.Fatalf("Unexpected schema, Diff: %v", testutil.Diff(expected, printed))
+	}
+}
+
+func TestBuildSchema_InterfacesImplementingInterfaces(t *testing.T) {
+	sdl := `interface Entity implements Node {
+  id: ID!
+  name: String
+}
+
+type Image implements Node & Entity & Resource {
+  id: ID!
+  name: String
+  url: String
+}
+
+interface Node {
+  id: ID!
+}
+
+type Query {
+  node: Node
+}
+
+interface Resource implements Node {
+  id: ID!
+  url: String
+}
+`
+	schema := buildSchemaOrFail(t, sdl)
+	if printed := graphql.PrintSchema(schema); printed != sdl {
+		t.Fatalf("Unexpected schema, Diff: %v", testutil.Diff(sdl, printed))
+	}
+	clientSchema, err := graphql.BuildClientSchema(introspectionOf(t, schema))
+	if err != nil {
+		t.Fatalf("unexpected error building client schema: %v", err)
+	}
+	if printed := graphql.PrintSchema(clientSchema); printed != sdl {
+		t.Fatalf("Unexpected client schema, Diff: %v", testutil.Diff(sdl, printed))
+	}
+
+	_, err = graphql.BuildSchema(`
+		interface Node {
+			id: ID!
+		}
+
+		interface Entity implements Node {
+			id: ID!
+		}
+
+		type Query implements Entity {
+			id: ID!
+		}
+	`, graphql.BuildSchemaOptions{})
+	expected := `Type Query must implement Node because it is implemented by Entity.`
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected error: %v, got %v", expected, err)
+	}
+}
+
+func TestBuildSchema_RepeatableDirectives(t *testing.T) {
+	sdl := `directive @tag(name: String!) repeatable on FIELD | OBJECT
+
+type Query {
+  hello: String
+}
+`
+	schema := buildSchemaOrFail(t, sdl)
+	if directive := schema.Directive("tag"); directive == nil || !directive.IsRepeatable {
+		t.Fatalf("expected @tag to be repeatable, got %v", directive)
+	}
+	if printed := graphql.PrintSchema(schema); printed != sdl {
+		t.Fatalf("Unexpected schema, Diff: %v", testutil.Diff(sdl, printed))
+	}
+	clientSchema, err := graphql.BuildClientSchema(introspectionOf(t, schema))
+	if err != nil {
+		t.Fatalf("unexpected error building client schema: %v", err)
+	}
+	if printed := graphql.PrintSchema(clientSchema); printed != sdl {
+		t.Fatalf("Unexpected client schema, Diff: %v", testutil.Diff(sdl, printed))
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ hello @tag(name: "a") @tag(name: "b") @skip(if: false) @skip(if: true) }`,
+	})
+	expected := `The directive "skip" can only be used once at this location.`
+	if len(result.Errors) != 1 || result.Errors[0].Message != expected {
+		t.Fatalf("Expected error: %v, got %v", expected, result.Errors)
+	}
+}
+
+func TestBuildSchema_CustomScalarsAndDirectives(t *testing.T) {
+	schema := buildSchemaOrFail(t, `
+		"Marks a field as cached"
+		directive @cached(ttl: Int = 60) on FIELD_DEFINITION | OBJECT
+
+		scalar JSON
+
+		type Query {
+			echo(value: JSON): JSON
+		}
+	`)
+
+	directive := schema.Directive("cached")
+	if directive == nil {
+		t.Fatalf("expected directive @cached")
+	}
+	if directive.Description != "Marks a field as cached" {
+		t.Fatalf("unexpected directive description: %q", directive.Description)
+	}
+	if !reflect.DeepEqual(directive.Locations, []string{"FIELD_DEFINITION", "OBJECT"}) {
+		t.Fatalf("unexpected directive locations: %v", directive.Locations)
+	}
+	for _, specified := range graphql.SpecifiedDirectives {
+		if schema.Directive(specified.Name) == nil {
+			t.Fatalf("expected specified directive @%v", specified.Name)
+		}
+	}
+
+	echo := schema.QueryType().Fields()["echo"]
+	echo.Resolve = func(p graphql.ResolveParams) (interface{}, error) {
+		return p.Args["value"], nil
+	}
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ echo(value: {a: [1, "two", true]}) }`,
+	})
+	expected := &graphql.Result{
+		Data: map[string]interface{}{
+			"echo": map[string]interface{}{
+				"a": []interface{}{1, "two", true},
+			},
+		},
+	}
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
+
+func TestBuildSchema_UsesProvidedTypes(t *testing.T) {
+	upper := graphql.NewScalar(graphql.ScalarConfig{
+		Name: "Upper",
+		Serialize: func(value interface{}) interface{} {
+			return "UPPER"
+		},
+	})
+	schema, err := graphql.BuildSchema(`
+		scalar Upper
+
+		type Query {
+			value: Upper
+		}
+	`, graphql.BuildSchemaOptions{Types: []graphql.Type{upper}})
+	if err != nil {
+		t.Fatalf("unexpected error building schema: %v", err)
+	}
+	if schema.Type("Upper") != upper {
+		t.Fatalf("expected provided scalar to be used")
+	}
+}
+
+func TestBuildSchema_ReportsErrors(t *testing.T) {
+	tests := []struct {
+		sdl      string
+		expected gqlerrors.FormattedError
+	}{
+		{
+			sdl: `type Query {
+  foo: Bar
+}`,
+			expected: gqlerrors.FormattedError{
+				Message:   `Unknown type "Bar".`,
+				Locations: []location.SourceLocation{{Line: 2, Column: 8}},
+			},
+		},
+		{
+			sdl: `type Query {
+  foo(arg: Query): String
+}`,
+			expected: gqlerrors.FormattedError{
+				Message:   `The type of Query.foo(arg:) must be Input Type but got: Query.`,
+				Locations: []location.SourceLocation{{Line: 2, Column: 12}},
+			},
+		},
+		{
+			sdl: `type Query {
+  foo: String
+}
+type Query {
+  bar: String
+}`,
+			expected: gqlerrors.FormattedError{
+				Message:   `Type "Query" was defined more than once.`,
+				Locations: []location.SourceLocation{{Line: 4, Column: 6}},
+			},
+		},
+		{
+			sdl: `type Query {
+  foo: String
+}
+union Result = Query | String`,
+			expected: gqlerrors.FormattedError{
+				Message:   `Union type Result can only include Object types, it cannot include String.`,
+				Locations: []location.SourceLocation{{Line: 4, Column: 24}},
+			},
+		},
+		{
+			sdl: `type Query {
+  foo: String
+}
+extend interface Query {
+  bar: String
+}`,
+			expected: gqlerrors.FormattedError{
+				Message:   `Cannot extend non-interface type "Query".`,
+				Locations: []location.SourceLocation{{Line: 4, Column: 18}},
+			},
+		},
+		{
+			sdl: `type Query {
+  foo: String
+}
+enum Color {
+  RED
+}
+extend enum Color {
+  RED
+}`,
+			expected: gqlerrors.FormattedError{
+				Message:   `Enum value "Color.RED" can only be defined once.`,
+				Locations: []location.SourceLocation{{Line: 8, Column: 3}},
+			},
+		},
+		{
+			sdl: `type Foo {
+  foo: String
+}`,
+			expected: gqlerrors.FormattedError{
+				Message:   `Must provide schema definition with query type or a type named Query.`,
+				Locations: []location.SourceLocation{},
+			},
+		},
+	}
+	for _, test := range tests {
+		_, err := graphql.BuildSchema(test.sdl, graphql.BuildSchemaOptions{})
+		if err == nil {
+			t.Fatalf("expected error %q", test.expected.Message)
+		}
+		formatted := gqlerrors.FormatError(err)
+		if !testutil.EqualFormattedError(test.expected, formatted) {
+			t.Fatalf("Unexpected error, Diff: %v", testutil.Diff(test.expected, formatted))
+		}
+	}
+}
+
+func TestBuildSchema_PreservesAppliedDirectives(t *testing.T) {
+	sdl := `schema @meta(tags: ["root"]) {
+  query: Query
+}
+
+directive @auth(requires: Role = USER) on OBJECT | FIELD_DEFINITION
+
+directive @meta(tags: [String]) repeatable on SCHEMA | SCALAR | OBJECT | FIELD_DEFINITION | ARGUMENT_DEFINITION | INTERFACE | UNION | ENUM | ENUM_VALUE | INPUT_OBJECT | INPUT_FIELD_DEFINITION
+
+scalar Date @meta(tags: ["scalar"])
+
+input Filter @meta(tags: ["input"]) {
+  name: String @meta(tags: ["input field"])
+}
+
+interface Node @meta(tags: ["interface"]) {
+  id: ID!
+}
+
+type Query @auth(requires: USER) {
+  node(id: ID! @meta(tags: ["argument"])): Node
+  result(filter: Filter): Result @auth(requires: ADMIN) @meta @meta(tags: ["a", "b"])
+  role: Role
+  today: Date
+}
+
+union Result @meta(tags: ["union"]) = User
+
+enum Role @meta(tags: ["enum"]) {
+  ADMIN @meta(tags: ["enum value"])
+  USER
+}
+
+type User implements Node {
+  id: ID!
+}
+`
+	schema := buildSchemaOrFail(t, sdl)
+	if printed := graphql.PrintSchema(schema); printed != sdl {
+		t.Fatalf("Unexpected schema, Diff: %v", testutil.Diff(sdl, printed))
+	}
+
+	query := schema.QueryType()
+	if auth := query.AppliedDirectives().Directive("auth"); auth == nil || auth.Args["requires"] != "USER" {
+		t.Fatalf("expected @auth(requires: USER) on Query, got %v", auth)
+	}
+	result := query.Fields()["result"]
+	if auth := result.AppliedDirectives.Directive("auth"); auth == nil || auth.Args["requires"] != "ADMIN" {
+		t.Fatalf("expected @auth(requires: ADMIN) on Query.result, got %v", auth)
+	}
+	if len(result.AppliedDirectives) != 3 {
+		t.Fatalf("expected three directives on Query.result, got %v", len(result.AppliedDirectives))
+	}
+	if meta := schema.AppliedDirectives().Directive("meta"); meta == nil ||
+		!reflect.DeepEqual(meta.Args["tags"], []interface{}{"root"}) {
+		t.Fatalf("expected @meta on the schema, got %v", meta)
+	}
+	for _, value := range schema.Type("Role").(*graphql.Enum).Values() {
+		if value.Name == "ADMIN" && value.AppliedDirectives.Directive("meta") == nil {
+			t.Fatalf("expected @meta on Role.ADMIN")
+		}
+	}
+}
+
+func TestBuildSchema_ChecksAppliedDirectives(t *testing.T) {
+	tests := []struct {
+		sdl      string
+		expected string
+	}{
+		{
+			sdl: `
+				type Query {
+					hello: String @unknown
+				}
+			`,
+			expected: `Unknown directive "@unknown" used on Query.hello.`,
+		},
+		{
+			sdl: `
+				directive @auth on FIELD_DEFINITION
+
+				type Query @auth {
+					hello: String
+				}
+			`,
+			expected: `Directive "@auth" may not be used on OBJECT, but is used on Query.`,
+		},
+		{
+			sdl: `
+				directive @auth on FIELD_DEFINITION
+
+				type Query {
+					hello: String @auth @auth
+				}
+			`,
+			expected: `Directive "@auth" can only be used once on Query.hello.`,
+		},
+	}
+	for _, test := range tests {
+		_, err := graphql.BuildSchema(test.sdl, graphql.BuildSchemaOptions{})
+		if err == nil || err.Error() != test.expected {
+			t.Fatalf("Expected error: %v, got %v", test.expected, err)
+		}
+	}
+}
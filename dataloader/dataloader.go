@@ -0,0 +1,270 @@
+// Package dataloader batches and caches the loading of values by key during
+// the execution of a GraphQL request.
+//
+// A resolver loads a value with Loader.Load, which records the key and
+// returns a thunk for the resolver to return. The executor calls the thunks
+// of a result level by level; before each level, the keys recorded by every
+// Loader are fetched with one call of its BatchFn per Loader, so the items of
+// a list are loaded together rather than one query each:
+//
+//	userLoader := dataloader.NewLoader(dataloader.LoaderConfig{
+//		BatchFn: func(ctx context.Context, keys []interface{}) []*dataloader.Result {
+//			...
+//		},
+//	})
+//
+//	Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+//		return userLoader.Load(p.Context, p.Source.(*Post).AuthorID), nil
+//	},
+//
+//	graphql.Do(graphql.Params{
+//		...
+//		Context: dataloader.NewContext(ctx),
+//	})
+//
+// Loaded values are cached for the request by the context returned by
+// NewContext.
+package dataloader
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/graphql-go/graphql"
+)
+
+// Result is the value or the error loaded for a key.
+type Result struct {
+	Data  interface{}
+	Error error
+}
+
+// BatchFn loads the values of the given keys, returning a Result for each
+// key in the same order.
+type BatchFn func(ctx context.Context, keys []interface{}) []*Result
+
+// LoaderConfig options for creating a new Loader
+type LoaderConfig struct {
+	// BatchFn loads the values of a batch of keys.
+	BatchFn BatchFn
+
+	// MaxBatchSize limits the number of keys passed to BatchFn at once when
+	// greater than zero.
+	MaxBatchSize int
+}
+
+// Loader loads values by key in batches. A Loader is usually shared by
+// every request, while the values it loads are cached per request.
+type Loader struct {
+	batchFn      BatchFn
+	maxBatchSize int
+}
+
+// NewLoader returns a new Loader.
+func NewLoader(config LoaderConfig) *Loader {
+	return &Loader{
+		batchFn:      config.BatchFn,
+		maxBatchSize: config.MaxBatchSize,
+	}
+}
+
+// Load loads the value of the given key, which must be comparable, and
+// returns a thunk returning it. The key is fetched along with the other keys
+// pending when the executor dispatches the batches of the request, or when
+// the thunk is called first.
+//
+// Keys are cached by the context of the request, see NewContext; without
+// one, every Load is a batch of its own.
+func (l *Loader) Load(ctx context.Context, key interface{}) func() (interface{}, error) {
+	batches := batchesFromContext(ctx)
+	if batches == nil {
+		batches = newBatches()
+	}
+	b := batches.batch(l)
+	e := b.load(ctx, key)
+	return func() (interface{}, error) {
+		select {
+		case <-e.done:
+		default:
+			b.dispatch()
+			<-e.done
+		}
+		return e.data, e.err
+	}
+}
+
+// Prime sets the cached value of the given key for the request, unless the
+// key was already loaded.
+func (l *Loader) Prime(ctx context.Context, key interface{}, value interface{}) {
+	if batches := batchesFromContext(ctx); batches != nil {
+		batches.batch(l).prime(key, value)
+	}
+}
+
+// Clear removes the cached value of the given key for the request, e.g.
+// after a mutation changed it.
+func (l *Loader) Clear(ctx context.Context, key interface{}) {
+	if batches := batchesFromContext(ctx); batches != nil {
+		batches.batch(l).clear(key)
+	}
+}
+
+type batchesKey struct{}
+
+// NewContext returns a copy of the given context holding the cache of the
+// Loaders for a request, which also lets the executor dispatch their pending
+// batches.
+func NewContext(ctx context.Context) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	batches := newBatches()
+	ctx = context.WithValue(ctx, batchesKey{}, batches)
+	return graphql.WithDispatcher(ctx, batches)
+}
+
+func batchesFromContext(ctx context.Context) *batches {
+	if ctx == nil {
+		return nil
+	}
+	batches, _ := ctx.Value(batchesKey{}).(*batches)
+	return batches
+}
+
+// batches holds the state of the Loaders used by a request.
+type batches struct {
+	mu      sync.Mutex
+	loaders map[*Loader]*batch
+}
+
+func newBatches() *batches {
+	return &batches{loaders: map[*Loader]*batch{}}
+}
+
+func (bs *batches) batch(l *Loader) *batch {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+	b, ok := bs.loaders[l]
+	if !ok {
+		b = &batch{loader: l, cache: map[interface{}]*entry{}}
+		bs.loaders[l] = b
+	}
+	return b
+}
+
+// Dispatch loads the keys pending for every Loader of the request.
+func (bs *batches) Dispatch() {
+	bs.mu.Lock()
+	loaders := make([]*batch, 0, len(bs.loaders))
+	for _, b := range bs.loaders {
+		loaders = append(loaders, b)
+	}
+	bs.mu.Unlock()
+	for _, b := range loaders {
+		b.dispatch()
+	}
+}
+
+// entry is the value of a key, which is loaded once done is closed.
+type entry struct {
+	data interface{}
+	err  error
+	done chan struct{}
+}
+
+// batch holds the cache and the pending keys of a Loader for a request.
+type batch struct {
+	loader *Loader
+
+	mu      sync.Mutex
+	cache   map[interface{}]*entry
+	ctx     context.Context
+	keys    []interface{}
+	entries []*entry
+}
+
+func (b *batch) load(ctx context.Context, key interface{}) *entry {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if e, ok := b.cache[key]; ok {
+		return e
+	}
+	e := &entry{done: make(chan struct{})}
+	b.cache[key] = e
+	if len(b.keys) == 0 {
+		b.ctx = ctx
+	}
+	b.keys = append(b.keys, key)
+	b.entries = append(b.entries, e)
+	return e
+}
+
+func (b *batch) prime(key interface{}, value interface{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.cache[key]; ok {
+		return
+	}
+	e := &entry{data: value, done: make(chan struct{})}
+	close(e.done)
+	b.cache[key] = e
+}
+
+func (b *batch) clear(key interface{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.cache, key)
+}
+
+// dispatch loads the pending keys, in batches of at most MaxBatchSize keys.
+func (b *batch) dispatch() {
+	b.mu.Lock()
+	ctx, keys, entries := b.ctx, b.keys, b.entries
+	b.ctx, b.keys, b.entries = nil, nil, nil
+	b.mu.Unlock()
+
+	size := b.loader.maxBatchSize
+	if size <= 0 {
+		size = len(keys)
+	}
+	for start := 0; start < len(keys); start += size {
+		end := start + size
+		if end > len(keys) {
+			end = len(keys)
+		}
+		b.run(ctx, keys[start:end], entries[start:end])
+	}
+}
+
+func (b *batch) run(ctx context.Context, keys []interface{}, entries []*entry) {
+	defer func() {
+		if r := recover(); r != nil {
+			err := fmt.Errorf("dataloader: batch function panicked: %v", r)
+			for _, e := range entries {
+				select {
+				case <-e.done:
+				default:
+					e.err = err
+					close(e.done)
+				}
+			}
+		}
+	}()
+
+	results := b.loader.batchFn(ctx, keys)
+	if len(results) != len(keys) {
+		err := fmt.Errorf("dataloader: batch function returned %v results for %v keys", len(results), len(keys))
+		for _, e := range entries {
+			e.err = err
+			close(e.done)
+		}
+		return
+	}
+	for i, e := range entries {
+		if results[i] != nil {
+			e.data, e.err = results[i].Data, results[i].Error
+		}
+		close(e.done)
+	}
+}
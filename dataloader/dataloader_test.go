@@ -0,0 +1,244 @@
+package dataloader_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/dataloader"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+type post struct {
+	ID       int
+	AuthorID int
+}
+
+type user struct {
+	ID       int
+	Name     string
+	FriendID int
+}
+
+var users = map[int]*user{
+	1: {ID: 1, Name: "Ada", FriendID: 2},
+	2: {ID: 2, Name: "Bob", FriendID: 3},
+	3: {ID: 3, Name: "Cy", FriendID: 1},
+}
+
+// batchRecorder records the keys of each call of its BatchFn.
+type batchRecorder struct {
+	mu      sync.Mutex
+	batches [][]interface{}
+}
+
+func (r *batchRecorder) batchFn(ctx context.Context, keys []interface{}) []*dataloader.Result {
+	r.mu.Lock()
+	r.batches = append(r.batches, keys)
+	r.mu.Unlock()
+	results := make([]*dataloader.Result, len(keys))
+	for i, key := range keys {
+		if user, ok := users[key.(int)]; ok {
+			results[i] = &dataloader.Result{Data: user}
+		} else {
+			results[i] = &dataloader.Result{Error: fmt.Errorf("user %v not found", key)}
+		}
+	}
+	return results
+}
+
+// sortedBatches returns the keys of each batch sorted, as the order in which
+// keys are loaded is unspecified under concurrent resolution.
+func (r *batchRecorder) sortedBatches() [][]int {
+	sorted := [][]int{}
+	for _, keys := range r.batches {
+		ints := []int{}
+		for _, key := range keys {
+			ints = append(ints, key.(int))
+		}
+		sort.Ints(ints)
+		sorted = append(sorted, ints)
+	}
+	return sorted
+}
+
+func dataloaderTestSchema(t *testing.T, loader *dataloader.Loader) graphql.Schema {
+	var userType *graphql.Object
+	userType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "User",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"name": &graphql.Field{Type: graphql.String},
+				"friend": &graphql.Field{
+					Type: userType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return loader.Load(p.Context, p.Source.(*user).FriendID), nil
+					},
+				},
+			}
+		}),
+	})
+	postType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Post",
+		Fields: graphql.Fields{
+			"id": &graphql.Field{Type: graphql.Int},
+			"author": &graphql.Field{
+				Type: userType,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return loader.Load(p.Context, p.Source.(*post).AuthorID), nil
+				},
+			},
+		},
+	})
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"posts": &graphql.Field{
+					Type: graphql.NewList(postType),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return []*post{{1, 1}, {2, 2}, {3, 1}, {4, 3}, {5, 4}}, nil
+					},
+				},
+				"user": &graphql.Field{
+					Type: userType,
+					Args: graphql.FieldConfigArgument{
+						"id": &graphql.ArgumentConfig{Type: graphql.Int},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return loader.Load(p.Context, p.Args["id"]), nil
+					},
+				},
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Error in schema %v", err.Error())
+	}
+	return schema
+}
+
+func TestLoader_BatchesTheKeysOfAListLevel(t *testing.T) {
+	recorder := &batchRecorder{}
+	loader := dataloader.NewLoader(dataloader.LoaderConfig{BatchFn: recorder.batchFn})
+	result := graphql.Do(graphql.Params{
+		Schema:        dataloaderTestSchema(t, loader),
+		RequestString: `{ posts { id author { name friend { name } } } }`,
+		Context:       dataloader.NewContext(context.Background()),
+	})
+	expected := &graphql.Result{
+		Data: map[string]interface{}{
+			"posts": []interface{}{
+				map[string]interface{}{"id": 1, "author": map[string]interface{}{"name": "Ada", "friend": map[string]interface{}{"name": "Bob"}}},
+				map[string]interface{}{"id": 2, "author": map[string]interface{}{"name": "Bob", "friend": map[string]interface{}{"name": "Cy"}}},
+				map[string]interface{}{"id": 3, "author": map[string]interface{}{"name": "Ada", "friend": map[string]interface{}{"name": "Bob"}}},
+				map[string]interface{}{"id": 4, "author": map[string]interface{}{"name": "Cy", "friend": map[string]interface{}{"name": "Ada"}}},
+				map[string]interface{}{"id": 5, "author": nil},
+			},
+		},
+	}
+	if !testutil.EqualData(expected.Data, result.Data) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected.Data, result.Data))
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Message != "user 4 not found" {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	if expected := [][]interface{}{{1, 2, 3, 4}}; !reflect.DeepEqual(expected, recorder.batches) {
+		t.Fatalf("Expected the authors to be loaded in one batch and their friends from the cache, got: %v", recorder.batches)
+	}
+}
+
+func TestLoader_BatchesConcurrentlyResolvedFields(t *testing.T) {
+	recorder := &batchRecorder{}
+	loader := dataloader.NewLoader(dataloader.LoaderConfig{BatchFn: recorder.batchFn})
+	result := graphql.Do(graphql.Params{
+		Schema:        dataloaderTestSchema(t, loader),
+		RequestString: `{ posts { author { name } } a: user(id: 1) { name } b: user(id: 2) { friend { friend { name } } } }`,
+		Context:       dataloader.NewContext(context.Background()),
+		Concurrency:   4,
+	})
+	if len(result.Errors) != 1 {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	if expected := [][]int{{1, 2, 3, 4}}; !reflect.DeepEqual(expected, recorder.sortedBatches()) {
+		t.Fatalf("Unexpected batches: %v", recorder.sortedBatches())
+	}
+}
+
+func TestLoader_LimitsTheSizeOfBatches(t *testing.T) {
+	recorder := &batchRecorder{}
+	loader := dataloader.NewLoader(dataloader.LoaderConfig{BatchFn: recorder.batchFn, MaxBatchSize: 3})
+	graphql.Do(graphql.Params{
+		Schema:        dataloaderTestSchema(t, loader),
+		RequestString: `{ posts { author { name } } }`,
+		Context:       dataloader.NewContext(context.Background()),
+	})
+	if expected := [][]interface{}{{1, 2, 3}, {4}}; !reflect.DeepEqual(expected, recorder.batches) {
+		t.Fatalf("Unexpected batches: %v", recorder.batches)
+	}
+}
+
+func TestLoader_LoadsEachKeyOnItsOwnWithoutRequestContext(t *testing.T) {
+	recorder := &batchRecorder{}
+	loader := dataloader.NewLoader(dataloader.LoaderConfig{BatchFn: recorder.batchFn})
+	result := graphql.Do(graphql.Params{
+		Schema:        dataloaderTestSchema(t, loader),
+		RequestString: `{ a: user(id: 1) { name } b: user(id: 1) { name } }`,
+		Context:       context.Background(),
+	})
+	expected := &graphql.Result{
+		Data: map[string]interface{}{
+			"a": map[string]interface{}{"name": "Ada"},
+			"b": map[string]interface{}{"name": "Ada"},
+		},
+	}
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+	if len(recorder.batches) != 2 {
+		t.Fatalf("Expected a batch per key, got: %v", recorder.batches)
+	}
+}
+
+func TestLoader_PrimesAndClearsTheCache(t *testing.T) {
+	recorder := &batchRecorder{}
+	loader := dataloader.NewLoader(dataloader.LoaderConfig{BatchFn: recorder.batchFn})
+	ctx := dataloader.NewContext(context.Background())
+
+	primed := &user{ID: 1, Name: "Primed"}
+	loader.Prime(ctx, 1, primed)
+	if value, err := loader.Load(ctx, 1)(); value != primed || err != nil {
+		t.Fatalf("Expected the primed value, got: %v, %v", value, err)
+	}
+	if len(recorder.batches) != 0 {
+		t.Fatalf("Expected no batch, got: %v", recorder.batches)
+	}
+
+	loader.Clear(ctx, 1)
+	if value, err := loader.Load(ctx, 1)(); value != users[1] || err != nil {
+		t.Fatalf("Expected the loaded value, got: %v, %v", value, err)
+	}
+	if expected := [][]interface{}{{1}}; !reflect.DeepEqual(expected, recorder.batches) {
+		t.Fatalf("Unexpected batches: %v", recorder.batches)
+	}
+}
+
+func TestLoader_ReportsBatchFunctionsReturningTooFewResults(t *testing.T) {
+	loader := dataloader.NewLoader(dataloader.LoaderConfig{
+		BatchFn: func(ctx context.Context, keys []interface{}) []*dataloader.Result {
+			return nil
+		},
+	})
+	ctx := dataloader.NewContext(context.Background())
+	thunk := loader.Load(ctx, 1)
+	loader.Load(ctx, 2)
+	expected := errors.New("dataloader: batch function returned 0 results for 2 keys")
+	if _, err := thunk(); err == nil || err.Error() != expected.Error() {
+		t.Fatalf("Expected error %v, got: %v", expected, err)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"reflect"
 	"regexp"
+	"time"
 
 	"github.com/graphql-go/graphql/language/ast"
 )
@@ -219,11 +220,12 @@ type ParseLiteralFn func(valueAST ast.Value) interface{}
 
 // ScalarConfig options for creating a new GraphQLScalar
 type ScalarConfig struct {
-	Name         string `json:"name"`
-	Description  string `json:"description"`
-	Serialize    SerializeFn
-	ParseValue   ParseValueFn
-	ParseLiteral ParseLiteralFn
+	Name              string `json:"name"`
+	Description       string `json:"description"`
+	Serialize         SerializeFn
+	ParseValue        ParseValueFn
+	ParseLiteral      ParseLiteralFn
+	AppliedDirectives AppliedDirectives `json:"appliedDirectives"`
 }
 
 // NewScalar creates a new GraphQLScalar
@@ -293,6 +295,9 @@ func (st *Scalar) Description() string {
 	return st.PrivateDescription
 
 }
+func (st *Scalar) AppliedDirectives() AppliedDirectives {
+	return st.scalarConfig.AppliedDirectives
+}
 func (st *Scalar) String() string {
 	return st.PrivateName
 }
@@ -369,11 +374,12 @@ type IsTypeOfFn func(p IsTypeOfParams) bool
 type InterfacesThunk func() []*Interface
 
 type ObjectConfig struct {
-	Name        string      `json:"name"`
-	Interfaces  interface{} `json:"interfaces"`
-	Fields      interface{} `json:"fields"`
-	IsTypeOf    IsTypeOfFn  `json:"isTypeOf"`
-	Description string      `json:"description"`
+	Name              string            `json:"name"`
+	Interfaces        interface{}       `json:"interfaces"`
+	Fields            interface{}       `json:"fields"`
+	IsTypeOf          IsTypeOfFn        `json:"isTypeOf"`
+	Description       string            `json:"description"`
+	AppliedDirectives AppliedDirectives `json:"appliedDirectives"`
 }
 
 type FieldsThunk func() Fields
@@ -421,6 +427,9 @@ func (gt *Object) Name() string {
 func (gt *Object) Description() string {
 	return ""
 }
+func (gt *Object) AppliedDirectives() AppliedDirectives {
+	return gt.typeConfig.AppliedDirectives
+}
 func (gt *Object) String() string {
 	return gt.PrivateName
 }
@@ -469,7 +478,7 @@ func (gt *Object) Error() error {
 	return gt.err
 }
 
-func defineInterfaces(ttype *Object, interfaces []*Interface) ([]*Interface, error) {
+func defineInterfaces(ttype Composite, interfaces []*Interface) ([]*Interface, error) {
 	ifaces := []*Interface{}
 
 	if len(interfaces) == 0 {
@@ -483,6 +492,13 @@ func defineInterfaces(ttype *Object, interfaces []*Interface) ([]*Interface, err
 		if err != nil {
 			return ifaces, err
 		}
+		err = invariantf(
+			iface.Name() != ttype.Name(),
+			`Type %v cannot implement itself.`, ttype,
+		)
+		if err != nil {
+			return ifaces, err
+		}
 		if iface.ResolveType != nil {
 			err = invariantf(
 				iface.ResolveType != nil,
@@ -536,6 +552,9 @@ func defineFieldMap(ttype Named, fieldMap Fields) (FieldDefinitionMap, error) {
 			Resolve:           field.Resolve,
 			Subscribe:         field.Subscribe,
 			DeprecationReason: field.DeprecationReason,
+			AppliedDirectives: field.AppliedDirectives,
+			Timeout:           field.Timeout,
+			Complexity:        field.Complexity,
 		}
 
 		fieldDef.Args = []*Argument{}
@@ -560,6 +579,7 @@ func defineFieldMap(ttype Named, fieldMap Fields) (FieldDefinitionMap, error) {
 				PrivateDescription: arg.Description,
 				Type:               arg.Type,
 				DefaultValue:       arg.DefaultValue,
+				AppliedDirectives:  arg.AppliedDirectives,
 			}
 			fieldDef.Args = append(fieldDef.Args, fieldArg)
 		}
@@ -610,25 +630,36 @@ type Field struct {
 	Subscribe         FieldResolveFn      `json:"-"`
 	DeprecationReason string              `json:"deprecationReason"`
 	Description       string              `json:"description"`
+	AppliedDirectives AppliedDirectives   `json:"appliedDirectives"`
+	// Timeout, when positive, bounds the time given to Resolve, which is
+	// abandoned with an error for the field once it has elapsed.
+	Timeout time.Duration `json:"-"`
+	// Complexity, when set, computes the cost of the field for
+	// QueryComplexityRule instead of its estimator.
+	Complexity ComplexityFn `json:"-"`
 }
 
 type FieldConfigArgument map[string]*ArgumentConfig
 
 type ArgumentConfig struct {
-	Type         Input       `json:"type"`
-	DefaultValue interface{} `json:"defaultValue"`
-	Description  string      `json:"description"`
+	Type              Input             `json:"type"`
+	DefaultValue      interface{}       `json:"defaultValue"`
+	Description       string            `json:"description"`
+	AppliedDirectives AppliedDirectives `json:"appliedDirectives"`
 }
 
 type FieldDefinitionMap map[string]*FieldDefinition
 type FieldDefinition struct {
-	Name              string         `json:"name"`
-	Description       string         `json:"description"`
-	Type              Output         `json:"type"`
-	Args              []*Argument    `json:"args"`
-	Resolve           FieldResolveFn `json:"-"`
-	Subscribe         FieldResolveFn `json:"-"`
-	DeprecationReason string         `json:"deprecationReason"`
+	Name              string            `json:"name"`
+	Description       string            `json:"description"`
+	Type              Output            `json:"type"`
+	Args              []*Argument       `json:"args"`
+	Resolve           FieldResolveFn    `json:"-"`
+	Subscribe         FieldResolveFn    `json:"-"`
+	DeprecationReason string            `json:"deprecationReason"`
+	AppliedDirectives AppliedDirectives `json:"appliedDirectives"`
+	Timeout           time.Duration     `json:"-"`
+	Complexity        ComplexityFn      `json:"-"`
 }
 
 type FieldArgument struct {
@@ -639,10 +670,11 @@ type FieldArgument struct {
 }
 
 type Argument struct {
-	PrivateName        string      `json:"name"`
-	Type               Input       `json:"type"`
-	DefaultValue       interface{} `json:"defaultValue"`
-	PrivateDescription string      `json:"description"`
+	PrivateName        string            `json:"name"`
+	Type               Input             `json:"type"`
+	DefaultValue       interface{}       `json:"defaultValue"`
+	PrivateDescription string            `json:"description"`
+	AppliedDirectives  AppliedDirectives `json:"appliedDirectives"`
 }
 
 func (st *Argument) Name() string {
@@ -681,16 +713,20 @@ type Interface struct {
 	PrivateDescription string `json:"description"`
 	ResolveType        ResolveTypeFn
 
-	typeConfig        InterfaceConfig
-	initialisedFields bool
-	fields            FieldDefinitionMap
-	err               error
+	typeConfig            InterfaceConfig
+	initialisedFields     bool
+	fields                FieldDefinitionMap
+	initialisedInterfaces bool
+	interfaces            []*Interface
+	err                   error
 }
 type InterfaceConfig struct {
-	Name        string      `json:"name"`
-	Fields      interface{} `json:"fields"`
-	ResolveType ResolveTypeFn
-	Description string `json:"description"`
+	Name              string      `json:"name"`
+	Interfaces        interface{} `json:"interfaces"`
+	Fields            interface{} `json:"fields"`
+	ResolveType       ResolveTypeFn
+	Description       string            `json:"description"`
+	AppliedDirectives AppliedDirectives `json:"appliedDirectives"`
 }
 
 // ResolveTypeParams Params for ResolveTypeFn()
@@ -763,6 +799,34 @@ func (it *Interface) Fields() (fields FieldDefinitionMap) {
 	return it.fields
 }
 
+// Interfaces returns the interfaces implemented by the interface.
+func (it *Interface) Interfaces() []*Interface {
+	if it.initialisedInterfaces {
+		return it.interfaces
+	}
+
+	var configInterfaces []*Interface
+	switch iface := it.typeConfig.Interfaces.(type) {
+	case InterfacesThunk:
+		configInterfaces = iface()
+	case []*Interface:
+		configInterfaces = iface
+	case nil:
+	default:
+		it.err = fmt.Errorf("Unknown Interface.Interfaces type: %T", it.typeConfig.Interfaces)
+		it.initialisedInterfaces = true
+		return nil
+	}
+
+	it.interfaces, it.err = defineInterfaces(it, configInterfaces)
+	it.initialisedInterfaces = true
+	return it.interfaces
+}
+
+func (it *Interface) AppliedDirectives() AppliedDirectives {
+	return it.typeConfig.AppliedDirectives
+}
+
 func (it *Interface) String() string {
 	return it.PrivateName
 }
@@ -807,10 +871,11 @@ type Union struct {
 type UnionTypesThunk func() []*Object
 
 type UnionConfig struct {
-	Name        string      `json:"name"`
-	Types       interface{} `json:"types"`
-	ResolveType ResolveTypeFn
-	Description string `json:"description"`
+	Name              string      `json:"name"`
+	Types             interface{} `json:"types"`
+	ResolveType       ResolveTypeFn
+	Description       string            `json:"description"`
+	AppliedDirectives AppliedDirectives `json:"appliedDirectives"`
 }
 
 func NewUnion(config UnionConfig) *Union {
@@ -900,6 +965,10 @@ func (ut *Union) Description() string {
 	return ut.PrivateDescription
 }
 
+func (ut *Union) AppliedDirectives() AppliedDirectives {
+	return ut.typeConfig.AppliedDirectives
+}
+
 func (ut *Union) Error() error {
 	return ut.err
 }
@@ -937,20 +1006,23 @@ type Enum struct {
 }
 type EnumValueConfigMap map[string]*EnumValueConfig
 type EnumValueConfig struct {
-	Value             interface{} `json:"value"`
-	DeprecationReason string      `json:"deprecationReason"`
-	Description       string      `json:"description"`
+	Value             interface{}       `json:"value"`
+	DeprecationReason string            `json:"deprecationReason"`
+	Description       string            `json:"description"`
+	AppliedDirectives AppliedDirectives `json:"appliedDirectives"`
 }
 type EnumConfig struct {
-	Name        string             `json:"name"`
-	Values      EnumValueConfigMap `json:"values"`
-	Description string             `json:"description"`
+	Name              string             `json:"name"`
+	Values            EnumValueConfigMap `json:"values"`
+	Description       string             `json:"description"`
+	AppliedDirectives AppliedDirectives  `json:"appliedDirectives"`
 }
 type EnumValueDefinition struct {
-	Name              string      `json:"name"`
-	Value             interface{} `json:"value"`
-	DeprecationReason string      `json:"deprecationReason"`
-	Description       string      `json:"description"`
+	Name              string            `json:"name"`
+	Value             interface{}       `json:"value"`
+	DeprecationReason string            `json:"deprecationReason"`
+	Description       string            `json:"description"`
+	AppliedDirectives AppliedDirectives `json:"appliedDirectives"`
 }
 
 func NewEnum(config EnumConfig) *Enum {
@@ -996,6 +1068,7 @@ func (gt *Enum) defineEnumValues(valueMap EnumValueConfigMap) ([]*EnumValueDefin
 			Value:             valueConfig.Value,
 			DeprecationReason: valueConfig.DeprecationReason,
 			Description:       valueConfig.Description,
+			AppliedDirectives: valueConfig.AppliedDirectives,
 		}
 		if value.Value == nil {
 			value.Value = valueName
@@ -1050,6 +1123,9 @@ func (gt *Enum) Name() string {
 func (gt *Enum) Description() string {
 	return gt.PrivateDescription
 }
+func (gt *Enum) AppliedDirectives() AppliedDirectives {
+	return gt.enumConfig.AppliedDirectives
+}
 func (gt *Enum) String() string {
 	return gt.PrivateName
 }
@@ -1107,15 +1183,17 @@ type InputObject struct {
 	err        error
 }
 type InputObjectFieldConfig struct {
-	Type         Input       `json:"type"`
-	DefaultValue interface{} `json:"defaultValue"`
-	Description  string      `json:"description"`
+	Type              Input             `json:"type"`
+	DefaultValue      interface{}       `json:"defaultValue"`
+	Description       string            `json:"description"`
+	AppliedDirectives AppliedDirectives `json:"appliedDirectives"`
 }
 type InputObjectField struct {
-	PrivateName        string      `json:"name"`
-	Type               Input       `json:"type"`
-	DefaultValue       interface{} `json:"defaultValue"`
-	PrivateDescription string      `json:"description"`
+	PrivateName        string            `json:"name"`
+	Type               Input             `json:"type"`
+	DefaultValue       interface{}       `json:"defaultValue"`
+	PrivateDescription string            `json:"description"`
+	AppliedDirectives  AppliedDirectives `json:"appliedDirectives"`
 }
 
 func (st *InputObjectField) Name() string {
@@ -1135,9 +1213,10 @@ type InputObjectConfigFieldMap map[string]*InputObjectFieldConfig
 type InputObjectFieldMap map[string]*InputObjectField
 type InputObjectConfigFieldMapThunk func() InputObjectConfigFieldMap
 type InputObjectConfig struct {
-	Name        string      `json:"name"`
-	Fields      interface{} `json:"fields"`
-	Description string      `json:"description"`
+	Name              string            `json:"name"`
+	Fields            interface{}       `json:"fields"`
+	Description       string            `json:"description"`
+	AppliedDirectives AppliedDirectives `json:"appliedDirectives"`
 }
 
 func NewInputObject(config InputObjectConfig) *InputObject {
@@ -1190,6 +1269,7 @@ func (gt *InputObject) defineFieldMap() InputObjectFieldMap {
 		field.Type = fieldConfig.Type
 		field.PrivateDescription = fieldConfig.Description
 		field.DefaultValue = fieldConfig.DefaultValue
+		field.AppliedDirectives = fieldConfig.AppliedDirectives
 		resultFieldMap[fieldName] = field
 	}
 	gt.init = true
@@ -1220,6 +1300,9 @@ func (gt *InputObject) Name() string {
 func (gt *InputObject) Description() string {
 	return gt.PrivateDescription
 }
+func (gt *InputObject) AppliedDirectives() AppliedDirectives {
+	return gt.typeConfig.AppliedDirectives
+}
 func (gt *InputObject) String() string {
 	return gt.PrivateName
 }
@@ -24,6 +24,33 @@ const (
 	DirectiveLocationInputFieldDefinition = "INPUT_FIELD_DEFINITION"
 )
 
+// directiveLocations the set of valid directive locations.
+var directiveLocations = map[string]bool{
+	DirectiveLocationQuery:                true,
+	DirectiveLocationMutation:             true,
+	DirectiveLocationSubscription:         true,
+	DirectiveLocationField:                true,
+	DirectiveLocationFragmentDefinition:   true,
+	DirectiveLocationFragmentSpread:       true,
+	DirectiveLocationInlineFragment:       true,
+	DirectiveLocationSchema:               true,
+	DirectiveLocationScalar:               true,
+	DirectiveLocationObject:               true,
+	DirectiveLocationFieldDefinition:      true,
+	DirectiveLocationArgumentDefinition:   true,
+	DirectiveLocationInterface:            true,
+	DirectiveLocationUnion:                true,
+	DirectiveLocationEnum:                 true,
+	DirectiveLocationEnumValue:            true,
+	DirectiveLocationInputObject:          true,
+	DirectiveLocationInputFieldDefinition: true,
+}
+
+// isDirectiveLocation reports whether the given name is a valid directive location.
+func isDirectiveLocation(location string) bool {
+	return directiveLocations[location]
+}
+
 // DefaultDeprecationReason Constant string used for default reason for a deprecation.
 const DefaultDeprecationReason = "No longer supported"
 
@@ -34,23 +61,61 @@ var SpecifiedDirectives = []*Directive{
 	DeprecatedDirective,
 }
 
+// IncrementalDirectives are the directives requesting the incremental
+// delivery of results, which schemas opt into by adding them to their
+// directives along with SpecifiedDirectives. They are honoured by
+// ExecuteIncremental, while Execute ignores them.
+var IncrementalDirectives = []*Directive{
+	DeferDirective,
+	StreamDirective,
+}
+
 // Directive structs are used by the GraphQL runtime as a way of modifying execution
 // behavior. Type system creators will usually not create these directly.
 type Directive struct {
-	Name        string      `json:"name"`
-	Description string      `json:"description"`
-	Locations   []string    `json:"locations"`
-	Args        []*Argument `json:"args"`
+	Name         string      `json:"name"`
+	Description  string      `json:"description"`
+	Locations    []string    `json:"locations"`
+	Args         []*Argument `json:"args"`
+	IsRepeatable bool        `json:"isRepeatable"`
+
+	// Resolve wraps the resolution of the fields on which the directive is
+	// used in an operation, directly or through a fragment, and of the fields
+	// whose definition the directive is applied to.
+	Resolve DirectiveResolveFn `json:"-"`
 
 	err error
 }
 
+// DirectiveResolveParams Params for DirectiveResolveFn()
+type DirectiveResolveParams struct {
+	// Directive is the directive being applied.
+	Directive *Directive
+
+	// Args is a map of the arguments of the directive, coerced to their types.
+	Args map[string]interface{}
+
+	// ResolveParams are the params of the field being resolved.
+	ResolveParams ResolveParams
+
+	// Next resolves the field, through the directives used inside of this one.
+	Next FieldResolveFn
+}
+
+// DirectiveResolveFn is a field middleware: it may change the params, the
+// result or the error of Next, or not call it at all.
+type DirectiveResolveFn func(p DirectiveResolveParams) (interface{}, error)
+
 // DirectiveConfig options for creating a new GraphQLDirective
 type DirectiveConfig struct {
 	Name        string              `json:"name"`
 	Description string              `json:"description"`
 	Locations   []string            `json:"locations"`
 	Args        FieldConfigArgument `json:"args"`
+	// IsRepeatable allows the directive to be used more than once at a location.
+	IsRepeatable bool `json:"isRepeatable"`
+	// Resolve makes the directive executable, see Directive.Resolve.
+	Resolve DirectiveResolveFn `json:"-"`
 }
 
 func NewDirective(config DirectiveConfig) *Directive {
@@ -82,6 +147,7 @@ func NewDirective(config DirectiveConfig) *Directive {
 			PrivateDescription: argConfig.Description,
 			Type:               argConfig.Type,
 			DefaultValue:       argConfig.DefaultValue,
+			AppliedDirectives:  argConfig.AppliedDirectives,
 		})
 	}
 
@@ -89,9 +155,32 @@ func NewDirective(config DirectiveConfig) *Directive {
 	dir.Description = config.Description
 	dir.Locations = config.Locations
 	dir.Args = args
+	dir.IsRepeatable = config.IsRepeatable
+	dir.Resolve = config.Resolve
 	return dir
 }
 
+// AppliedDirective is a directive used on an element of the schema, such as
+// @auth(requires: ADMIN) on a field definition, along with its arguments.
+type AppliedDirective struct {
+	Name string                 `json:"name"`
+	Args map[string]interface{} `json:"args"`
+}
+
+// AppliedDirectives are the directives used on an element of the schema.
+type AppliedDirectives []*AppliedDirective
+
+// Directive returns the first of the applied directives with the given name,
+// or nil if there is none.
+func (directives AppliedDirectives) Directive(name string) *AppliedDirective {
+	for _, directive := range directives {
+		if directive != nil && directive.Name == name {
+			return directive
+		}
+	}
+	return nil
+}
+
 // IncludeDirective is used to conditionally include fields or fragments.
 var IncludeDirective = NewDirective(DirectiveConfig{
 	Name: "include",
@@ -146,3 +235,53 @@ var DeprecatedDirective = NewDirective(DirectiveConfig{
 		DirectiveLocationEnumValue,
 	},
 })
+
+// DeferDirective is used to deliver the fields of fragments after the initial
+// result, see ExecuteIncremental.
+var DeferDirective = NewDirective(DirectiveConfig{
+	Name: "defer",
+	Description: "Directs the executor to deliver this fragment after the initial " +
+		"result when the `if` argument is true.",
+	Args: FieldConfigArgument{
+		"if": &ArgumentConfig{
+			Type:         NewNonNull(Boolean),
+			Description:  "Deferred when true.",
+			DefaultValue: true,
+		},
+		"label": &ArgumentConfig{
+			Type:        String,
+			Description: "Unique name identifying the payload of the fragment.",
+		},
+	},
+	Locations: []string{
+		DirectiveLocationFragmentSpread,
+		DirectiveLocationInlineFragment,
+	},
+})
+
+// StreamDirective is used to deliver the items of list fields after the
+// initial result, see ExecuteIncremental.
+var StreamDirective = NewDirective(DirectiveConfig{
+	Name: "stream",
+	Description: "Directs the executor to deliver the items of this list field " +
+		"after the initial result when the `if` argument is true.",
+	Args: FieldConfigArgument{
+		"if": &ArgumentConfig{
+			Type:         NewNonNull(Boolean),
+			Description:  "Streamed when true.",
+			DefaultValue: true,
+		},
+		"label": &ArgumentConfig{
+			Type:        String,
+			Description: "Unique name identifying the payloads of the items.",
+		},
+		"initialCount": &ArgumentConfig{
+			Type:         NewNonNull(Int),
+			Description:  "Number of items delivered in the initial result.",
+			DefaultValue: 0,
+		},
+	},
+	Locations: []string{
+		DirectiveLocationField,
+	},
+})
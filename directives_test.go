@@ -1,11 +1,15 @@
 package graphql_test
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/graphql-go/graphql"
 	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/location"
 	"github.com/graphql-go/graphql/testutil"
 )
 
@@ -514,3 +518,275 @@ func TestDirectivesWorksWithSkipAndIncludeDirectives_NoIncludeOrSkip(t *testing.
 		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
 	}
 }
+
+var uppercaseDirective = graphql.NewDirective(graphql.DirectiveConfig{
+	Name: "uppercase",
+	Locations: []string{
+		graphql.DirectiveLocationField,
+		graphql.DirectiveLocationFragmentSpread,
+		graphql.DirectiveLocationInlineFragment,
+	},
+	Resolve: func(p graphql.DirectiveResolveParams) (interface{}, error) {
+		result, err := p.Next(p.ResolveParams)
+		if s, ok := result.(string); ok {
+			return strings.ToUpper(s), err
+		}
+		return result, err
+	},
+})
+
+var suffixDirective = graphql.NewDirective(graphql.DirectiveConfig{
+	Name: "suffix",
+	Locations: []string{
+		graphql.DirectiveLocationField,
+		graphql.DirectiveLocationFragmentSpread,
+		graphql.DirectiveLocationInlineFragment,
+	},
+	Args: graphql.FieldConfigArgument{
+		"value": &graphql.ArgumentConfig{
+			Type: graphql.NewNonNull(graphql.String),
+		},
+	},
+	IsRepeatable: true,
+	Resolve: func(p graphql.DirectiveResolveParams) (interface{}, error) {
+		result, err := p.Next(p.ResolveParams)
+		return fmt.Sprintf("%v%v", result, p.Args["value"]), err
+	},
+})
+
+var authDirective = graphql.NewDirective(graphql.DirectiveConfig{
+	Name:      "auth",
+	Locations: []string{graphql.DirectiveLocationField},
+	Args: graphql.FieldConfigArgument{
+		"role": &graphql.ArgumentConfig{
+			Type: graphql.NewNonNull(graphql.String),
+		},
+	},
+	Resolve: func(p graphql.DirectiveResolveParams) (interface{}, error) {
+		if p.ResolveParams.Context.Value("role") != p.Args["role"] {
+			return nil, fmt.Errorf("%v requires the %v role", p.ResolveParams.Info.FieldName, p.Args["role"])
+		}
+		return p.Next(p.ResolveParams)
+	},
+})
+
+var executableDirectivesTestSchema, _ = graphql.NewSchema(graphql.SchemaConfig{
+	Query: graphql.NewObject(graphql.ObjectConfig{
+		Name: "TestType",
+		Fields: graphql.Fields{
+			"a": &graphql.Field{
+				Type: graphql.String,
+			},
+			"b": &graphql.Field{
+				Type: graphql.String,
+			},
+		},
+	}),
+	Directives: append([]*graphql.Directive{uppercaseDirective, suffixDirective, authDirective},
+		graphql.SpecifiedDirectives...),
+})
+
+func executeExecutableDirectivesTestQuery(t *testing.T, doc string, variables map[string]interface{}) *graphql.Result {
+	return graphql.Do(graphql.Params{
+		Schema:         executableDirectivesTestSchema,
+		RequestString:  doc,
+		RootObject:     directivesTestData,
+		VariableValues: variables,
+		Context:        context.WithValue(context.Background(), "role", "user"),
+	})
+}
+
+func TestDirectivesExecutableDirectives_WrapFieldResolution(t *testing.T) {
+	query := `{ a @uppercase, b }`
+	expected := &graphql.Result{
+		Data: map[string]interface{}{
+			"a": "A",
+			"b": "b",
+		},
+	}
+	result := executeExecutableDirectivesTestQuery(t, query, nil)
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
+
+func TestDirectivesExecutableDirectives_ReceiveCoercedArgs(t *testing.T) {
+	query := `query ($suffix: String!) { a @suffix(value: "!"), b @suffix(value: $suffix) }`
+	expected := &graphql.Result{
+		Data: map[string]interface{}{
+			"a": "a!",
+			"b": "b?",
+		},
+	}
+	result := executeExecutableDirectivesTestQuery(t, query, map[string]interface{}{"suffix": "?"})
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
+
+func TestDirectivesExecutableDirectives_AreAppliedInOrder(t *testing.T) {
+	query := `{
+		a @suffix(value: "x") @uppercase
+		b @uppercase @suffix(value: "x") @suffix(value: "y")
+	}`
+	expected := &graphql.Result{
+		Data: map[string]interface{}{
+			"a": "Ax",
+			"b": "BYX",
+		},
+	}
+	result := executeExecutableDirectivesTestQuery(t, query, nil)
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
+
+func TestDirectivesExecutableDirectives_WrapFieldsOfFragments(t *testing.T) {
+	query := `
+		query {
+			... on TestType @uppercase {
+				a
+			}
+			...Frag @suffix(value: "!")
+		}
+		fragment Frag on TestType {
+			a
+			b @suffix(value: "?")
+		}
+	`
+	expected := &graphql.Result{
+		Data: map[string]interface{}{
+			"a": "A!",
+			"b": "b?!",
+		},
+	}
+	result := executeExecutableDirectivesTestQuery(t, query, nil)
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
+
+func TestDirectivesExecutableDirectives_MayNotCallNext(t *testing.T) {
+	query := `{ a @auth(role: "admin"), b @auth(role: "user") }`
+	result := executeExecutableDirectivesTestQuery(t, query, nil)
+	expected := &graphql.Result{
+		Data: map[string]interface{}{
+			"a": nil,
+			"b": "b",
+		},
+		Errors: []gqlerrors.FormattedError{
+			{
+				Message:   "a requires the admin role",
+				Locations: []location.SourceLocation{{Line: 1, Column: 3}},
+				Path:      []interface{}{"a"},
+			},
+		},
+	}
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
+
+func TestDirectives_AppliedDirectivesMustBeDefinedForTheirLocation(t *testing.T) {
+	_, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "TestType",
+			Fields: graphql.Fields{
+				"a": &graphql.Field{
+					Type: graphql.String,
+					AppliedDirectives: graphql.AppliedDirectives{
+						{Name: "uppercase"},
+					},
+				},
+			},
+		}),
+		Directives: []*graphql.Directive{uppercaseDirective},
+	})
+	expectedErr := gqlerrors.FormatError(errors.New(`Directive "@uppercase" may not be used on FIELD_DEFINITION, but is used on TestType.a.`))
+	if !testutil.EqualFormattedError(expectedErr, gqlerrors.FormatError(err)) {
+		t.Fatalf("Expected error: %v, got %v", expectedErr, err)
+	}
+}
+
+func TestDirectivesExecutableDirectives_WrapFieldsOfAppliedDirectives(t *testing.T) {
+	requiresDirective := graphql.NewDirective(graphql.DirectiveConfig{
+		Name:      "requires",
+		Locations: []string{graphql.DirectiveLocationFieldDefinition},
+		Args: graphql.FieldConfigArgument{
+			"role": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.String),
+			},
+		},
+		Resolve: func(p graphql.DirectiveResolveParams) (interface{}, error) {
+			if p.ResolveParams.Context.Value("role") != p.Args["role"] {
+				return nil, fmt.Errorf("%v requires the %v role", p.ResolveParams.Info.FieldName, p.Args["role"])
+			}
+			return p.Next(p.ResolveParams)
+		},
+	})
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "TestType",
+			Fields: graphql.Fields{
+				"a": &graphql.Field{
+					Type: graphql.String,
+					AppliedDirectives: graphql.AppliedDirectives{
+						{Name: "requires", Args: map[string]interface{}{"role": "admin"}},
+					},
+				},
+				"b": &graphql.Field{
+					Type: graphql.String,
+				},
+			},
+		}),
+		Directives: append([]*graphql.Directive{requiresDirective, uppercaseDirective},
+			graphql.SpecifiedDirectives...),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error creating schema: %v", err)
+	}
+	field := schema.QueryType().Fields()["a"]
+	if requires := field.AppliedDirectives.Directive("requires"); requires == nil || requires.Args["role"] != "admin" {
+		t.Fatalf("expected @requires(role: \"admin\") on TestType.a, got %v", requires)
+	}
+
+	query := `{ a @uppercase, b @uppercase }`
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: query,
+		RootObject:    directivesTestData,
+		Context:       context.WithValue(context.Background(), "role", "user"),
+	})
+	expected := &graphql.Result{
+		Data: map[string]interface{}{
+			"a": nil,
+			"b": "B",
+		},
+		Errors: []gqlerrors.FormattedError{
+			{
+				Message:   "a requires the admin role",
+				Locations: []location.SourceLocation{{Line: 1, Column: 3}},
+				Path:      []interface{}{"a"},
+			},
+		},
+	}
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+
+	result = graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: query,
+		RootObject:    directivesTestData,
+		Context:       context.WithValue(context.Background(), "role", "admin"),
+	})
+	expected = &graphql.Result{
+		Data: map[string]interface{}{
+			"a": "A",
+			"b": "B",
+		},
+	}
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
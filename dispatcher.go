@@ -0,0 +1,35 @@
+package graphql
+
+import (
+	"context"
+)
+
+// Dispatcher is notified by the executor before each breadth-first pass over
+// the thunks of a result, so that batching loaders, such as those of the
+// dataloader package, may fetch the keys loaded by a whole level of fields
+// in one call.
+type Dispatcher interface {
+	// Dispatch runs the batches pending for the thunks about to be called.
+	Dispatch()
+}
+
+type dispatcherKey struct{}
+
+// WithDispatcher returns a copy of the given context in which the given
+// Dispatcher is notified by the executions it is passed to.
+func WithDispatcher(ctx context.Context, dispatcher Dispatcher) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, dispatcherKey{}, dispatcher)
+}
+
+// dispatcherFromContext returns the Dispatcher of the given context, or nil
+// if there is none.
+func dispatcherFromContext(ctx context.Context) Dispatcher {
+	if ctx == nil {
+		return nil
+	}
+	dispatcher, _ := ctx.Value(dispatcherKey{}).(Dispatcher)
+	return dispatcher
+}
@@ -0,0 +1,168 @@
+package graphql
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+// DocumentCacheKey identifies a request string parsed with some options and
+// validated with some rules against the types of a schema.
+type DocumentCacheKey struct {
+	Query  string
+	schema uint64
+
+	// rulesKey identifies the validation rules when set, which are
+	// identified by the address of the first element of the slice holding
+	// them, and by their number, otherwise.
+	rulesKey     string
+	rules        *ValidationRuleFn
+	ruleCount    int
+	parseOptions parser.ParseOptions
+}
+
+// NewDocumentCacheKey returns the key under which Do caches the document of
+// the request of the given params: its request string parsed with
+// ParseOptions and validated with ValidationRules, identified by
+// ValidationRulesKey if set, against Schema.
+func NewDocumentCacheKey(p Params) DocumentCacheKey {
+	key := DocumentCacheKey{
+		Query:        p.RequestString,
+		schema:       p.Schema.id,
+		rulesKey:     p.ValidationRulesKey,
+		parseOptions: p.ParseOptions,
+	}
+	if p.ValidationRulesKey == "" {
+		rules := p.ValidationRules
+		if len(rules) == 0 {
+			rules = SpecifiedRules
+		}
+		key.rules = &rules[0]
+		key.ruleCount = len(rules)
+	}
+	return key
+}
+
+// CachedDocument is a parsed document and the result of its validation.
+type CachedDocument struct {
+	Document         *ast.Document
+	ValidationResult ValidationResult
+
+	// operations holds the operations of the document prepared by Do.
+	mu         sync.Mutex
+	operations map[string]*PreparedOperation
+}
+
+// prepare returns the operation of the document with the given name prepared
+// for execution against the given schema, preparing it on first use.
+func (d *CachedDocument) prepare(schema Schema, operationName string) (*PreparedOperation, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if operation, ok := d.operations[operationName]; ok && operation.schema.id == schema.id {
+		return operation, nil
+	}
+	operation, err := Prepare(schema, d.Document, operationName)
+	if err != nil {
+		return nil, err
+	}
+	if d.operations == nil {
+		d.operations = map[string]*PreparedOperation{}
+	}
+	d.operations[operationName] = operation
+	return operation, nil
+}
+
+// DocumentCache stores parsed and validated documents, letting Do skip the
+// parse and the validation of requests it has already seen, and execute their
+// operations prepared, see Prepare.
+//
+// Documents are cached per schema, ParseOptions and ValidationRules of the
+// requests, see NewDocumentCacheKey. The rules are identified by the
+// ValidationRulesKey of the requests when set, and by the slice holding them
+// otherwise: requests building their rules anew, e.g. appending a rule to
+// SpecifiedRules, must set a ValidationRulesKey to share the documents, while
+// others should share the slice, which must not be modified once used.
+// Implementations must be safe for concurrent use.
+type DocumentCache interface {
+	Get(key DocumentCacheKey) (*CachedDocument, bool)
+	Add(key DocumentCacheKey, document *CachedDocument)
+}
+
+// LRUDocumentCache is a DocumentCache holding a bounded number of documents,
+// evicting the least recently used one when full.
+type LRUDocumentCache struct {
+	mu        sync.Mutex
+	documents *lru
+
+	hits   uint64
+	misses uint64
+}
+
+// NewLRUDocumentCache returns a LRUDocumentCache holding at most size
+// documents.
+func NewLRUDocumentCache(size int) *LRUDocumentCache {
+	return &LRUDocumentCache{
+		documents: newLRU(size),
+	}
+}
+
+// Get returns the document of the given key, if cached.
+func (c *LRUDocumentCache) Get(key DocumentCacheKey) (*CachedDocument, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	document, ok := c.documents.get(key)
+	if !ok {
+		atomic.AddUint64(&c.misses, 1)
+		return nil, false
+	}
+	atomic.AddUint64(&c.hits, 1)
+	return document.(*CachedDocument), true
+}
+
+// Add caches the document of the given key.
+func (c *LRUDocumentCache) Add(key DocumentCacheKey, document *CachedDocument) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.documents.add(key, document)
+}
+
+// Len returns the number of cached documents.
+func (c *LRUDocumentCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.documents.len()
+}
+
+// Hits returns the number of lookups which found a cached document.
+func (c *LRUDocumentCache) Hits() uint64 {
+	return atomic.LoadUint64(&c.hits)
+}
+
+// Misses returns the number of lookups which found no cached document.
+func (c *LRUDocumentCache) Misses() uint64 {
+	return atomic.LoadUint64(&c.misses)
+}
+
+type documentCacheHitKey struct{}
+
+// DocumentCacheHitFromContext reports whether the document of the request was
+// found in the DocumentCache of its Params, which is known from the context
+// given to the extensions after Init, and ok is false when the request used
+// no cache.
+func DocumentCacheHitFromContext(ctx context.Context) (hit bool, ok bool) {
+	if ctx == nil {
+		return false, false
+	}
+	hit, ok = ctx.Value(documentCacheHitKey{}).(bool)
+	return hit, ok
+}
+
+func withDocumentCacheHit(ctx context.Context, hit bool) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, documentCacheHitKey{}, hit)
+}
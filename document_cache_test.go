@@ -0,0 +1,235 @@
+package graphql_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+// countingRule counts the documents it validates.
+func countingRule(count *int) graphql.ValidationRuleFn {
+	return func(context *graphql.ValidationContext) *graphql.ValidationRuleInstance {
+		*count++
+		return &graphql.ValidationRuleInstance{}
+	}
+}
+
+func TestDocumentCache_SkipsTheParseAndValidationOfCachedDocuments(t *testing.T) {
+	cache := graphql.NewLRUDocumentCache(10)
+	validations := 0
+	rules := append([]graphql.ValidationRuleFn{countingRule(&validations)}, graphql.SpecifiedRules...)
+	expected := &graphql.Result{
+		Data: map[string]interface{}{
+			"hero": map[string]interface{}{
+				"name": "R2-D2",
+			},
+		},
+	}
+	for i := 0; i < 3; i++ {
+		result := graphql.Do(graphql.Params{
+			Schema:          testutil.StarWarsSchema,
+			RequestString:   `{ hero { name } }`,
+			ValidationRules: rules,
+			DocumentCache:   cache,
+		})
+		if !testutil.EqualResults(expected, result) {
+			t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+		}
+	}
+	if validations != 1 {
+		t.Fatalf("Expected the document to be validated once, got %v", validations)
+	}
+	if cache.Hits() != 2 || cache.Misses() != 1 || cache.Len() != 1 {
+		t.Fatalf("Unexpected cache stats: %v hits, %v misses, %v documents", cache.Hits(), cache.Misses(), cache.Len())
+	}
+}
+
+func TestDocumentCache_CachesValidationErrors(t *testing.T) {
+	cache := graphql.NewLRUDocumentCache(10)
+	for i := 0; i < 2; i++ {
+		result := graphql.Do(graphql.Params{
+			Schema:        testutil.StarWarsSchema,
+			RequestString: `{ hero { unknown } }`,
+			DocumentCache: cache,
+		})
+		if len(result.Errors) != 1 || result.Errors[0].Message != `Cannot query field "unknown" on type "Character".` {
+			t.Fatalf("Unexpected errors: %v", result.Errors)
+		}
+	}
+	if cache.Hits() != 1 {
+		t.Fatalf("Expected the invalid document to be cached, got %v hits", cache.Hits())
+	}
+}
+
+func TestDocumentCache_KeysDocumentsBySchema(t *testing.T) {
+	cache := graphql.NewLRUDocumentCache(10)
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"hero": &graphql.Field{Type: graphql.String},
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Error in schema %v", err.Error())
+	}
+	result := graphql.Do(graphql.Params{
+		Schema:        testutil.StarWarsSchema,
+		RequestString: `{ hero { name } }`,
+		DocumentCache: cache,
+	})
+	if result.HasErrors() {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	result = graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ hero { name } }`,
+		DocumentCache: cache,
+	})
+	if len(result.Errors) != 1 || result.Errors[0].Message != `Field "hero" of type "String" must not have a sub selection.` {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	if cache.Misses() != 2 || cache.Len() != 2 {
+		t.Fatalf("Expected a document per schema, got %v misses, %v documents", cache.Misses(), cache.Len())
+	}
+}
+
+func TestDocumentCache_KeysDocumentsByValidationRulesAndParseOptions(t *testing.T) {
+	cache := graphql.NewLRUDocumentCache(10)
+	rules := append([]graphql.ValidationRuleFn{graphql.MaxDepthRule(1)}, graphql.SpecifiedRules...)
+	result := graphql.Do(graphql.Params{
+		Schema:        testutil.StarWarsSchema,
+		RequestString: `{ hero { name } }`,
+		DocumentCache: cache,
+	})
+	if result.HasErrors() {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	for i := 0; i < 2; i++ {
+		result = graphql.Do(graphql.Params{
+			Schema:          testutil.StarWarsSchema,
+			RequestString:   `{ hero { name } }`,
+			ValidationRules: rules,
+			DocumentCache:   cache,
+		})
+		if len(result.Errors) != 1 || result.Errors[0].Message != graphql.MaxDepthMessage("name", 1) {
+			t.Fatalf("Unexpected errors: %v", result.Errors)
+		}
+	}
+	result = graphql.Do(graphql.Params{
+		Schema:        testutil.StarWarsSchema,
+		RequestString: `{ hero { name } }`,
+		ParseOptions:  parser.ParseOptions{MaxDepth: 1},
+		DocumentCache: cache,
+	})
+	if len(result.Errors) != 1 || !strings.HasPrefix(result.Errors[0].Message, "Syntax Error") {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	if cache.Hits() != 1 || cache.Misses() != 3 {
+		t.Fatalf("Expected a document per rules and options, got %v hits, %v misses", cache.Hits(), cache.Misses())
+	}
+}
+
+func TestDocumentCache_KeysDocumentsByTheKeyOfValidationRules(t *testing.T) {
+	cache := graphql.NewLRUDocumentCache(10)
+	for i := 0; i < 2; i++ {
+		for _, depth := range []int{1, 2} {
+			// the rules are built anew by each request
+			result := graphql.Do(graphql.Params{
+				Schema:             testutil.StarWarsSchema,
+				RequestString:      `{ hero { name } }`,
+				ValidationRules:    append([]graphql.ValidationRuleFn{graphql.MaxDepthRule(depth)}, graphql.SpecifiedRules...),
+				ValidationRulesKey: fmt.Sprintf("maxDepth%v", depth),
+				DocumentCache:      cache,
+			})
+			if depth == 1 && (len(result.Errors) != 1 || result.Errors[0].Message != graphql.MaxDepthMessage("name", 1)) {
+				t.Fatalf("Unexpected errors: %v", result.Errors)
+			}
+			if depth == 2 && result.HasErrors() {
+				t.Fatalf("Unexpected errors: %v", result.Errors)
+			}
+		}
+	}
+	if cache.Hits() != 2 || cache.Misses() != 2 {
+		t.Fatalf("Expected a document per rules key, got %v hits, %v misses", cache.Hits(), cache.Misses())
+	}
+	key := graphql.NewDocumentCacheKey(graphql.Params{
+		Schema:             testutil.StarWarsSchema,
+		RequestString:      `{ hero { name } }`,
+		ValidationRulesKey: "maxDepth1",
+	})
+	if _, ok := cache.Get(key); !ok {
+		t.Fatalf("Expected the document to be cached under the key of its params")
+	}
+}
+
+func TestDocumentCache_NotifiesExtensionsOfCachedDocuments(t *testing.T) {
+	cache := graphql.NewLRUDocumentCache(10)
+	schema := testutil.StarWarsSchema
+	ext := newtestExt("cacheExt")
+	hits := []bool{}
+	validated := 0
+	ext.parseDidStartFn = func(ctx context.Context) (context.Context, graphql.ParseFinishFunc) {
+		hit, ok := graphql.DocumentCacheHitFromContext(ctx)
+		if !ok {
+			t.Fatalf("Expected the cache lookup in the context")
+		}
+		hits = append(hits, hit)
+		return ctx, func(err error) {}
+	}
+	ext.validationDidStartFn = func(ctx context.Context) (context.Context, graphql.ValidationFinishFunc) {
+		return ctx, func([]gqlerrors.FormattedError) {
+			validated++
+		}
+	}
+	schema.AddExtensions(ext)
+	for i := 0; i < 2; i++ {
+		result := graphql.Do(graphql.Params{
+			Schema:        schema,
+			RequestString: `{ hero { name } }`,
+			DocumentCache: cache,
+		})
+		if result.HasErrors() {
+			t.Fatalf("Unexpected errors: %v", result.Errors)
+		}
+	}
+	if len(hits) != 2 || hits[0] || !hits[1] || validated != 2 {
+		t.Fatalf("Unexpected notifications: %v hits, %v validations", hits, validated)
+	}
+}
+
+func TestLRUDocumentCache_EvictsTheLeastRecentlyUsedDocument(t *testing.T) {
+	cache := graphql.NewLRUDocumentCache(2)
+	schema := testutil.StarWarsSchema
+	a := graphql.NewDocumentCacheKey(graphql.Params{Schema: schema, RequestString: "a"})
+	b := graphql.NewDocumentCacheKey(graphql.Params{Schema: schema, RequestString: "b"})
+	c := graphql.NewDocumentCacheKey(graphql.Params{Schema: schema, RequestString: "c"})
+	document := &graphql.CachedDocument{Document: &ast.Document{}}
+
+	cache.Add(a, document)
+	cache.Add(b, document)
+	if _, ok := cache.Get(a); !ok {
+		t.Fatalf("Expected a to be cached")
+	}
+	cache.Add(c, document)
+	if _, ok := cache.Get(b); ok {
+		t.Fatalf("Expected b to be evicted")
+	}
+	if _, ok := cache.Get(a); !ok {
+		t.Fatalf("Expected a to be cached")
+	}
+	if _, ok := cache.Get(c); !ok {
+		t.Fatalf("Expected c to be cached")
+	}
+	if cache.Len() != 2 {
+		t.Fatalf("Expected 2 documents, got %v", cache.Len())
+	}
+}
@@ -5,8 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
-	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/graphql-go/graphql/gqlerrors"
 	"github.com/graphql-go/graphql/language/ast"
@@ -21,14 +22,38 @@ type ExecuteParams struct {
 
 	// Context may be provided to pass application-specific per-request
 	// information to resolve functions.
+	//
+	// Once the context is done, no further field or thunk is resolved: the
+	// result holds the data resolved so far along with the error of the
+	// context, located at the first field cut. Resolvers are expected to
+	// return once their context is done, as only those of fields with a
+	// Timeout are abandoned.
 	Context context.Context
+
+	// Concurrency opts into resolving sibling fields and list items
+	// concurrently, on at most Concurrency goroutines, when greater than one.
+	// The root fields of mutations are still resolved serially.
+	Concurrency int
+
+	// PreparedOperation, when set, is the operation to execute, see Prepare,
+	// in which case AST and OperationName are ignored. It must have been
+	// prepared against Schema, or a copy of it.
+	PreparedOperation *PreparedOperation
+
+	// UnorderedResults opts out of building the objects of the result,
+	// including its Data, as *OrderedMap values, which keep their fields in the
+	// order they were requested in, notably when marshalled to JSON, for
+	// map[string]interface{} ones as before.
+	UnorderedResults bool
+
+	// incremental is set by ExecuteIncremental.
+	incremental *incrementalExecution
 }
 
 func Execute(p ExecuteParams) (result *Result) {
 	// Use background context if no context was provided
-	ctx := p.Context
-	if ctx == nil {
-		ctx = context.Background()
+	if p.Context == nil {
+		p.Context = context.Background()
 	}
 	// run executionDidStart functions from extensions
 	extErrs, executionFinishFn := handleExtensionsExecutionDidStart(&p)
@@ -47,49 +72,45 @@ func Execute(p ExecuteParams) (result *Result) {
 		addExtensionResults(&p, result)
 	}()
 
-	resultChannel := make(chan *Result, 2)
-
-	go func() {
-		result := &Result{}
+	exeContext, err := buildExecutionContext(buildExecutionCtxParams{
+		Schema:        p.Schema,
+		Root:          p.Root,
+		AST:           p.AST,
+		OperationName: p.OperationName,
+		Args:          p.Args,
+		Context:       p.Context,
+		Concurrency:   p.Concurrency,
+		Prepared:      p.PreparedOperation,
+		Incremental:   p.incremental,
+		Ordered:       !p.UnorderedResults,
+	})
+	if err != nil {
+		return &Result{
+			Errors: gqlerrors.FormatErrors(err),
+		}
+	}
 
-		defer func() {
-			if err := recover(); err != nil {
-				result.Errors = append(result.Errors, gqlerrors.FormatError(err.(error)))
+	defer func() {
+		if r := recover(); r != nil {
+			result = &Result{}
+			if r == errFieldCut {
+				// The error of the cancellation was recorded where it occurred.
+				result.Errors = exeContext.Errors
+				return
 			}
-			resultChannel <- result
-		}()
-
-		exeContext, err := buildExecutionContext(buildExecutionCtxParams{
-			Schema:        p.Schema,
-			Root:          p.Root,
-			AST:           p.AST,
-			OperationName: p.OperationName,
-			Args:          p.Args,
-			Result:        result,
-			Context:       p.Context,
-		})
-
-		if err != nil {
-			result.Errors = append(result.Errors, gqlerrors.FormatError(err.(error)))
-			resultChannel <- result
-			return
+			err, ok := r.(error)
+			if !ok {
+				err = fmt.Errorf("%v", r)
+			}
+			result.Errors = append(result.Errors, gqlerrors.FormatError(err))
 		}
-
-		resultChannel <- executeOperation(executeOperationParams{
-			ExecutionContext: exeContext,
-			Root:             p.Root,
-			Operation:        exeContext.Operation,
-		})
 	}()
 
-	select {
-	case <-ctx.Done():
-		result := &Result{}
-		result.Errors = append(result.Errors, gqlerrors.FormatError(ctx.Err()))
-		return result
-	case r := <-resultChannel:
-		return r
-	}
+	return executeOperation(executeOperationParams{
+		ExecutionContext: exeContext,
+		Root:             p.Root,
+		Operation:        exeContext.Operation,
+	})
 }
 
 type buildExecutionCtxParams struct {
@@ -98,8 +119,11 @@ type buildExecutionCtxParams struct {
 	AST           *ast.Document
 	OperationName string
 	Args          map[string]interface{}
-	Result        *Result
 	Context       context.Context
+	Concurrency   int
+	Prepared      *PreparedOperation
+	Incremental   *incrementalExecution
+	Ordered       bool
 }
 
 type executionContext struct {
@@ -110,20 +134,119 @@ type executionContext struct {
 	VariableValues map[string]interface{}
 	Errors         []gqlerrors.FormattedError
 	Context        context.Context
+
+	// hasDirectiveResolvers is set when a directive of the schema has a
+	// Resolve function, which requires tracking the directives of fragments.
+	hasDirectiveResolvers bool
+
+	// dispatcher is notified before each breadth-first pass over thunks.
+	dispatcher Dispatcher
+
+	// ordered is set when the objects of the result are *OrderedMap values.
+	ordered bool
+
+	// workers holds a token for each goroutine resolving fields besides the
+	// executing one; it is nil unless concurrent resolution was requested.
+	workers chan struct{}
+
+	// mu guards Errors and Context, which resolvers running concurrently
+	// update.
+	mu sync.Mutex
+
+	// cut is set once a field was cut by the cancellation of the execution.
+	cut int32
+
+	// prepared caches the fields collected for the operation, if prepared.
+	prepared *PreparedOperation
+
+	// incremental holds the deferred fragments and streamed lists of
+	// incremental executions, and payload the result the fields are executed
+	// for, either the initial result or a subsequent payload.
+	incremental *incrementalExecution
+	payload     *incrementalResult
+}
+
+// addErrors records field errors of the execution.
+func (eCtx *executionContext) addErrors(errs ...gqlerrors.FormattedError) {
+	eCtx.mu.Lock()
+	defer eCtx.mu.Unlock()
+	eCtx.Errors = append(eCtx.Errors, errs...)
+}
+
+// context returns the context passed to resolvers, as updated by extensions.
+func (eCtx *executionContext) context() context.Context {
+	eCtx.mu.Lock()
+	defer eCtx.mu.Unlock()
+	return eCtx.Context
+}
+
+func (eCtx *executionContext) setContext(ctx context.Context) {
+	eCtx.mu.Lock()
+	defer eCtx.mu.Unlock()
+	eCtx.Context = ctx
 }
 
 func buildExecutionContext(p buildExecutionCtxParams) (*executionContext, error) {
 	eCtx := &executionContext{}
+	var (
+		operation *ast.OperationDefinition
+		fragments map[string]ast.Definition
+		err       error
+	)
+	if p.Prepared != nil {
+		if p.Prepared.schema.id != p.Schema.id {
+			return nil, errors.New("PreparedOperation was prepared against another schema")
+		}
+		operation, fragments = p.Prepared.operation, p.Prepared.fragments
+		eCtx.prepared = p.Prepared
+	} else if operation, fragments, err = getOperation(p.AST, p.OperationName); err != nil {
+		return nil, err
+	}
+
+	variableValues, err := getVariableValues(p.Schema, operation.GetVariableDefinitions(), p.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	eCtx.Schema = p.Schema
+	eCtx.Fragments = fragments
+	eCtx.Root = p.Root
+	eCtx.Operation = operation
+	eCtx.VariableValues = variableValues
+	eCtx.Context = p.Context
+	eCtx.dispatcher = dispatcherFromContext(p.Context)
+	eCtx.ordered = p.Ordered
+	if p.Concurrency > 1 {
+		eCtx.workers = make(chan struct{}, p.Concurrency-1)
+	}
+	for _, directive := range p.Schema.Directives() {
+		if directive.Resolve != nil {
+			eCtx.hasDirectiveResolvers = true
+			break
+		}
+	}
+	if p.Incremental != nil {
+		eCtx.incremental = p.Incremental
+		eCtx.payload = p.Incremental.initial
+		p.Incremental.eCtx = eCtx
+	}
+	return eCtx, nil
+}
+
+// getOperation returns the operation of the document with the given name,
+// which may be omitted when the document holds a single operation, and the
+// fragments of the document by name.
+func getOperation(document *ast.Document, operationName string) (*ast.OperationDefinition, map[string]ast.Definition, error) {
 	var operation *ast.OperationDefinition
 	fragments := map[string]ast.Definition{}
 
-	for _, definition := range p.AST.Definitions {
+	for _, definition := range document.Definitions {
 		switch definition := definition.(type) {
 		case *ast.OperationDefinition:
-			if (p.OperationName == "") && operation != nil {
-				return nil, errors.New("Must provide operation name if query contains multiple operations.")
+			if (operationName == "") && operation != nil {
+				return nil, nil, errors.New("Must provide operation name if query contains multiple operations.")
 			}
-			if p.OperationName == "" || definition.GetName() != nil && definition.GetName().Value == p.OperationName {
+			if operationName == "" || definition.GetName() != nil && definition.GetName().Value == operationName {
 				operation = definition
 			}
 		case *ast.FragmentDefinition:
@@ -133,29 +256,17 @@ func buildExecutionContext(p buildExecutionCtxParams) (*executionContext, error)
 			}
 			fragments[key] = definition
 		default:
-			return nil, fmt.Errorf("GraphQL cannot execute a request containing a %v", definition.GetKind())
+			return nil, nil, fmt.Errorf("GraphQL cannot execute a request containing a %v", definition.GetKind())
 		}
 	}
 
 	if operation == nil {
-		if p.OperationName != "" {
-			return nil, fmt.Errorf(`Unknown operation named "%v".`, p.OperationName)
+		if operationName != "" {
+			return nil, nil, fmt.Errorf(`Unknown operation named "%v".`, operationName)
 		}
-		return nil, fmt.Errorf(`Must provide an operation.`)
+		return nil, nil, fmt.Errorf(`Must provide an operation.`)
 	}
-
-	variableValues, err := getVariableValues(p.Schema, operation.GetVariableDefinitions(), p.Args)
-	if err != nil {
-		return nil, err
-	}
-
-	eCtx.Schema = p.Schema
-	eCtx.Fragments = fragments
-	eCtx.Root = p.Root
-	eCtx.Operation = operation
-	eCtx.VariableValues = variableValues
-	eCtx.Context = p.Context
-	return eCtx, nil
+	return operation, fragments, nil
 }
 
 type executeOperationParams struct {
@@ -165,22 +276,25 @@ type executeOperationParams struct {
 }
 
 func executeOperation(p executeOperationParams) *Result {
-	operationType, err := getOperationRootType(p.ExecutionContext.Schema, p.Operation)
-	if err != nil {
+	var (
+		operationType *Object
+		err           error
+	)
+	if prepared := p.ExecutionContext.prepared; prepared != nil {
+		operationType = prepared.rootType
+	} else if operationType, err = getOperationRootType(p.ExecutionContext.Schema, p.Operation); err != nil {
 		return &Result{Errors: gqlerrors.FormatErrors(err)}
 	}
 
-	fields := collectFields(collectFieldsParams{
-		ExeContext:   p.ExecutionContext,
-		RuntimeType:  operationType,
-		SelectionSet: p.Operation.GetSelectionSet(),
-	})
+	collected := collectSubFields(p.ExecutionContext, operationType, p.Operation.GetSelectionSet(), nil)
+	p.ExecutionContext.deferFragments(operationType, p.Root, nil, collected.deferred)
 
 	executeFieldsParams := executeFieldsParams{
-		ExecutionContext: p.ExecutionContext,
-		ParentType:       operationType,
-		Source:           p.Root,
-		Fields:           fields,
+		ExecutionContext:   p.ExecutionContext,
+		ParentType:         operationType,
+		Source:             p.Root,
+		OrderedFields:      collected.fields,
+		FragmentDirectives: collected.fragmentDirectives,
 	}
 
 	if p.Operation.GetOperation() == ast.OperationTypeMutation {
@@ -241,8 +355,14 @@ type executeFieldsParams struct {
 	ExecutionContext *executionContext
 	ParentType       *Object
 	Source           interface{}
-	Fields           map[string][]*ast.Field
 	Path             *ResponsePath
+
+	// OrderedFields are the fields to execute, in the order of the response.
+	OrderedFields []*orderedField
+
+	// FragmentDirectives holds the directives of the fragments through which
+	// the fields were collected.
+	FragmentDirectives map[*ast.Field][]*ast.Directive
 }
 
 // Implements the "Evaluating selection sets" section of the spec for "write" mode.
@@ -250,25 +370,22 @@ func executeFieldsSerially(p executeFieldsParams) *Result {
 	if p.Source == nil {
 		p.Source = map[string]interface{}{}
 	}
-	if p.Fields == nil {
-		p.Fields = map[string][]*ast.Field{}
-	}
 
-	finalResults := make(map[string]interface{}, len(p.Fields))
-	for _, orderedField := range orderedFields(p.Fields) {
+	finalResults := newResultObject(p.ExecutionContext, len(p.OrderedFields))
+	for _, orderedField := range p.OrderedFields {
 		responseName := orderedField.responseName
-		fieldASTs := orderedField.fieldASTs
 		fieldPath := p.Path.WithKey(responseName)
-		resolved, state := resolveField(p.ExecutionContext, p.ParentType, p.Source, fieldASTs, fieldPath)
+		resolved, state := resolveField(p.ExecutionContext, p.ParentType, p.Source, orderedField, p.FragmentDirectives, fieldPath)
 		if state.hasNoFieldDefs {
 			continue
 		}
-		finalResults[responseName] = resolved
+		finalResults.set(responseName, resolved)
 	}
-	dethunkMapDepthFirst(finalResults)
+	data := finalResults.value()
+	dethunkMapDepthFirst(data)
 
 	return &Result{
-		Data:   finalResults,
+		Data:   data,
 		Errors: p.ExecutionContext.Errors,
 	}
 }
@@ -277,7 +394,7 @@ func executeFieldsSerially(p executeFieldsParams) *Result {
 func executeFields(p executeFieldsParams) *Result {
 	finalResults := executeSubFields(p)
 
-	dethunkMapWithBreadthFirstTraversal(finalResults)
+	dethunkMapWithBreadthFirstTraversal(p.ExecutionContext, finalResults)
 
 	return &Result{
 		Data:   finalResults,
@@ -285,26 +402,110 @@ func executeFields(p executeFieldsParams) *Result {
 	}
 }
 
-func executeSubFields(p executeFieldsParams) map[string]interface{} {
+// executeSubFields returns the object of the executed fields, either a
+// map[string]interface{} or an *OrderedMap, whose values may still be thunks.
+func executeSubFields(p executeFieldsParams) interface{} {
 
 	if p.Source == nil {
 		p.Source = map[string]interface{}{}
 	}
-	if p.Fields == nil {
-		p.Fields = map[string][]*ast.Field{}
+	fields := p.OrderedFields
+	resolved := make([]interface{}, len(fields))
+	states := make([]resolveFieldResultState, len(fields))
+	tasks := make([]func(), len(fields))
+	for i, orderedField := range fields {
+		i, orderedField := i, orderedField
+		tasks[i] = func() {
+			fieldPath := p.Path.WithKey(orderedField.responseName)
+			resolved[i], states[i] = resolveField(p.ExecutionContext, p.ParentType, p.Source, orderedField, p.FragmentDirectives, fieldPath)
+		}
 	}
+	runTasks(p.ExecutionContext, tasks)
 
-	finalResults := make(map[string]interface{}, len(p.Fields))
-	for responseName, fieldASTs := range p.Fields {
-		fieldPath := p.Path.WithKey(responseName)
-		resolved, state := resolveField(p.ExecutionContext, p.ParentType, p.Source, fieldASTs, fieldPath)
-		if state.hasNoFieldDefs {
+	finalResults := newResultObject(p.ExecutionContext, len(fields))
+	for i, orderedField := range fields {
+		if states[i].hasNoFieldDefs {
 			continue
 		}
-		finalResults[responseName] = resolved
+		finalResults.set(orderedField.responseName, resolved[i])
+	}
+
+	return finalResults.value()
+}
+
+// resultObject is an object of the result being built, which is an
+// *OrderedMap if ordered results were requested.
+type resultObject struct {
+	fields  map[string]interface{}
+	ordered *OrderedMap
+}
+
+func newResultObject(eCtx *executionContext, size int) *resultObject {
+	if eCtx.ordered {
+		return &resultObject{ordered: NewOrderedMap()}
+	}
+	return &resultObject{fields: make(map[string]interface{}, size)}
+}
+
+func (o *resultObject) set(responseName string, value interface{}) {
+	if o.ordered != nil {
+		o.ordered.Set(responseName, value)
+		return
+	}
+	o.fields[responseName] = value
+}
+
+func (o *resultObject) value() interface{} {
+	if o.ordered != nil {
+		return o.ordered
+	}
+	return o.fields
+}
+
+// runTasks runs the given tasks and waits for them to finish. The tasks run
+// one after another unless concurrent resolution was requested, in which case
+// each task runs on a goroutine of its own while there is a free worker, and
+// on the calling goroutine otherwise so nested fields cannot starve waiting
+// for one. A panic of a task, such as a null propagated by a non-null field,
+// is raised again on the calling goroutine once every task has finished.
+func runTasks(eCtx *executionContext, tasks []func()) {
+	if eCtx.workers == nil || len(tasks) < 2 {
+		for _, task := range tasks {
+			task()
+		}
+		return
 	}
 
-	return finalResults
+	panics := make([]interface{}, len(tasks))
+	var wg sync.WaitGroup
+	for i, task := range tasks {
+		run := func(i int, task func()) {
+			defer func() {
+				panics[i] = recover()
+			}()
+			task()
+		}
+		select {
+		case eCtx.workers <- struct{}{}:
+			wg.Add(1)
+			go func(i int, task func()) {
+				defer func() {
+					<-eCtx.workers
+					wg.Done()
+				}()
+				run(i, task)
+			}(i, task)
+		default:
+			run(i, task)
+		}
+	}
+	wg.Wait()
+
+	for _, r := range panics {
+		if r != nil {
+			panic(r)
+		}
+	}
 }
 
 // dethunkQueue is a structure that allows us to execute a classic breadth-first traversal.
@@ -316,37 +517,76 @@ func (d *dethunkQueue) push(f func()) {
 	d.DethunkFuncs = append(d.DethunkFuncs, f)
 }
 
-func (d *dethunkQueue) shift() func() {
-	f := d.DethunkFuncs[0]
-	d.DethunkFuncs = d.DethunkFuncs[1:]
-	return f
-}
-
 // dethunkWithBreadthFirstTraversal performs a breadth-first descent of the map, calling any thunks
 // in the map values and replacing each thunk with that thunk's return value. This parallels
 // the reference graphql-js implementation, which calls Promise.all on thunks at each depth (which
-// is an implicit parallel descent).
-func dethunkMapWithBreadthFirstTraversal(finalResults map[string]interface{}) {
+// is an implicit parallel descent). The dispatcher of the execution, if any, is notified before
+// the thunks of each depth are called.
+func dethunkMapWithBreadthFirstTraversal(eCtx *executionContext, finalResults interface{}) {
+	dethunkWithBreadthFirstTraversal(eCtx, func(dethunkQueue *dethunkQueue) {
+		dethunkMapBreadthFirst(finalResults, dethunkQueue)
+	})
+}
+
+// dethunkListWithBreadthFirstTraversal is dethunkMapWithBreadthFirstTraversal
+// for the items of a list.
+func dethunkListWithBreadthFirstTraversal(eCtx *executionContext, list []interface{}) {
+	dethunkWithBreadthFirstTraversal(eCtx, func(dethunkQueue *dethunkQueue) {
+		dethunkListBreadthFirst(list, dethunkQueue)
+	})
+}
+
+func dethunkWithBreadthFirstTraversal(eCtx *executionContext, first func(dethunkQueue *dethunkQueue)) {
 	dethunkQueue := &dethunkQueue{DethunkFuncs: []func(){}}
-	dethunkMapBreadthFirst(finalResults, dethunkQueue)
+	dispatch(eCtx)
+	first(dethunkQueue)
 	for len(dethunkQueue.DethunkFuncs) > 0 {
-		f := dethunkQueue.shift()
-		f()
+		pass := dethunkQueue.DethunkFuncs
+		dethunkQueue.DethunkFuncs = []func(){}
+		dispatch(eCtx)
+		for _, f := range pass {
+			f()
+		}
 	}
 }
 
-func dethunkMapBreadthFirst(m map[string]interface{}, dethunkQueue *dethunkQueue) {
-	for k, v := range m {
-		if f, ok := v.(func() interface{}); ok {
-			m[k] = f()
+func dispatch(eCtx *executionContext) {
+	if eCtx.dispatcher != nil {
+		eCtx.dispatcher.Dispatch()
+	}
+}
+
+// dethunkObject calls any thunks in the values of the given object, either a
+// map[string]interface{} or an *OrderedMap, replacing each thunk with its
+// return value, and passes each value to the given function.
+func dethunkObject(object interface{}, fn func(value interface{})) {
+	switch m := object.(type) {
+	case map[string]interface{}:
+		for k, v := range m {
+			if f, ok := v.(func() interface{}); ok {
+				m[k] = f()
+			}
+			fn(m[k])
 		}
-		switch val := m[k].(type) {
-		case map[string]interface{}:
+	case *OrderedMap:
+		for _, k := range m.keys {
+			if f, ok := m.values[k].(func() interface{}); ok {
+				m.values[k] = f()
+			}
+			fn(m.values[k])
+		}
+	}
+}
+
+func dethunkMapBreadthFirst(m interface{}, dethunkQueue *dethunkQueue) {
+	dethunkObject(m, func(value interface{}) {
+		switch val := value.(type) {
+		case map[string]interface{}, *OrderedMap:
 			dethunkQueue.push(func() { dethunkMapBreadthFirst(val, dethunkQueue) })
 		case []interface{}:
 			dethunkQueue.push(func() { dethunkListBreadthFirst(val, dethunkQueue) })
 		}
-	}
+	})
 }
 
 func dethunkListBreadthFirst(list []interface{}, dethunkQueue *dethunkQueue) {
@@ -355,7 +595,7 @@ func dethunkListBreadthFirst(list []interface{}, dethunkQueue *dethunkQueue) {
 			list[i] = f()
 		}
 		switch val := list[i].(type) {
-		case map[string]interface{}:
+		case map[string]interface{}, *OrderedMap:
 			dethunkQueue.push(func() { dethunkMapBreadthFirst(val, dethunkQueue) })
 		case []interface{}:
 			dethunkQueue.push(func() { dethunkListBreadthFirst(val, dethunkQueue) })
@@ -367,18 +607,15 @@ func dethunkListBreadthFirst(list []interface{}, dethunkQueue *dethunkQueue) {
 // in the map values and replacing each thunk with that thunk's return value. This is needed
 // to conform to the graphql-js reference implementation, which requires serial (depth-first)
 // implementations for mutation selects.
-func dethunkMapDepthFirst(m map[string]interface{}) {
-	for k, v := range m {
-		if f, ok := v.(func() interface{}); ok {
-			m[k] = f()
-		}
-		switch val := m[k].(type) {
-		case map[string]interface{}:
+func dethunkMapDepthFirst(m interface{}) {
+	dethunkObject(m, func(value interface{}) {
+		switch val := value.(type) {
+		case map[string]interface{}, *OrderedMap:
 			dethunkMapDepthFirst(val)
 		case []interface{}:
 			dethunkListDepthFirst(val)
 		}
-	}
+	})
 }
 
 func dethunkListDepthFirst(list []interface{}) {
@@ -387,7 +624,7 @@ func dethunkListDepthFirst(list []interface{}) {
 			list[i] = f()
 		}
 		switch val := list[i].(type) {
-		case map[string]interface{}:
+		case map[string]interface{}, *OrderedMap:
 			dethunkMapDepthFirst(val)
 		case []interface{}:
 			dethunkListDepthFirst(val)
@@ -401,6 +638,25 @@ type collectFieldsParams struct {
 	SelectionSet         *ast.SelectionSet
 	Fields               map[string][]*ast.Field
 	VisitedFragmentNames map[string]bool
+
+	// ResponseNames, unless nil, receives the response names of the fields
+	// in the order they are first collected, which is the order of the
+	// fields of the response.
+	ResponseNames *[]string
+
+	// Directives are the directives of the fragments enclosing SelectionSet,
+	// which are recorded for each collected field in FragmentDirectives
+	// unless it is nil.
+	Directives         []*ast.Directive
+	FragmentDirectives map[*ast.Field][]*ast.Directive
+
+	// UsesVariables, unless nil, is set when the fields collected depend on
+	// the variables of the operation through @skip, @include or @defer.
+	UsesVariables *bool
+
+	// Deferred, unless nil, collects the fragments deferred by @defer in
+	// place of their fields.
+	Deferred *[]*deferredFragment
 }
 
 // Given a selectionSet, adds all of the fields in that selection to
@@ -423,26 +679,45 @@ func collectFields(p collectFieldsParams) (fields map[string][]*ast.Field) {
 	for _, iSelection := range p.SelectionSet.Selections {
 		switch selection := iSelection.(type) {
 		case *ast.Field:
+			p.noteVariables(selection.Directives)
 			if !shouldIncludeNode(p.ExeContext, selection.Directives) {
 				continue
 			}
+			if p.FragmentDirectives != nil && len(p.Directives) > 0 {
+				p.FragmentDirectives[selection] = p.Directives
+			}
 			name := getFieldEntryKey(selection)
 			if _, ok := fields[name]; !ok {
 				fields[name] = []*ast.Field{}
+				if p.ResponseNames != nil {
+					*p.ResponseNames = append(*p.ResponseNames, name)
+				}
 			}
 			fields[name] = append(fields[name], selection)
 		case *ast.InlineFragment:
-
+			p.noteVariables(selection.Directives)
 			if !shouldIncludeNode(p.ExeContext, selection.Directives) ||
 				!doesFragmentConditionMatch(p.ExeContext, selection, p.RuntimeType) {
 				continue
 			}
+			if label, ok := p.deferLabel(selection.Directives); ok {
+				*p.Deferred = append(*p.Deferred, &deferredFragment{
+					label:        label,
+					selectionSet: selection.SelectionSet,
+					directives:   p.fragmentDirectives(selection.Directives),
+				})
+				continue
+			}
 			innerParams := collectFieldsParams{
 				ExeContext:           p.ExeContext,
 				RuntimeType:          p.RuntimeType,
 				SelectionSet:         selection.SelectionSet,
 				Fields:               fields,
 				VisitedFragmentNames: p.VisitedFragmentNames,
+				ResponseNames:        p.ResponseNames,
+				Directives:           p.fragmentDirectives(selection.Directives),
+				FragmentDirectives:   p.FragmentDirectives,
+				UsesVariables:        p.UsesVariables,
 			}
 			collectFields(innerParams)
 		case *ast.FragmentSpread:
@@ -450,11 +725,16 @@ func collectFields(p collectFieldsParams) (fields map[string][]*ast.Field) {
 			if selection.Name != nil {
 				fragName = selection.Name.Value
 			}
+			p.noteVariables(selection.Directives)
 			if visited, ok := p.VisitedFragmentNames[fragName]; (ok && visited) ||
 				!shouldIncludeNode(p.ExeContext, selection.Directives) {
 				continue
 			}
-			p.VisitedFragmentNames[fragName] = true
+			// deferred fragments may be spread again in place
+			label, deferred := p.deferLabel(selection.Directives)
+			if !deferred {
+				p.VisitedFragmentNames[fragName] = true
+			}
 			fragment, hasFragment := p.ExeContext.Fragments[fragName]
 			if !hasFragment {
 				continue
@@ -464,12 +744,24 @@ func collectFields(p collectFieldsParams) (fields map[string][]*ast.Field) {
 				if !doesFragmentConditionMatch(p.ExeContext, fragment, p.RuntimeType) {
 					continue
 				}
+				if deferred {
+					*p.Deferred = append(*p.Deferred, &deferredFragment{
+						label:        label,
+						selectionSet: fragment.GetSelectionSet(),
+						directives:   p.fragmentDirectives(selection.Directives, fragment.Directives),
+					})
+					continue
+				}
 				innerParams := collectFieldsParams{
 					ExeContext:           p.ExeContext,
 					RuntimeType:          p.RuntimeType,
 					SelectionSet:         fragment.GetSelectionSet(),
 					Fields:               fields,
 					VisitedFragmentNames: p.VisitedFragmentNames,
+					ResponseNames:        p.ResponseNames,
+					Directives:           p.fragmentDirectives(selection.Directives, fragment.Directives),
+					FragmentDirectives:   p.FragmentDirectives,
+					UsesVariables:        p.UsesVariables,
 				}
 				collectFields(innerParams)
 			}
@@ -478,6 +770,38 @@ func collectFields(p collectFieldsParams) (fields map[string][]*ast.Field) {
 	return fields
 }
 
+// noteVariables sets UsesVariables if the given @skip, @include or @defer
+// directives depend on variables.
+func (p collectFieldsParams) noteVariables(directives []*ast.Directive) {
+	if p.UsesVariables != nil && inclusionUsesVariables(directives) {
+		*p.UsesVariables = true
+	}
+}
+
+// fragmentDirectives returns the directives enclosing the selection set of a
+// fragment used with the given directives, outermost first.
+func (p collectFieldsParams) fragmentDirectives(directives ...[]*ast.Directive) []*ast.Directive {
+	if p.FragmentDirectives == nil {
+		return nil
+	}
+	enclosing := p.Directives
+	for _, list := range directives {
+		if len(list) > 0 {
+			enclosing = append(append([]*ast.Directive{}, enclosing...), list...)
+		}
+	}
+	return enclosing
+}
+
+// newFragmentDirectives returns the map in which collectFields records the
+// directives of fragments, if they may change the resolution of fields.
+func newFragmentDirectives(eCtx *executionContext) map[*ast.Field][]*ast.Directive {
+	if !eCtx.hasDirectiveResolvers {
+		return nil
+	}
+	return map[*ast.Field][]*ast.Directive{}
+}
+
 // Determines if a field should be included based on the @include and @skip
 // directives, where @skip has higher precedence than @include.
 func shouldIncludeNode(eCtx *executionContext, directives []*ast.Directive) bool {
@@ -580,20 +904,81 @@ type resolveFieldResultState struct {
 	hasNoFieldDefs bool
 }
 
+// errFieldCut is raised in place of the error of the cancellation for every
+// field cut after the first one, so that the error is only reported once.
+var errFieldCut = errors.New("field cut by the cancellation of the execution")
+
+// cutField stops the resolution of a field once the execution was cancelled
+// with the given error, raising the error for the first field cut and
+// errFieldCut for the next ones; like any field error, it nulls the field,
+// or its parent if the field is non-null.
+func (eCtx *executionContext) cutField(err error) {
+	if atomic.CompareAndSwapInt32(&eCtx.cut, 0, 1) {
+		panic(err)
+	}
+	panic(errFieldCut)
+}
+
+// callWithContext calls fn on a goroutine of its own, abandoning it with the
+// error of the given context once the context is done, so that a resolver
+// ignoring its context cannot exceed the timeout of its field. The result of
+// an abandoned call is discarded.
+func callWithContext(ctx context.Context, fn func() (interface{}, error)) (interface{}, error) {
+	type outcome struct {
+		value    interface{}
+		err      error
+		panicked interface{}
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		var o outcome
+		defer func() {
+			o.panicked = recover()
+			done <- o
+		}()
+		o.value, o.err = fn()
+	}()
+	select {
+	case o := <-done:
+		if o.panicked != nil {
+			panic(o.panicked)
+		}
+		return o.value, o.err
+	case <-ctx.Done():
+		select {
+		case o := <-done:
+			// The call finished along with the context.
+			if o.panicked != nil {
+				panic(o.panicked)
+			}
+			return o.value, o.err
+		default:
+			return nil, ctx.Err()
+		}
+	}
+}
+
 func handleFieldError(r interface{}, fieldNodes []ast.Node, path *ResponsePath, returnType Output, eCtx *executionContext) {
+	if r == errFieldCut {
+		if _, ok := returnType.(*NonNull); ok {
+			panic(r)
+		}
+		return
+	}
 	err := NewLocatedErrorWithPath(r, fieldNodes, path.AsArray())
 	// send panic upstream
 	if _, ok := returnType.(*NonNull); ok {
 		panic(err)
 	}
-	eCtx.Errors = append(eCtx.Errors, gqlerrors.FormatError(err))
+	eCtx.addErrors(gqlerrors.FormatError(err))
 }
 
 // Resolves the field on the given source object. In particular, this
 // figures out the value that the field returns by calling its resolve function,
 // then calls completeValue to complete promises, serialize scalars, or execute
 // the sub-selection-set for objects.
-func resolveField(eCtx *executionContext, parentType *Object, source interface{}, fieldASTs []*ast.Field, path *ResponsePath) (result interface{}, resultState resolveFieldResultState) {
+func resolveField(eCtx *executionContext, parentType *Object, source interface{}, field *orderedField, fragmentDirectives map[*ast.Field][]*ast.Directive, path *ResponsePath) (result interface{}, resultState resolveFieldResultState) {
+	fieldASTs := field.fieldASTs
 	// catch panic from resolveFn
 	var returnType Output
 	defer func() (interface{}, resolveFieldResultState) {
@@ -610,21 +995,36 @@ func resolveField(eCtx *executionContext, parentType *Object, source interface{}
 		fieldName = fieldAST.Name.Value
 	}
 
-	fieldDef := getFieldDef(eCtx.Schema, parentType, fieldName)
+	fieldDef := field.fieldDef
+	if fieldDef == nil {
+		fieldDef = getFieldDef(eCtx.Schema, parentType, fieldName)
+	}
 	if fieldDef == nil {
 		resultState.hasNoFieldDefs = true
 		return nil, resultState
 	}
 	returnType = fieldDef.Type
+	if err := eCtx.context().Err(); err != nil {
+		eCtx.cutField(err)
+	}
 	resolveFn := fieldDef.Resolve
 	if resolveFn == nil {
 		resolveFn = DefaultResolveFn
 	}
+	if eCtx.hasDirectiveResolvers {
+		resolveFn = withDirectiveResolvers(eCtx, resolveFn, fieldDef, fieldASTs, fragmentDirectives)
+	}
 
 	// Build a map of arguments from the field.arguments AST, using the
-	// variables scope to fulfill any variable references.
-	// TODO: find a way to memoize, in case this field is within a List type.
-	args := getArgumentValues(fieldDef.Args, fieldAST.Arguments, eCtx.VariableValues)
+	// variables scope to fulfill any variable references. Arguments which do
+	// not depend on variables are computed once by prepared operations, and
+	// copied for each execution.
+	var args map[string]interface{}
+	if field.args != nil {
+		args = copyArgumentValue(field.args).(map[string]interface{})
+	} else {
+		args = getArgumentValues(fieldDef.Args, fieldAST.Arguments, eCtx.VariableValues)
+	}
 
 	info := ResolveInfo{
 		FieldName:      fieldName,
@@ -643,29 +1043,106 @@ func resolveField(eCtx *executionContext, parentType *Object, source interface{}
 
 	extErrs, resolveFieldFinishFn := handleExtensionsResolveFieldDidStart(eCtx.Schema.extensions, eCtx, &info)
 	if len(extErrs) != 0 {
-		eCtx.Errors = append(eCtx.Errors, extErrs...)
+		eCtx.addErrors(extErrs...)
 	}
 
-	result, resolveFnError = resolveFn(ResolveParams{
+	ctx, cancel := eCtx.context(), context.CancelFunc(nil)
+	if fieldDef.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, fieldDef.Timeout)
+	}
+	defer func() {
+		if cancel != nil {
+			cancel()
+		}
+	}()
+	params := ResolveParams{
 		Source:  source,
 		Args:    args,
 		Info:    info,
-		Context: eCtx.Context,
-	})
+		Context: ctx,
+	}
+	if fieldDef.Timeout > 0 {
+		result, resolveFnError = callWithContext(ctx, func() (interface{}, error) {
+			return resolveFn(params)
+		})
+	} else {
+		result, resolveFnError = resolveFn(params)
+	}
 
 	extErrs = resolveFieldFinishFn(result, resolveFnError)
 	if len(extErrs) != 0 {
-		eCtx.Errors = append(eCtx.Errors, extErrs...)
+		eCtx.addErrors(extErrs...)
 	}
 
 	if resolveFnError != nil {
+		if err := eCtx.context().Err(); err != nil {
+			eCtx.cutField(err)
+		}
 		panic(resolveFnError)
 	}
 
+	// The timeout of the field also bounds the thunk it may return.
+	if thunk, ok := result.(func() (interface{}, error)); ok && cancel != nil {
+		fieldCancel := cancel
+		cancel = nil
+		result = func() (interface{}, error) {
+			defer fieldCancel()
+			return callWithContext(ctx, thunk)
+		}
+	}
+
 	completed := completeValueCatchingError(eCtx, returnType, fieldASTs, info, path, result)
 	return completed, resultState
 }
 
+// withDirectiveResolvers wraps a resolve function with the Resolve functions
+// of the directives applied to the field definition, then of those used on
+// the given fields and their enclosing fragments, the outermost directive
+// being called first.
+func withDirectiveResolvers(eCtx *executionContext, resolveFn FieldResolveFn, fieldDef *FieldDefinition, fieldASTs []*ast.Field, fragmentDirectives map[*ast.Field][]*ast.Directive) FieldResolveFn {
+	type directiveUse struct {
+		directive *Directive
+		args      map[string]interface{}
+	}
+	uses := []directiveUse{}
+	for _, applied := range fieldDef.AppliedDirectives {
+		if directive := eCtx.Schema.Directive(applied.Name); directive != nil && directive.Resolve != nil {
+			uses = append(uses, directiveUse{directive, applied.Args})
+		}
+	}
+	used := map[string]bool{}
+	for _, fieldAST := range fieldASTs {
+		for _, node := range append(fragmentDirectives[fieldAST], fieldAST.Directives...) {
+			if node == nil || node.Name == nil {
+				continue
+			}
+			directive := eCtx.Schema.Directive(node.Name.Value)
+			if directive == nil || directive.Resolve == nil {
+				continue
+			}
+			// Merged fields may each use a non-repeatable directive.
+			if !directive.IsRepeatable && used[directive.Name] {
+				continue
+			}
+			used[directive.Name] = true
+			args := getArgumentValues(directive.Args, node.Arguments, eCtx.VariableValues)
+			uses = append(uses, directiveUse{directive, args})
+		}
+	}
+	for i := len(uses) - 1; i >= 0; i-- {
+		use, next := uses[i], resolveFn
+		resolveFn = func(p ResolveParams) (interface{}, error) {
+			return use.directive.Resolve(DirectiveResolveParams{
+				Directive:     use.directive,
+				Args:          use.args,
+				ResolveParams: p,
+				Next:          next,
+			})
+		}
+	}
+	return resolveFn
+}
+
 func completeValueCatchingError(eCtx *executionContext, returnType Type, fieldASTs []*ast.Field, info ResolveInfo, path *ResponsePath, result interface{}) (completed interface{}) {
 	// catch panic
 	defer func() interface{} {
@@ -765,8 +1242,15 @@ func completeThunkValueCatchingError(eCtx *executionContext, returnType Type, fi
 		err := gqlerrors.NewFormattedError("Error resolving func. Expected `func() (interface{}, error)` signature")
 		panic(gqlerrors.FormatError(err))
 	}
+	ctx := eCtx.context()
+	if err := ctx.Err(); err != nil {
+		eCtx.cutField(err)
+	}
 	fnResult, err := propertyFn()
 	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			eCtx.cutField(ctxErr)
+		}
 		panic(gqlerrors.FormatError(err))
 	}
 
@@ -790,7 +1274,7 @@ func completeAbstractValue(eCtx *executionContext, returnType Abstract, fieldAST
 	resolveTypeParams := ResolveTypeParams{
 		Value:   result,
 		Info:    info,
-		Context: eCtx.Context,
+		Context: eCtx.context(),
 	}
 	if unionReturnType, ok := returnType.(*Union); ok && unionReturnType.ResolveType != nil {
 		runtimeType = unionReturnType.ResolveType(resolveTypeParams)
@@ -827,7 +1311,7 @@ func completeObjectValue(eCtx *executionContext, returnType *Object, fieldASTs [
 		p := IsTypeOfParams{
 			Value:   result,
 			Info:    info,
-			Context: eCtx.Context,
+			Context: eCtx.context(),
 		}
 		if !returnType.IsTypeOf(p) {
 			panic(gqlerrors.NewFormattedError(
@@ -837,30 +1321,15 @@ func completeObjectValue(eCtx *executionContext, returnType *Object, fieldASTs [
 	}
 
 	// Collect sub-fields to execute to complete this value.
-	subFieldASTs := map[string][]*ast.Field{}
-	visitedFragmentNames := map[string]bool{}
-	for _, fieldAST := range fieldASTs {
-		if fieldAST == nil {
-			continue
-		}
-		selectionSet := fieldAST.SelectionSet
-		if selectionSet != nil {
-			innerParams := collectFieldsParams{
-				ExeContext:           eCtx,
-				RuntimeType:          returnType,
-				SelectionSet:         selectionSet,
-				Fields:               subFieldASTs,
-				VisitedFragmentNames: visitedFragmentNames,
-			}
-			subFieldASTs = collectFields(innerParams)
-		}
-	}
+	collected := collectSubFields(eCtx, returnType, nil, fieldASTs)
+	eCtx.deferFragments(returnType, result, path, collected.deferred)
 	executeFieldsParams := executeFieldsParams{
-		ExecutionContext: eCtx,
-		ParentType:       returnType,
-		Source:           result,
-		Fields:           subFieldASTs,
-		Path:             path,
+		ExecutionContext:   eCtx,
+		ParentType:         returnType,
+		Source:             result,
+		OrderedFields:      collected.fields,
+		Path:               path,
+		FragmentDirectives: collected.fragmentDirectives,
 	}
 	return executeSubFields(executeFieldsParams)
 }
@@ -894,12 +1363,24 @@ func completeListValue(eCtx *executionContext, returnType *List, fieldASTs []*as
 	}
 
 	itemType := returnType.OfType
-	completedResults := make([]interface{}, 0, resultVal.Len())
-	for i := 0; i < resultVal.Len(); i++ {
-		val := resultVal.Index(i).Interface()
-		fieldPath := path.WithKey(i)
-		completedItem := completeValueCatchingError(eCtx, itemType, fieldASTs, info, fieldPath, val)
-		completedResults = append(completedResults, completedItem)
+	length := resultVal.Len()
+	initialCount, label, streamed := streamArgs(eCtx, fieldASTs, path)
+	if streamed && initialCount < length {
+		length = initialCount
+	}
+	completedResults := make([]interface{}, length)
+	tasks := make([]func(), length)
+	for i := range tasks {
+		i := i
+		tasks[i] = func() {
+			val := resultVal.Index(i).Interface()
+			fieldPath := path.WithKey(i)
+			completedResults[i] = completeValueCatchingError(eCtx, itemType, fieldASTs, info, fieldPath, val)
+		}
+	}
+	runTasks(eCtx, tasks)
+	for i := length; streamed && i < resultVal.Len(); i++ {
+		eCtx.streamItem(itemType, fieldASTs, info, path.WithKey(i), label, resultVal.Index(i).Interface())
 	}
 	return completedResults
 }
@@ -1044,34 +1525,22 @@ func getFieldDef(schema Schema, parentType *Object, fieldName string) *FieldDefi
 type orderedField struct {
 	responseName string
 	fieldASTs    []*ast.Field
+
+	// fieldDef and args, the arguments of the field when they do not depend
+	// on variables, are known beforehand for prepared operations.
+	fieldDef *FieldDefinition
+	args     map[string]interface{}
 }
 
-// orders fields from a fields map by location in the source
-func orderedFields(fields map[string][]*ast.Field) []*orderedField {
-	orderedFields := []*orderedField{}
-	fieldMap := map[int]*orderedField{}
-	startLocs := []int{}
-
-	for responseName, fieldASTs := range fields {
-		// find the lowest location in the current fieldASTs
-		lowest := -1
-		for _, fieldAST := range fieldASTs {
-			loc := fieldAST.GetLoc().Start
-			if lowest == -1 || loc < lowest {
-				lowest = loc
-			}
-		}
-		startLocs = append(startLocs, lowest)
-		fieldMap[lowest] = &orderedField{
+// orderedFields returns the collected fields in the order of their response
+// names, see collectFieldsParams.ResponseNames.
+func orderedFields(fields map[string][]*ast.Field, responseNames []string) []*orderedField {
+	orderedFields := make([]*orderedField, 0, len(responseNames))
+	for _, responseName := range responseNames {
+		orderedFields = append(orderedFields, &orderedField{
 			responseName: responseName,
-			fieldASTs:    fieldASTs,
-		}
-	}
-
-	sort.Ints(startLocs)
-	for _, startLoc := range startLocs {
-		orderedFields = append(orderedFields, fieldMap[startLoc])
+			fieldASTs:    fields[responseName],
+		})
 	}
-
 	return orderedFields
 }
@@ -0,0 +1,235 @@
+package graphql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/location"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+// cancellationTestRoot returns a root value whose cancel field cancels the
+// execution.
+func cancellationTestRoot(cancel context.CancelFunc) map[string]interface{} {
+	return map[string]interface{}{
+		"cancel": func() interface{} {
+			cancel()
+			return "cancel"
+		},
+	}
+}
+
+func cancellationTestSchema(t *testing.T, cancel context.CancelFunc) graphql.Schema {
+	var objectType *graphql.Object
+	objectType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Object",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"fast": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return "fast", nil
+					},
+				},
+				// Resolved by the default resolve function, which is called
+				// directly, from the root value.
+				"cancel": &graphql.Field{
+					Type: graphql.String,
+				},
+				"stuck": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						cancel()
+						<-p.Context.Done()
+						return nil, p.Context.Err()
+					},
+				},
+				"timeout": &graphql.Field{
+					Type:    graphql.String,
+					Timeout: 10 * time.Millisecond,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						<-p.Context.Done()
+						time.Sleep(time.Second)
+						return "timeout", nil
+					},
+				},
+				"thunkTimeout": &graphql.Field{
+					Type:    graphql.String,
+					Timeout: 10 * time.Millisecond,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return func() (interface{}, error) {
+							time.Sleep(time.Second)
+							return "thunkTimeout", nil
+						}, nil
+					},
+				},
+				"object": &graphql.Field{
+					Type: objectType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return p.Source, nil
+					},
+				},
+				"nonNullObject": &graphql.Field{
+					Type: graphql.NewNonNull(objectType),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return p.Source, nil
+					},
+				},
+				"nonNullFast": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return "nonNullFast", nil
+					},
+				},
+			}
+		}),
+	})
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: objectType,
+	})
+	if err != nil {
+		t.Fatalf("Error in schema %v", err.Error())
+	}
+	return schema
+}
+
+func TestCancellation_ReturnsPartialDataWithAnErrorWhereExecutionWasCut(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	result := graphql.Do(graphql.Params{
+		Schema:        cancellationTestSchema(t, cancel),
+		RequestString: `{ fast cancel object { fast } after: fast }`,
+		RootObject:    cancellationTestRoot(cancel),
+		Context:       ctx,
+	})
+	expected := &graphql.Result{
+		Data: map[string]interface{}{
+			"fast":   "fast",
+			"cancel": "cancel",
+			"object": nil,
+			"after":  nil,
+		},
+		Errors: []gqlerrors.FormattedError{
+			{
+				Message:   context.Canceled.Error(),
+				Locations: []location.SourceLocation{{Line: 1, Column: 15}},
+				Path:      []interface{}{"object"},
+			},
+		},
+	}
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
+
+func TestCancellation_CutsTheFieldsFollowingResolversInFlight(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	result := graphql.Do(graphql.Params{
+		Schema:        cancellationTestSchema(t, cancel),
+		RequestString: `{ fast object { stuck fast } }`,
+		RootObject:    cancellationTestRoot(cancel),
+		Context:       ctx,
+	})
+	expected := &graphql.Result{
+		Data: map[string]interface{}{
+			"fast": "fast",
+			"object": map[string]interface{}{
+				"stuck": nil,
+				"fast":  nil,
+			},
+		},
+		Errors: []gqlerrors.FormattedError{
+			{
+				Message:   context.Canceled.Error(),
+				Locations: []location.SourceLocation{{Line: 1, Column: 17}},
+				Path:      []interface{}{"object", "stuck"},
+			},
+		},
+	}
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
+
+func TestCancellation_NullsTheParentsOfNonNullFieldsCut(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	result := graphql.Do(graphql.Params{
+		Schema:        cancellationTestSchema(t, cancel),
+		RequestString: `{ cancel object { nonNullFast } other: object { nonNullObject { fast } } }`,
+		RootObject:    cancellationTestRoot(cancel),
+		Context:       ctx,
+	})
+	expected := &graphql.Result{
+		Data: map[string]interface{}{
+			"cancel": "cancel",
+			"object": nil,
+			"other":  nil,
+		},
+		Errors: []gqlerrors.FormattedError{
+			{
+				Message:   context.Canceled.Error(),
+				Locations: []location.SourceLocation{{Line: 1, Column: 10}},
+				Path:      []interface{}{"object"},
+			},
+		},
+	}
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
+
+func TestCancellation_AbandonsFieldsExceedingTheirTimeout(t *testing.T) {
+	start := time.Now()
+	result := graphql.Do(graphql.Params{
+		Schema:        cancellationTestSchema(t, func() {}),
+		RequestString: `{ timeout thunkTimeout fast }`,
+		Context:       context.Background(),
+	})
+	if duration := time.Since(start); duration > 500*time.Millisecond {
+		t.Fatalf("Expected the fields to time out, execution took %v", duration)
+	}
+	expected := &graphql.Result{
+		Data: map[string]interface{}{
+			"timeout":      nil,
+			"thunkTimeout": nil,
+			"fast":         "fast",
+		},
+		Errors: []gqlerrors.FormattedError{
+			{
+				Message:   context.DeadlineExceeded.Error(),
+				Locations: []location.SourceLocation{{Line: 1, Column: 3}},
+				Path:      []interface{}{"timeout"},
+			},
+			{
+				Message:   context.DeadlineExceeded.Error(),
+				Locations: []location.SourceLocation{{Line: 1, Column: 11}},
+				Path:      []interface{}{"thunkTimeout"},
+			},
+		},
+	}
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
+
+func TestCancellation_StopsSchedulingConcurrentFields(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result := graphql.Do(graphql.Params{
+		Schema:        cancellationTestSchema(t, cancel),
+		RequestString: `{ fast object { fast } }`,
+		Context:       ctx,
+		Concurrency:   4,
+	})
+	if !testutil.EqualData(map[string]interface{}{"fast": nil, "object": nil}, result.Data) {
+		t.Fatalf("Unexpected data: %v", result.Data)
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Message != context.Canceled.Error() {
+		t.Fatalf("Expected a single cancellation error, got: %v", result.Errors)
+	}
+}
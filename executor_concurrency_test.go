@@ -0,0 +1,259 @@
+package graphql_test
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/location"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+// inFlight tracks how many resolvers are running at once.
+type inFlight struct {
+	current int32
+	max     int32
+}
+
+func (f *inFlight) resolve(value interface{}) (interface{}, error) {
+	current := atomic.AddInt32(&f.current, 1)
+	defer atomic.AddInt32(&f.current, -1)
+	for {
+		max := atomic.LoadInt32(&f.max)
+		if current <= max || atomic.CompareAndSwapInt32(&f.max, max, current) {
+			break
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+	return value, nil
+}
+
+func concurrencyTestSchema(t *testing.T, f *inFlight) graphql.Schema {
+	itemType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Item",
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type: graphql.Int,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return f.resolve(p.Source)
+				},
+			},
+			"error": &graphql.Field{
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return nil, fmt.Errorf("error %v", p.Source)
+				},
+			},
+			"nonNullError": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return nil, fmt.Errorf("error %v", p.Source)
+				},
+			},
+		},
+	})
+	fields := graphql.Fields{
+		"items": &graphql.Field{
+			Type: graphql.NewList(itemType),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return []interface{}{1, 2, 3, 4, 5, 6}, nil
+			},
+		},
+	}
+	for _, name := range []string{"a", "b", "c", "d"} {
+		name := name
+		fields[name] = &graphql.Field{
+			Type: graphql.String,
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return f.resolve(name)
+			},
+		}
+	}
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name:   "Query",
+			Fields: fields,
+		}),
+		Mutation: graphql.NewObject(graphql.ObjectConfig{
+			Name:   "Mutation",
+			Fields: fields,
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Error in schema %v", err.Error())
+	}
+	return schema
+}
+
+func TestConcurrency_ResolvesSiblingFieldsConcurrently(t *testing.T) {
+	f := &inFlight{}
+	result := graphql.Do(graphql.Params{
+		Schema:        concurrencyTestSchema(t, f),
+		RequestString: `{ d c b a }`,
+		Concurrency:   4,
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("wrong result, unexpected errors: %v", result.Errors)
+	}
+	expected := map[string]interface{}{
+		"a": "a",
+		"b": "b",
+		"c": "c",
+		"d": "d",
+	}
+	if data := result.Data.(*graphql.OrderedMap).Map(); !reflect.DeepEqual(expected, data) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, data))
+	}
+	if keys := result.Data.(*graphql.OrderedMap).Keys(); fmt.Sprint(keys) != "[d c b a]" {
+		t.Fatalf("Unexpected field order: %v", keys)
+	}
+	if f.max < 2 {
+		t.Fatalf("Expected fields to be resolved concurrently, at most %v were", f.max)
+	}
+}
+
+func TestConcurrency_BoundsResolversRunningAtOnce(t *testing.T) {
+	f := &inFlight{}
+	result := graphql.Do(graphql.Params{
+		Schema:        concurrencyTestSchema(t, f),
+		RequestString: `{ a b items { id } c d }`,
+		Concurrency:   3,
+	})
+	expected := &graphql.Result{
+		Data: map[string]interface{}{
+			"a": "a",
+			"b": "b",
+			"c": "c",
+			"d": "d",
+			"items": []interface{}{
+				map[string]interface{}{"id": 1},
+				map[string]interface{}{"id": 2},
+				map[string]interface{}{"id": 3},
+				map[string]interface{}{"id": 4},
+				map[string]interface{}{"id": 5},
+				map[string]interface{}{"id": 6},
+			},
+		},
+	}
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+	if f.max > 3 {
+		t.Fatalf("Expected at most 3 resolvers at once, got %v", f.max)
+	}
+}
+
+func TestConcurrency_CollectsErrorsOfConcurrentFields(t *testing.T) {
+	result := graphql.Do(graphql.Params{
+		Schema:        concurrencyTestSchema(t, &inFlight{}),
+		RequestString: `{ items { error } }`,
+		Concurrency:   4,
+	})
+	if len(result.Errors) != 6 {
+		t.Fatalf("Expected an error per item, got: %v", result.Errors)
+	}
+	messages := map[string]bool{}
+	for _, err := range result.Errors {
+		messages[err.Message] = true
+	}
+	for i := 1; i <= 6; i++ {
+		if !messages[fmt.Sprintf("error %v", i)] {
+			t.Fatalf("Expected error of item %v, got: %v", i, result.Errors)
+		}
+	}
+}
+
+func TestConcurrency_PropagatesNullsOfNonNullFields(t *testing.T) {
+	result := graphql.Do(graphql.Params{
+		Schema:        concurrencyTestSchema(t, &inFlight{}),
+		RequestString: `{ a items { id nonNullError } }`,
+		Concurrency:   4,
+	})
+	expected := &graphql.Result{
+		Data: map[string]interface{}{
+			"a":     "a",
+			"items": []interface{}{nil, nil, nil, nil, nil, nil},
+		},
+	}
+	if !testutil.EqualData(expected.Data, result.Data) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected.Data, result.Data))
+	}
+	if len(result.Errors) != 6 {
+		t.Fatalf("Expected an error per item, got: %v", result.Errors)
+	}
+	for _, err := range result.Errors {
+		expected := gqlerrors.FormattedError{
+			Message:   err.Message,
+			Locations: []location.SourceLocation{{Line: 1, Column: 16}},
+			Path:      err.Path,
+		}
+		if !testutil.EqualFormattedError(expected, err) || len(err.Path) != 3 {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+}
+
+func TestConcurrency_ResolvesMutationFieldsSerially(t *testing.T) {
+	f := &inFlight{}
+	result := graphql.Do(graphql.Params{
+		Schema:        concurrencyTestSchema(t, f),
+		RequestString: `mutation { a b c d }`,
+		Concurrency:   4,
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	if f.max != 1 {
+		t.Fatalf("Expected mutation fields to be resolved one at a time, %v were at once", f.max)
+	}
+}
+
+func TestConcurrency_KeepsSerialResolutionByDefault(t *testing.T) {
+	f := &inFlight{}
+	result := graphql.Do(graphql.Params{
+		Schema:        concurrencyTestSchema(t, f),
+		RequestString: `{ a b items { id } }`,
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	if f.max != 1 {
+		t.Fatalf("Expected fields to be resolved one at a time, %v were at once", f.max)
+	}
+}
+
+type concurrencyTestKey struct{}
+
+func TestConcurrency_UpdatesContextFromExtensionsSafely(t *testing.T) {
+	var mu sync.Mutex
+	resolved := 0
+	ext := newtestExt("concurrentExt")
+	ext.resolveFieldDidStartFn = func(ctx context.Context, i *graphql.ResolveInfo) (context.Context, graphql.ResolveFieldFinishFunc) {
+		ctx = context.WithValue(ctx, concurrencyTestKey{}, i.FieldName)
+		return ctx, func(v interface{}, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			resolved++
+		}
+	}
+	schema := concurrencyTestSchema(t, &inFlight{})
+	schema.AddExtensions(ext)
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ a b items { id } }`,
+		Context:       context.Background(),
+		Concurrency:   4,
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	if resolved != 9 {
+		t.Fatalf("Expected 9 fields to be resolved, got %v", resolved)
+	}
+}
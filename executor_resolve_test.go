@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"github.com/graphql-go/graphql"
 	"github.com/graphql-go/graphql/testutil"
-	"reflect"
 	"testing"
 )
 
@@ -39,7 +38,7 @@ func TestExecutesResolveFunction_DefaultFunctionAccessesProperties(t *testing.T)
 		RequestString: `{ test }`,
 		RootObject:    source,
 	})
-	if !reflect.DeepEqual(expected, result.Data) {
+	if !testutil.EqualData(expected, result.Data) {
 		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result.Data))
 	}
 }
@@ -62,7 +61,7 @@ func TestExecutesResolveFunction_DefaultFunctionCallsMethods(t *testing.T) {
 		RequestString: `{ test }`,
 		RootObject:    source,
 	})
-	if !reflect.DeepEqual(expected, result.Data) {
+	if !testutil.EqualData(expected, result.Data) {
 		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result.Data))
 	}
 }
@@ -87,7 +86,7 @@ func TestExecutesResolveFunction_UsesProvidedResolveFunction(t *testing.T) {
 		Schema:        schema,
 		RequestString: `{ test }`,
 	})
-	if !reflect.DeepEqual(expected, result.Data) {
+	if !testutil.EqualData(expected, result.Data) {
 		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result.Data))
 	}
 
@@ -98,7 +97,7 @@ func TestExecutesResolveFunction_UsesProvidedResolveFunction(t *testing.T) {
 		Schema:        schema,
 		RequestString: `{ test(aStr: "String!") }`,
 	})
-	if !reflect.DeepEqual(expected, result.Data) {
+	if !testutil.EqualData(expected, result.Data) {
 		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result.Data))
 	}
 
@@ -109,7 +108,7 @@ func TestExecutesResolveFunction_UsesProvidedResolveFunction(t *testing.T) {
 		Schema:        schema,
 		RequestString: `{ test(aInt: -123, aStr: "String!") }`,
 	})
-	if !reflect.DeepEqual(expected, result.Data) {
+	if !testutil.EqualData(expected, result.Data) {
 		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result.Data))
 	}
 }
@@ -156,7 +155,7 @@ func TestExecutesResolveFunction_UsesProvidedResolveFunction_SourceIsStruct_With
 		RequestString: `{ test { Str, Int } }`,
 	})
 
-	if !reflect.DeepEqual(expected, result.Data) {
+	if !testutil.EqualData(expected, result.Data) {
 		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result.Data))
 	}
 
@@ -170,7 +169,7 @@ func TestExecutesResolveFunction_UsesProvidedResolveFunction_SourceIsStruct_With
 		Schema:        schema,
 		RequestString: `{ test(aStr: "String!") { Str, Int } }`,
 	})
-	if !reflect.DeepEqual(expected, result.Data) {
+	if !testutil.EqualData(expected, result.Data) {
 		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result.Data))
 	}
 
@@ -184,7 +183,7 @@ func TestExecutesResolveFunction_UsesProvidedResolveFunction_SourceIsStruct_With
 		Schema:        schema,
 		RequestString: `{ test(aInt: -123, aStr: "String!") { Str, Int } }`,
 	})
-	if !reflect.DeepEqual(expected, result.Data) {
+	if !testutil.EqualData(expected, result.Data) {
 		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result.Data))
 	}
 }
@@ -232,7 +231,7 @@ func TestExecutesResolveFunction_UsesProvidedResolveFunction_SourceIsStruct_With
 		RequestString: `{ test { str, int } }`,
 	})
 
-	if !reflect.DeepEqual(expected, result.Data) {
+	if !testutil.EqualData(expected, result.Data) {
 		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result.Data))
 	}
 
@@ -246,7 +245,7 @@ func TestExecutesResolveFunction_UsesProvidedResolveFunction_SourceIsStruct_With
 		Schema:        schema,
 		RequestString: `{ test(aStr: "String!") { str, int } }`,
 	})
-	if !reflect.DeepEqual(expected, result.Data) {
+	if !testutil.EqualData(expected, result.Data) {
 		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result.Data))
 	}
 
@@ -260,7 +259,7 @@ func TestExecutesResolveFunction_UsesProvidedResolveFunction_SourceIsStruct_With
 		Schema:        schema,
 		RequestString: `{ test(aInt: -123, aStr: "String!") { str, int } }`,
 	})
-	if !reflect.DeepEqual(expected, result.Data) {
+	if !testutil.EqualData(expected, result.Data) {
 		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result.Data))
 	}
 }
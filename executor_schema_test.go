@@ -2,7 +2,6 @@ package graphql_test
 
 import (
 	"fmt"
-	"reflect"
 	"testing"
 
 	"github.com/graphql-go/graphql"
@@ -309,7 +308,7 @@ func TestExecutesUsingAComplexSchema(t *testing.T) {
 	if len(result.Errors) > 0 {
 		t.Fatalf("wrong result, unexpected errors: %v", result.Errors)
 	}
-	if !reflect.DeepEqual(expected, result) {
+	if !testutil.EqualResults(expected, result) {
 		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
 	}
 }
@@ -205,7 +205,7 @@ func TestExecutesArbitraryCode(t *testing.T) {
 	if len(result.Errors) > 0 {
 		t.Fatalf("wrong result, unexpected errors: %v", result.Errors)
 	}
-	if !reflect.DeepEqual(expected, result) {
+	if !testutil.EqualResults(expected, result) {
 		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
 	}
 }
@@ -292,7 +292,7 @@ func TestMergesParallelFragments(t *testing.T) {
 	if len(result.Errors) > 0 {
 		t.Fatalf("wrong result, unexpected errors: %v", result.Errors)
 	}
-	if !reflect.DeepEqual(expected, result) {
+	if !testutil.EqualResults(expected, result) {
 		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
 	}
 }
@@ -348,7 +348,7 @@ func TestCustomMapType(t *testing.T) {
 			"a": "1",
 		},
 	}
-	if !reflect.DeepEqual(result.Data, expected) {
+	if !testutil.EqualData(expected, result.Data) {
 		t.Fatalf("Expected context.key to equal %v, got %v", expected, result.Data)
 	}
 }
@@ -507,7 +507,7 @@ func TestThreadsRootValueContextCorrectly(t *testing.T) {
 			"a": "stringValue",
 		},
 	}
-	if !reflect.DeepEqual(expected, result) {
+	if !testutil.EqualResults(expected, result) {
 		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
 	}
 }
@@ -554,7 +554,7 @@ func TestThreadsContextCorrectly(t *testing.T) {
 			"a": "bar",
 		},
 	}
-	if !reflect.DeepEqual(expected, result) {
+	if !testutil.EqualResults(expected, result) {
 		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
 	}
 }
@@ -619,7 +619,7 @@ func TestNullsOutErrorSubtrees(t *testing.T) {
 		Root:   data,
 	}
 	result := testutil.TestExecute(t, ep)
-	if !reflect.DeepEqual(expectedData, result.Data) {
+	if !testutil.EqualData(expectedData, result.Data) {
 		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expectedData, result.Data))
 	}
 	if !testutil.EqualFormattedErrors(expectedErrors, result.Errors) {
@@ -667,7 +667,7 @@ func TestUsesTheInlineOperationIfNoOperationNameIsProvided(t *testing.T) {
 	if len(result.Errors) > 0 {
 		t.Fatalf("wrong result, unexpected errors: %v", result.Errors)
 	}
-	if !reflect.DeepEqual(expected, result) {
+	if !testutil.EqualResults(expected, result) {
 		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
 	}
 }
@@ -712,7 +712,7 @@ func TestUsesTheOnlyOperationIfNoOperationNameIsProvided(t *testing.T) {
 	if len(result.Errors) > 0 {
 		t.Fatalf("wrong result, unexpected errors: %v", result.Errors)
 	}
-	if !reflect.DeepEqual(expected, result) {
+	if !testutil.EqualResults(expected, result) {
 		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
 	}
 }
@@ -757,7 +757,7 @@ func TestUsesTheNamedOperationIfOperationNameIsProvided(t *testing.T) {
 	if len(result.Errors) > 0 {
 		t.Fatalf("wrong result, unexpected errors: %v", result.Errors)
 	}
-	if !reflect.DeepEqual(expected, result) {
+	if !testutil.EqualResults(expected, result) {
 		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
 	}
 }
@@ -1008,7 +1008,7 @@ func TestUsesTheQuerySchemaForQueries(t *testing.T) {
 	if len(result.Errors) > 0 {
 		t.Fatalf("wrong result, unexpected errors: %v", result.Errors)
 	}
-	if !reflect.DeepEqual(expected, result) {
+	if !testutil.EqualResults(expected, result) {
 		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
 	}
 }
@@ -1063,7 +1063,7 @@ func TestUsesTheMutationSchemaForMutations(t *testing.T) {
 	if len(result.Errors) > 0 {
 		t.Fatalf("wrong result, unexpected errors: %v", result.Errors)
 	}
-	if !reflect.DeepEqual(expected, result) {
+	if !testutil.EqualResults(expected, result) {
 		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
 	}
 }
@@ -1118,7 +1118,7 @@ func TestUsesTheSubscriptionSchemaForSubscriptions(t *testing.T) {
 	if len(result.Errors) > 0 {
 		t.Fatalf("wrong result, unexpected errors: %v", result.Errors)
 	}
-	if !reflect.DeepEqual(expected, result) {
+	if !testutil.EqualResults(expected, result) {
 		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
 	}
 }
@@ -1191,19 +1191,135 @@ func TestCorrectFieldOrderingDespiteExecutionOrder(t *testing.T) {
 	if len(result.Errors) > 0 {
 		t.Fatalf("wrong result, unexpected errors: %v", result.Errors)
 	}
-	if !reflect.DeepEqual(expected, result) {
+	if !testutil.EqualResults(expected, result) {
 		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
 	}
 
-	// TODO: test to ensure key ordering
-	// The following does not work
-	// - iterating over result.Data map
-	//   Note that golang's map iteration order is randomized
-	//   So, iterating over result.Data won't do it for a test
-	// - Marshal the result.Data to json string and assert it
-	//   json.Marshal seems to re-sort the keys automatically
-	//
-	t.Skipf("TODO: Ensure key ordering")
+	if keys := result.Data.(*graphql.OrderedMap).Keys(); !reflect.DeepEqual(keys, []string{"b", "a", "c", "d", "e"}) {
+		t.Fatalf("Unexpected key ordering: %v", keys)
+	}
+	b, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("Unexpected error marshalling result: %v", err)
+	}
+	if expected := `{"data":{"b":"b","a":"a","c":"c","d":"d","e":"e"}}`; string(b) != expected {
+		t.Fatalf("Unexpected JSON, expected: %v, got: %v", expected, string(b))
+	}
+}
+
+func TestCorrectFieldOrderingOfNestedObjectsAndFragments(t *testing.T) {
+	doc := `
+	{
+      ...Frag
+      z: b
+      c { y, x }
+      ... on Type { a }
+      list { y, x }
+    }
+    fragment Frag on Type { b }
+	`
+	child := map[string]interface{}{"x": "x", "y": "y"}
+	data := map[string]interface{}{
+		"a":    "a",
+		"b":    "b",
+		"c":    func() interface{} { return child },
+		"list": []interface{}{child, child},
+	}
+	childType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Child",
+		Fields: graphql.Fields{
+			"x": &graphql.Field{Type: graphql.String},
+			"y": &graphql.Field{Type: graphql.String},
+		},
+	})
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Type",
+			Fields: graphql.Fields{
+				"a":    &graphql.Field{Type: graphql.String},
+				"b":    &graphql.Field{Type: graphql.String},
+				"c":    &graphql.Field{Type: childType},
+				"list": &graphql.Field{Type: graphql.NewList(childType)},
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Error in schema %v", err.Error())
+	}
+
+	result := testutil.TestExecute(t, graphql.ExecuteParams{
+		Schema: schema,
+		AST:    testutil.TestParse(t, doc),
+		Root:   data,
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("wrong result, unexpected errors: %v", result.Errors)
+	}
+	b, err := json.Marshal(result.Data)
+	if err != nil {
+		t.Fatalf("Unexpected error marshalling result: %v", err)
+	}
+	expected := `{"b":"b","z":"b","c":{"y":"y","x":"x"},"a":"a","list":[{"y":"y","x":"x"},{"y":"y","x":"x"}]}`
+	if string(b) != expected {
+		t.Fatalf("Unexpected JSON, expected: %v, got: %v", expected, string(b))
+	}
+
+	c, ok := result.Data.(*graphql.OrderedMap).Get("c")
+	if !ok {
+		t.Fatalf("Expected field c to be set")
+	}
+	if x, _ := c.(*graphql.OrderedMap).Get("x"); x != "x" {
+		t.Fatalf("Unexpected value of c.x: %v", x)
+	}
+	expectedData := map[string]interface{}{
+		"z":    "b",
+		"a":    "a",
+		"b":    "b",
+		"c":    map[string]interface{}{"x": "x", "y": "y"},
+		"list": []interface{}{map[string]interface{}{"x": "x", "y": "y"}, map[string]interface{}{"x": "x", "y": "y"}},
+	}
+	if data := result.Data.(*graphql.OrderedMap).Map(); !reflect.DeepEqual(expectedData, data) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expectedData, data))
+	}
+}
+
+func TestUnorderedResultsAreMaps(t *testing.T) {
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"a": &graphql.Field{
+					Type: graphql.NewList(graphql.NewObject(graphql.ObjectConfig{
+						Name: "Child",
+						Fields: graphql.Fields{
+							"b": &graphql.Field{Type: graphql.String},
+						},
+					})),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return []interface{}{map[string]interface{}{"b": "b"}}, nil
+					},
+				},
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Error in schema %v", err.Error())
+	}
+	result := graphql.Do(graphql.Params{
+		Schema:           schema,
+		RequestString:    `{ a { b } }`,
+		UnorderedResults: true,
+	})
+	expected := &graphql.Result{
+		Data: map[string]interface{}{
+			"a": []interface{}{
+				map[string]interface{}{"b": "b"},
+			},
+		},
+	}
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
 }
 
 func TestAvoidsRecursion(t *testing.T) {
@@ -1258,7 +1374,7 @@ func TestAvoidsRecursion(t *testing.T) {
 	if len(result.Errors) > 0 {
 		t.Fatalf("wrong result, unexpected errors: %v", result.Errors)
 	}
-	if !reflect.DeepEqual(expected, result) {
+	if !testutil.EqualResults(expected, result) {
 		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
 	}
 
@@ -1308,7 +1424,7 @@ func TestDoesNotIncludeIllegalFieldsInOutput(t *testing.T) {
 	if len(result.Errors) != 0 {
 		t.Fatalf("wrong result, expected len(%v) errors, got len(%v)", len(expected.Errors), len(result.Errors))
 	}
-	if !reflect.DeepEqual(expected, result) {
+	if !testutil.EqualResults(expected, result) {
 		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
 	}
 }
@@ -1370,7 +1486,7 @@ func TestDoesNotIncludeArgumentsThatWereNotSet(t *testing.T) {
 	if len(result.Errors) > 0 {
 		t.Fatalf("wrong result, unexpected errors: %v", result.Errors)
 	}
-	if !reflect.DeepEqual(expected, result) {
+	if !testutil.EqualResults(expected, result) {
 		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
 	}
 }
@@ -1788,7 +1904,7 @@ func TestGraphqlTag(t *testing.T) {
 			"fooBar": "foo bar value",
 		},
 	}
-	if !reflect.DeepEqual(result.Data, expectedData) {
+	if !testutil.EqualData(expectedData, result.Data) {
 		t.Fatalf("unexpected result, got: %+v, expected: %+v", expectedData, result.Data)
 	}
 }
@@ -1843,7 +1959,7 @@ func TestFieldResolver(t *testing.T) {
 			"fooBar": "foo bar value",
 		},
 	}
-	if !reflect.DeepEqual(result.Data, expectedData) {
+	if !testutil.EqualData(expectedData, result.Data) {
 		t.Fatalf("unexpected result, got: %+v, expected: %+v", result.Data, expectedData)
 	}
 }
@@ -1860,10 +1976,16 @@ func (r testCustomResolver) Resolve(p graphql.ResolveParams) (interface{}, error
 func TestContextDeadline(t *testing.T) {
 	timeout := time.Millisecond * time.Duration(100)
 	acceptableDelay := time.Millisecond * time.Duration(10)
-	expectedErrors := []gqlerrors.FormattedError{
-		{
-			Message:   context.DeadlineExceeded.Error(),
-			Locations: []location.SourceLocation{},
+	expected := &graphql.Result{
+		Data: map[string]interface{}{
+			"hello": nil,
+		},
+		Errors: []gqlerrors.FormattedError{
+			{
+				Message:   context.DeadlineExceeded.Error(),
+				Locations: []location.SourceLocation{{Line: 1, Column: 2}},
+				Path:      []interface{}{"hello"},
+			},
 		},
 	}
 
@@ -1875,8 +1997,12 @@ func TestContextDeadline(t *testing.T) {
 				"hello": &graphql.Field{
 					Type: graphql.String,
 					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
-						time.Sleep(2 * time.Second)
-						return "world", nil
+						select {
+						case <-time.After(2 * time.Second):
+							return "world", nil
+						case <-p.Context.Done():
+							return nil, p.Context.Err()
+						}
 					},
 				},
 			},
@@ -1905,8 +2031,8 @@ func TestContextDeadline(t *testing.T) {
 	if !result.HasErrors() || len(result.Errors) == 0 {
 		t.Fatalf("Result should include errors when deadline is exceeded")
 	}
-	if !testutil.EqualFormattedErrors(expectedErrors, result.Errors) {
-		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expectedErrors, result.Errors))
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
 	}
 }
 
@@ -1976,7 +2102,7 @@ func TestThunkResultsProcessedCorrectly(t *testing.T) {
 		t.Fatalf("expected no errors, got %v", result.Errors)
 	}
 
-	foo := result.Data.(map[string]interface{})["foo"].(map[string]interface{})
+	foo := result.Data.(*graphql.OrderedMap).Map()["foo"].(map[string]interface{})
 	bar, ok := foo["bar"].(map[string]interface{})
 
 	if !ok {
@@ -2069,7 +2195,7 @@ func TestThunkErrorsAreHandledCorrectly(t *testing.T) {
 		RequestString: query,
 	})
 
-	foo := result.Data.(map[string]interface{})["foo"].(map[string]interface{})
+	foo := result.Data.(*graphql.OrderedMap).Map()["foo"].(map[string]interface{})
 	bar, ok := foo["bar"].(map[string]interface{})
 
 	if !ok {
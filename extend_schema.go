@@ -0,0 +1,414 @@
+package graphql
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// ExtendSchema returns a new Schema made of the given schema and the types,
+// type extensions, directives and schema extensions of the given document.
+//
+// This lets separate modules contribute fields, interfaces, union members,
+// enum values and input fields to types shared by a schema, e.g.:
+//
+//	extend type Query {
+//	  photos(first: Int): [Photo]
+//	}
+//
+// The given schema is left unchanged. Types of the given schema keep their
+// resolvers, while the types and fields added by the document are resolved
+// as by BuildASTSchema; resolvers may be bound to them with BindResolvers.
+func ExtendSchema(schema Schema, doc *ast.Document) (Schema, error) {
+	if err := invariant(doc != nil, "Must provide a schema definition document."); err != nil {
+		return Schema{}, err
+	}
+
+	b := newSchemaBuilder(BuildSchemaOptions{})
+	b.schema = &schema
+	if err := b.collectDefinitions(doc); err != nil {
+		return Schema{}, err
+	}
+	if len(b.typeDefNames) == 0 && len(b.extensionNames) == 0 &&
+		len(b.directiveDefs) == 0 && len(b.schemaExtensions) == 0 {
+		return schema, nil
+	}
+	for _, name := range b.typeDefNames {
+		b.namedType(name)
+	}
+	if err := b.checkDefinitions(); err != nil {
+		return Schema{}, err
+	}
+
+	directives, err := b.extendDirectives()
+	if err != nil {
+		return Schema{}, err
+	}
+	config, err := b.extendRootTypes()
+	if err != nil {
+		return Schema{}, err
+	}
+	config.Directives = directives
+	config.Types = b.extendedTypes()
+	config.Extensions = schema.extensions
+	config.AppliedDirectives = append(append(AppliedDirectives{}, schema.AppliedDirectives()...), b.schemaAppliedDirectives()...)
+	b.applyDirectives()
+	return NewSchema(config)
+}
+
+// checkExtensions checks the extensions of the types of the schema being
+// extended; extensions of types defined by the document are checked along
+// with their definition.
+func (b *schemaBuilder) checkExtensions() error {
+	if b.schema == nil {
+		return nil
+	}
+	for _, name := range b.extensionNames {
+		if _, ok := b.typeDefs[name]; ok {
+			continue
+		}
+		var err error
+		switch ttype := b.schema.Type(name).(type) {
+		case *Object:
+			err = b.checkObject(name, b.extensionInterfaces(name), b.extensionFields(name), fieldNameSet(ttype.Fields()))
+		case *Interface:
+			err = b.checkObject(name, b.extensionInterfaces(name), b.extensionFields(name), fieldNameSet(ttype.Fields()))
+		case *Union:
+			err = b.checkUnionMembers(name, b.extensionUnionMembers(name))
+		case *Enum:
+			existing := map[string]bool{}
+			for _, value := range ttype.Values() {
+				existing[value.Name] = true
+			}
+			err = checkUniqueNames("Enum value", name, enumValueNames(b.extensionEnumValues(name)), existing)
+		case *InputObject:
+			existing := map[string]bool{}
+			for fieldName := range ttype.Fields() {
+				existing[fieldName] = true
+			}
+			err = b.checkInputFields(name, b.extensionInputFields(name), existing)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func fieldNameSet(fields FieldDefinitionMap) map[string]bool {
+	names := map[string]bool{}
+	for name := range fields {
+		names[name] = true
+	}
+	return names
+}
+
+// extendType returns the counterpart of a type of the schema being extended,
+// which refers to the types of the extended schema and includes the
+// extensions of the document.
+func (b *schemaBuilder) extendType(ttype Type) Type {
+	if strings.HasPrefix(ttype.Name(), "__") {
+		return ttype
+	}
+	switch ttype := ttype.(type) {
+	case *Object:
+		return b.extendObject(ttype)
+	case *Interface:
+		return b.extendInterface(ttype)
+	case *Union:
+		return b.extendUnion(ttype)
+	case *Enum:
+		return b.extendEnum(ttype)
+	case *InputObject:
+		return b.extendInputObject(ttype)
+	case *Scalar:
+		return b.extendScalar(ttype)
+	}
+	return ttype
+}
+
+// extendAppliedDirectives returns the directives applied to a type of the
+// schema being extended along with those applied by its extensions.
+func (b *schemaBuilder) extendAppliedDirectives(existing AppliedDirectives, name string) AppliedDirectives {
+	return append(append(AppliedDirectives{}, existing...), b.typeAppliedDirectives(name, nil)...)
+}
+
+// extendScalar returns a copy of a scalar with the directives applied by its
+// extensions; scalars without extensions are kept as is.
+func (b *schemaBuilder) extendScalar(scalar *Scalar) *Scalar {
+	if len(b.extensions[scalar.Name()]) == 0 {
+		return scalar
+	}
+	config := scalar.scalarConfig
+	extended := NewScalar(config)
+	b.deferDirectives(func() {
+		extended.scalarConfig.AppliedDirectives = b.extendAppliedDirectives(config.AppliedDirectives, config.Name)
+	})
+	return extended
+}
+
+// extendedTypeRef returns the counterpart of a (wrapped) type of the schema
+// being extended.
+func (b *schemaBuilder) extendedTypeRef(ttype Type) Type {
+	switch ttype := ttype.(type) {
+	case *List:
+		return NewList(b.extendedTypeRef(ttype.OfType))
+	case *NonNull:
+		return NewNonNull(b.extendedTypeRef(ttype.OfType))
+	}
+	return b.namedType(ttype.Name())
+}
+
+func (b *schemaBuilder) extendObject(object *Object) *Object {
+	name := object.Name()
+	extended := NewObject(ObjectConfig{
+		Name:        name,
+		Description: object.PrivateDescription,
+		IsTypeOf:    object.IsTypeOf,
+		Interfaces: InterfacesThunk(func() []*Interface {
+			return b.extendInterfaces(object.Interfaces(), name)
+		}),
+		Fields: FieldsThunk(func() Fields {
+			return b.extendFields(object.Fields(), b.extensionFields(name))
+		}),
+	})
+	b.deferDirectives(func() {
+		extended.typeConfig.AppliedDirectives = b.extendAppliedDirectives(object.AppliedDirectives(), name)
+	})
+	return extended
+}
+
+func (b *schemaBuilder) extendInterface(iface *Interface) *Interface {
+	name := iface.Name()
+	extended := NewInterface(InterfaceConfig{
+		Name:        name,
+		Description: iface.PrivateDescription,
+		ResolveType: extendResolveType(iface.ResolveType),
+		Interfaces: InterfacesThunk(func() []*Interface {
+			return b.extendInterfaces(iface.Interfaces(), name)
+		}),
+		Fields: FieldsThunk(func() Fields {
+			return b.extendFields(iface.Fields(), b.extensionFields(name))
+		}),
+	})
+	b.deferDirectives(func() {
+		extended.typeConfig.AppliedDirectives = b.extendAppliedDirectives(iface.AppliedDirectives(), name)
+	})
+	return extended
+}
+
+func (b *schemaBuilder) extendUnion(union *Union) *Union {
+	name := union.Name()
+	extended := NewUnion(UnionConfig{
+		Name:        name,
+		Description: union.PrivateDescription,
+		ResolveType: extendResolveType(union.ResolveType),
+		Types: UnionTypesThunk(func() []*Object {
+			types := []*Object{}
+			for _, object := range union.Types() {
+				if object, ok := b.namedType(object.Name()).(*Object); ok {
+					types = append(types, object)
+				}
+			}
+			for _, named := range b.extensionUnionMembers(name) {
+				if object, ok := b.mustTypeRef(named).(*Object); ok {
+					types = append(types, object)
+				}
+			}
+			return types
+		}),
+	})
+	b.deferDirectives(func() {
+		extended.typeConfig.AppliedDirectives = b.extendAppliedDirectives(union.AppliedDirectives(), name)
+	})
+	return extended
+}
+
+func (b *schemaBuilder) extendEnum(enum *Enum) *Enum {
+	name := enum.Name()
+	if len(b.extensions[name]) == 0 {
+		return enum
+	}
+	values := EnumValueConfigMap{}
+	for _, value := range enum.Values() {
+		values[value.Name] = &EnumValueConfig{
+			Value:             value.Value,
+			DeprecationReason: value.DeprecationReason,
+			Description:       value.Description,
+			AppliedDirectives: value.AppliedDirectives,
+		}
+	}
+	b.addEnumValues(values, b.extensionEnumValues(name))
+	extended := NewEnum(EnumConfig{
+		Name:        name,
+		Description: enum.Description(),
+		Values:      values,
+	})
+	b.deferEnumValueDirectives(extended, b.extensionEnumValues(name))
+	b.deferDirectives(func() {
+		extended.enumConfig.AppliedDirectives = b.extendAppliedDirectives(enum.AppliedDirectives(), name)
+	})
+	return extended
+}
+
+func (b *schemaBuilder) extendInputObject(inputObject *InputObject) *InputObject {
+	name := inputObject.Name()
+	extended := NewInputObject(InputObjectConfig{
+		Name:        name,
+		Description: inputObject.PrivateDescription,
+		Fields: InputObjectConfigFieldMapThunk(func() InputObjectConfigFieldMap {
+			fields := InputObjectConfigFieldMap{}
+			for fieldName, field := range inputObject.Fields() {
+				fields[fieldName] = &InputObjectFieldConfig{
+					Type:              b.extendedTypeRef(field.Type),
+					DefaultValue:      field.DefaultValue,
+					Description:       field.PrivateDescription,
+					AppliedDirectives: field.AppliedDirectives,
+				}
+			}
+			b.addInputFields(fields, b.extensionInputFields(name))
+			return fields
+		}),
+	})
+	b.deferDirectives(func() {
+		extended.typeConfig.AppliedDirectives = b.extendAppliedDirectives(inputObject.AppliedDirectives(), name)
+	})
+	return extended
+}
+
+// extendInterfaces returns the existing interfaces of an object or interface
+// type along with the interfaces added by extensions.
+func (b *schemaBuilder) extendInterfaces(existing []*Interface, name string) []*Interface {
+	interfaces := []*Interface{}
+	for _, iface := range existing {
+		if iface, ok := b.namedType(iface.Name()).(*Interface); ok {
+			interfaces = append(interfaces, iface)
+		}
+	}
+	return append(interfaces, b.interfaceRefs(b.extensionInterfaces(name))...)
+}
+
+// extendFields returns the existing fields of an object or interface type,
+// keeping their resolvers, along with the fields added by extensions.
+func (b *schemaBuilder) extendFields(existing FieldDefinitionMap, defs []*ast.FieldDefinition) Fields {
+	fields := b.buildFields(defs)
+	for name, field := range existing {
+		fields[name] = &Field{
+			Name:              field.Name,
+			Type:              b.extendedTypeRef(field.Type),
+			Args:              b.extendArgs(field.Args),
+			Resolve:           field.Resolve,
+			Subscribe:         field.Subscribe,
+			DeprecationReason: field.DeprecationReason,
+			Description:       field.Description,
+			AppliedDirectives: field.AppliedDirectives,
+			Timeout:           field.Timeout,
+			Complexity:        field.Complexity,
+		}
+	}
+	return fields
+}
+
+func (b *schemaBuilder) extendArgs(existing []*Argument) FieldConfigArgument {
+	args := FieldConfigArgument{}
+	for _, arg := range existing {
+		args[arg.Name()] = &ArgumentConfig{
+			Type:              b.extendedTypeRef(arg.Type),
+			DefaultValue:      arg.DefaultValue,
+			Description:       arg.Description(),
+			AppliedDirectives: arg.AppliedDirectives,
+		}
+	}
+	return args
+}
+
+// extendResolveType maps the Object types returned by the ResolveTypeFn of an
+// abstract type being extended to their counterparts in the extended schema.
+func extendResolveType(resolveType ResolveTypeFn) ResolveTypeFn {
+	if resolveType == nil {
+		return nil
+	}
+	return func(p ResolveTypeParams) *Object {
+		object := resolveType(p)
+		if object == nil {
+			return nil
+		}
+		if extended, ok := p.Info.Schema.Type(object.Name()).(*Object); ok {
+			return extended
+		}
+		return object
+	}
+}
+
+// extendDirectives returns the directives of the schema being extended along
+// with the directives defined by the document.
+func (b *schemaBuilder) extendDirectives() ([]*Directive, error) {
+	directives := []*Directive{}
+	existing := map[string]bool{}
+	for _, directive := range b.schema.Directives() {
+		existing[directive.Name] = true
+		if isSpecifiedDirective(directive) {
+			directives = append(directives, directive)
+			continue
+		}
+		directive = NewDirective(DirectiveConfig{
+			Name:         directive.Name,
+			Description:  directive.Description,
+			Locations:    directive.Locations,
+			Args:         b.extendArgs(directive.Args),
+			IsRepeatable: directive.IsRepeatable,
+			Resolve:      directive.Resolve,
+		})
+		if directive.err != nil {
+			return nil, directive.err
+		}
+		directives = append(directives, directive)
+	}
+	for _, def := range b.directiveDefs {
+		if existing[def.Name.Value] {
+			return nil, newBuildError(fmt.Sprintf(`Directive "%v" already exists in the schema. It cannot be redefined.`, def.Name.Value), def.Name)
+		}
+	}
+	return b.buildDirectives(directives)
+}
+
+// extendRootTypes returns the root operation types of the schema being
+// extended, as changed by the schema extensions of the document.
+func (b *schemaBuilder) extendRootTypes() (SchemaConfig, error) {
+	config := SchemaConfig{}
+	if ttype := b.schema.QueryType(); ttype != nil {
+		config.Query, _ = b.namedType(ttype.Name()).(*Object)
+	}
+	if ttype := b.schema.MutationType(); ttype != nil {
+		config.Mutation, _ = b.namedType(ttype.Name()).(*Object)
+	}
+	if ttype := b.schema.SubscriptionType(); ttype != nil {
+		config.Subscription, _ = b.namedType(ttype.Name()).(*Object)
+	}
+	for _, extension := range b.schemaExtensions {
+		if err := b.addRootTypes(&config, extension.OperationTypes); err != nil {
+			return config, err
+		}
+	}
+	return config, nil
+}
+
+// extendedTypes returns the types of the extended schema, those of the schema
+// being extended followed by the types defined by the document, each sorted
+// by name to keep schema construction deterministic.
+func (b *schemaBuilder) extendedTypes() []Type {
+	names := []string{}
+	for name := range b.schema.TypeMap() {
+		if !strings.HasPrefix(name, "__") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	types := []Type{}
+	for _, name := range names {
+		types = append(types, b.namedType(name))
+	}
+	return append(types, b.builtTypes()...)
+}
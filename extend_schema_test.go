@@ -0,0 +1,336 @@
+package graphql_test
+
+import (
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/location"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+func extendSchemaOrFail(t *testing.T, schema graphql.Schema, sdl string) graphql.Schema {
+	doc, err := parser.Parse(parser.ParseParams{Source: sdl})
+	if err != nil {
+		t.Fatalf("unexpected error parsing extensions: %v", err)
+	}
+	extended, err := graphql.ExtendSchema(schema, doc)
+	if err != nil {
+		t.Fatalf("unexpected error extending schema: %v", err)
+	}
+	return extended
+}
+
+func TestExtendSchema_KeepsExistingResolvers(t *testing.T) {
+	schema := extendSchemaOrFail(t, testutil.StarWarsSchema, `
+		extend type Query {
+			greeting: String
+		}
+
+		extend type Human {
+			nickname: String
+		}
+	`)
+	err := graphql.BindResolvers(&schema, graphql.Resolvers{
+		"Query": map[string]interface{}{
+			"greeting": func(p graphql.ResolveParams) (interface{}, error) {
+				return "Hello", nil
+			},
+		},
+		"Human": map[string]interface{}{
+			"nickname": func(p graphql.ResolveParams) (interface{}, error) {
+				return "Farm boy", nil
+			},
+		},
+	}, graphql.BindOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error binding resolvers: %v", err)
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema: schema,
+		RequestString: `{
+			greeting
+			hero(episode: EMPIRE) {
+				name
+				... on Human {
+					nickname
+				}
+			}
+		}`,
+	})
+	expected := &graphql.Result{
+		Data: map[string]interface{}{
+			"greeting": "Hello",
+			"hero": map[string]interface{}{
+				"name":     "Luke Skywalker",
+				"nickname": "Farm boy",
+			},
+		},
+	}
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
+
+func TestExtendSchema_DoesNotModifyOriginalSchema(t *testing.T) {
+	expected := graphql.PrintSchema(testutil.StarWarsSchema)
+	extendSchemaOrFail(t, testutil.StarWarsSchema, `
+		extend type Query {
+			greeting: String
+		}
+
+		extend enum Episode {
+			PHANTOM
+		}
+	`)
+	if printed := graphql.PrintSchema(testutil.StarWarsSchema); printed != expected {
+		t.Fatalf("Unexpected schema, Diff: %v", testutil.Diff(expected, printed))
+	}
+}
+
+func TestExtendSchema_ExtendsAllKindsOfTypes(t *testing.T) {
+	schema := buildSchemaOrFail(t, `
+		type Query {
+			node: Node
+			search: SearchResult
+			color(filter: Filter): Color
+		}
+
+		interface Node {
+			id: ID
+		}
+
+		type Photo implements Node {
+			id: ID
+		}
+
+		union SearchResult = Photo
+
+		enum Color {
+			RED
+		}
+
+		input Filter {
+			name: String
+		}
+	`)
+	extended := extendSchemaOrFail(t, schema, `
+		type Article implements Node {
+			id: ID
+			url: String
+		}
+
+		type Mutation {
+			publish(id: ID!): Article
+		}
+
+		directive @cached(ttl: Int) on FIELD
+
+		extend schema {
+			mutation: Mutation
+		}
+
+		extend interface Node {
+			url: String
+		}
+
+		extend type Photo {
+			url: String
+		}
+
+		extend union SearchResult = Article
+
+		extend enum Color {
+			GREEN
+		}
+
+		extend input Filter {
+			limit: Int
+		}
+	`)
+	expected := `directive @cached(ttl: Int) on FIELD
+
+type Article implements Node {
+  id: ID
+  url: String
+}
+
+enum Color {
+  GREEN
+  RED
+}
+
+input Filter {
+  limit: Int
+  name: String
+}
+
+type Mutation {
+  publish(id: ID!): Article
+}
+
+interface Node {
+  id: ID
+  url: String
+}
+
+type Photo implements Node {
+  id: ID
+  url: String
+}
+
+type Query {
+  color(filter: Filter): Color
+  node: Node
+  search: SearchResult
+}
+
+union SearchResult = Photo | Article
+`
+	if printed := graphql.PrintSchema(extended); printed != expected {
+		t.Fatalf("Unexpected schema, Diff: %v", testutil.Diff(expected, printed))
+	}
+}
+
+func TestExtendSchema_KeepsAndAddsAppliedDirectives(t *testing.T) {
+	schema := buildSchemaOrFail(t, `
+		directive @key(fields: String!) repeatable on OBJECT | INTERFACE
+
+		directive @tag(name: String!) on FIELD_DEFINITION | ENUM_VALUE | SCALAR
+
+		type Query {
+			user: User @tag(name: "entry")
+			role: Role
+			today: Date
+		}
+
+		type User @key(fields: "id") {
+			id: ID!
+		}
+
+		enum Role {
+			ADMIN
+		}
+
+		scalar Date
+	`)
+	extended := extendSchemaOrFail(t, schema, `
+		extend type User @key(fields: "email") {
+			email: String @tag(name: "pii")
+		}
+
+		extend enum Role {
+			GUEST @tag(name: "anonymous")
+		}
+
+		extend scalar Date @tag(name: "iso8601")
+	`)
+	expected := `directive @key(fields: String!) repeatable on OBJECT | INTERFACE
+
+directive @tag(name: String!) on FIELD_DEFINITION | ENUM_VALUE | SCALAR
+
+scalar Date @tag(name: "iso8601")
+
+type Query {
+  role: Role
+  today: Date
+  user: User @tag(name: "entry")
+}
+
+enum Role {
+  ADMIN
+  GUEST @tag(name: "anonymous")
+}
+
+type User @key(fields: "id") @key(fields: "email") {
+  email: String @tag(name: "pii")
+  id: ID!
+}
+`
+	if printed := graphql.PrintSchema(extended); printed != expected {
+		t.Fatalf("Unexpected schema, Diff: %v", testutil.Diff(expected, printed))
+	}
+}
+
+func TestExtendSchema_ReportsErrors(t *testing.T) {
+	tests := []struct {
+		sdl      string
+		expected gqlerrors.FormattedError
+	}{
+		{
+			sdl: `extend type Unknown {
+  foo: String
+}`,
+			expected: gqlerrors.FormattedError{
+				Message:   `Cannot extend type "Unknown" because it is not defined.`,
+				Locations: []location.SourceLocation{{Line: 1, Column: 13}},
+			},
+		},
+		{
+			sdl: `extend union Character = Human`,
+			expected: gqlerrors.FormattedError{
+				Message:   `Cannot extend non-union type "Character".`,
+				Locations: []location.SourceLocation{{Line: 1, Column: 14}},
+			},
+		},
+		{
+			sdl: `extend type Human {
+  name: String
+}`,
+			expected: gqlerrors.FormattedError{
+				Message:   `Field "Human.name" already exists in the schema. It cannot also be defined in this type extension.`,
+				Locations: []location.SourceLocation{{Line: 2, Column: 3}},
+			},
+		},
+		{
+			sdl: `extend enum Episode {
+  JEDI
+}`,
+			expected: gqlerrors.FormattedError{
+				Message:   `Enum value "Episode.JEDI" already exists in the schema. It cannot also be defined in this type extension.`,
+				Locations: []location.SourceLocation{{Line: 2, Column: 3}},
+			},
+		},
+		{
+			sdl: `type Human {
+  id: ID
+}`,
+			expected: gqlerrors.FormattedError{
+				Message:   `Type "Human" already exists in the schema. It cannot also be defined in this type definition.`,
+				Locations: []location.SourceLocation{{Line: 1, Column: 6}},
+			},
+		},
+		{
+			sdl: `directive @include(if: Boolean!) on FIELD`,
+			expected: gqlerrors.FormattedError{
+				Message:   `Directive "include" already exists in the schema. It cannot be redefined.`,
+				Locations: []location.SourceLocation{{Line: 1, Column: 12}},
+			},
+		},
+		{
+			sdl: `extend schema {
+  query: Human
+}`,
+			expected: gqlerrors.FormattedError{
+				Message:   `Must provide only one query type in schema.`,
+				Locations: []location.SourceLocation{{Line: 2, Column: 3}},
+			},
+		},
+	}
+	for _, test := range tests {
+		doc, err := parser.Parse(parser.ParseParams{Source: test.sdl})
+		if err != nil {
+			t.Fatalf("unexpected error parsing extensions: %v", err)
+		}
+		_, err = graphql.ExtendSchema(testutil.StarWarsSchema, doc)
+		if err == nil {
+			t.Fatalf("expected error %q", test.expected.Message)
+		}
+		formatted := gqlerrors.FormatError(err)
+		if !testutil.EqualFormattedError(test.expected, formatted) {
+			t.Fatalf("Unexpected error, Diff: %v", testutil.Diff(test.expected, formatted))
+		}
+	}
+}
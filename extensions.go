@@ -207,9 +207,9 @@ func handleExtensionsResolveFieldDidStart(exts []Extension, p *executionContext,
 					errs = append(errs, gqlerrors.FormatError(fmt.Errorf("%s.ResolveFieldDidStart: %v", ext.Name(), r.(error))))
 				}
 			}()
-			ctx, finishFn = ext.ResolveFieldDidStart(p.Context, i)
+			ctx, finishFn = ext.ResolveFieldDidStart(p.context(), i)
 			// update context
-			p.Context = ctx
+			p.setContext(ctx)
 			fs[ext.Name()] = finishFn
 		}()
 	}
@@ -62,7 +62,7 @@ func TestExtensionInitPanic(t *testing.T) {
 			gqlerrors.FormatError(fmt.Errorf("%s.Init: %v", ext.Name(), errors.New("test error"))),
 		},
 	}
-	if !reflect.DeepEqual(expected, result) {
+	if !testutil.EqualResults(expected, result) || !reflect.DeepEqual(expected.Extensions, result.Extensions) {
 		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
 	}
 }
@@ -93,7 +93,7 @@ func TestExtensionParseDidStartPanic(t *testing.T) {
 			gqlerrors.FormatError(fmt.Errorf("%s.ParseDidStart: %v", ext.Name(), errors.New("test error"))),
 		},
 	}
-	if !reflect.DeepEqual(expected, result) {
+	if !testutil.EqualResults(expected, result) || !reflect.DeepEqual(expected.Extensions, result.Extensions) {
 		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
 	}
 }
@@ -121,7 +121,7 @@ func TestExtensionParseFinishFuncPanic(t *testing.T) {
 			gqlerrors.FormatError(fmt.Errorf("%s.ParseFinishFunc: %v", ext.Name(), errors.New("test error"))),
 		},
 	}
-	if !reflect.DeepEqual(expected, result) {
+	if !testutil.EqualResults(expected, result) || !reflect.DeepEqual(expected.Extensions, result.Extensions) {
 		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
 	}
 }
@@ -152,7 +152,7 @@ func TestExtensionValidationDidStartPanic(t *testing.T) {
 			gqlerrors.FormatError(fmt.Errorf("%s.ValidationDidStart: %v", ext.Name(), errors.New("test error"))),
 		},
 	}
-	if !reflect.DeepEqual(expected, result) {
+	if !testutil.EqualResults(expected, result) || !reflect.DeepEqual(expected.Extensions, result.Extensions) {
 		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
 	}
 }
@@ -180,7 +180,7 @@ func TestExtensionValidationFinishFuncPanic(t *testing.T) {
 			gqlerrors.FormatError(fmt.Errorf("%s.ValidationFinishFunc: %v", ext.Name(), errors.New("test error"))),
 		},
 	}
-	if !reflect.DeepEqual(expected, result) {
+	if !testutil.EqualResults(expected, result) || !reflect.DeepEqual(expected.Extensions, result.Extensions) {
 		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
 	}
 }
@@ -211,7 +211,7 @@ func TestExtensionExecutionDidStartPanic(t *testing.T) {
 			gqlerrors.FormatError(fmt.Errorf("%s.ExecutionDidStart: %v", ext.Name(), errors.New("test error"))),
 		},
 	}
-	if !reflect.DeepEqual(expected, result) {
+	if !testutil.EqualResults(expected, result) || !reflect.DeepEqual(expected.Extensions, result.Extensions) {
 		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
 	}
 }
@@ -242,7 +242,7 @@ func TestExtensionExecutionFinishFuncPanic(t *testing.T) {
 		},
 	}
 
-	if !reflect.DeepEqual(expected, result) {
+	if !testutil.EqualResults(expected, result) || !reflect.DeepEqual(expected.Extensions, result.Extensions) {
 		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
 	}
 }
@@ -276,7 +276,7 @@ func TestExtensionResolveFieldDidStartPanic(t *testing.T) {
 		},
 	}
 
-	if !reflect.DeepEqual(expected, result) {
+	if !testutil.EqualResults(expected, result) || !reflect.DeepEqual(expected.Extensions, result.Extensions) {
 		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
 	}
 }
@@ -307,7 +307,7 @@ func TestExtensionResolveFieldFinishFuncPanic(t *testing.T) {
 		},
 	}
 
-	if !reflect.DeepEqual(expected, result) {
+	if !testutil.EqualResults(expected, result) || !reflect.DeepEqual(expected.Extensions, result.Extensions) {
 		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
 	}
 }
@@ -372,7 +372,7 @@ func TestExtensionGetResultPanic(t *testing.T) {
 		Extensions: make(map[string]interface{}),
 	}
 
-	if !reflect.DeepEqual(expected, result) {
+	if !testutil.EqualResults(expected, result) || !reflect.DeepEqual(expected.Extensions, result.Extensions) {
 		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
 	}
 }
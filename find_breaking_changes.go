@@ -0,0 +1,464 @@
+package graphql
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/graphql-go/graphql/language/printer"
+)
+
+// BreakingChangeType is the kind of a change which breaks existing clients.
+type BreakingChangeType string
+
+// Breaking change kinds.
+const (
+	BreakingChangeFieldChangedKind           BreakingChangeType = "FIELD_CHANGED_KIND"
+	BreakingChangeFieldRemoved               BreakingChangeType = "FIELD_REMOVED"
+	BreakingChangeTypeChangedKind            BreakingChangeType = "TYPE_CHANGED_KIND"
+	BreakingChangeTypeRemoved                BreakingChangeType = "TYPE_REMOVED"
+	BreakingChangeTypeRemovedFromUnion       BreakingChangeType = "TYPE_REMOVED_FROM_UNION"
+	BreakingChangeValueRemovedFromEnum       BreakingChangeType = "VALUE_REMOVED_FROM_ENUM"
+	BreakingChangeArgRemoved                 BreakingChangeType = "ARG_REMOVED"
+	BreakingChangeArgChangedKind             BreakingChangeType = "ARG_CHANGED_KIND"
+	BreakingChangeNonNullArgAdded            BreakingChangeType = "NON_NULL_ARG_ADDED"
+	BreakingChangeNonNullInputFieldAdded     BreakingChangeType = "NON_NULL_INPUT_FIELD_ADDED"
+	BreakingChangeInterfaceRemovedFromObject BreakingChangeType = "INTERFACE_REMOVED_FROM_OBJECT"
+	BreakingChangeDirectiveRemoved           BreakingChangeType = "DIRECTIVE_REMOVED"
+	BreakingChangeDirectiveArgRemoved        BreakingChangeType = "DIRECTIVE_ARG_REMOVED"
+	BreakingChangeNonNullDirectiveArgAdded   BreakingChangeType = "NON_NULL_DIRECTIVE_ARG_ADDED"
+	BreakingChangeDirectiveLocationRemoved   BreakingChangeType = "DIRECTIVE_LOCATION_REMOVED"
+	BreakingChangeDirectiveRepeatableRemoved BreakingChangeType = "DIRECTIVE_REPEATABLE_REMOVED"
+)
+
+// DangerousChangeType is the kind of a change which may change the behavior
+// of existing clients without breaking them.
+type DangerousChangeType string
+
+// Dangerous change kinds.
+const (
+	DangerousChangeArgDefaultValueChange   DangerousChangeType = "ARG_DEFAULT_VALUE_CHANGE"
+	DangerousChangeValueAddedToEnum        DangerousChangeType = "VALUE_ADDED_TO_ENUM"
+	DangerousChangeInterfaceAddedToObject  DangerousChangeType = "INTERFACE_ADDED_TO_OBJECT"
+	DangerousChangeTypeAddedToUnion        DangerousChangeType = "TYPE_ADDED_TO_UNION"
+	DangerousChangeNullableInputFieldAdded DangerousChangeType = "NULLABLE_INPUT_FIELD_ADDED"
+	DangerousChangeNullableArgAdded        DangerousChangeType = "NULLABLE_ARG_ADDED"
+)
+
+// BreakingChange describes a change which breaks existing clients.
+type BreakingChange struct {
+	Type        BreakingChangeType
+	Description string
+}
+
+// DangerousChange describes a change which may change the behavior of
+// existing clients without breaking them.
+type DangerousChange struct {
+	Type        DangerousChangeType
+	Description string
+}
+
+// FindBreakingChanges returns the changes from the old schema to the new
+// schema which break existing clients, ordered by type and field name.
+func FindBreakingChanges(oldSchema, newSchema Schema) []BreakingChange {
+	d := newSchemaDiff(&oldSchema, &newSchema)
+	d.diff()
+	return d.breaking
+}
+
+// FindDangerousChanges returns the changes from the old schema to the new
+// schema which may change the behavior of existing clients, ordered by type
+// and field name.
+func FindDangerousChanges(oldSchema, newSchema Schema) []DangerousChange {
+	d := newSchemaDiff(&oldSchema, &newSchema)
+	d.diff()
+	return d.dangerous
+}
+
+type schemaDiff struct {
+	oldSchema *Schema
+	newSchema *Schema
+	breaking  []BreakingChange
+	dangerous []DangerousChange
+}
+
+func newSchemaDiff(oldSchema, newSchema *Schema) *schemaDiff {
+	return &schemaDiff{
+		oldSchema: oldSchema,
+		newSchema: newSchema,
+		breaking:  []BreakingChange{},
+		dangerous: []DangerousChange{},
+	}
+}
+
+func (d *schemaDiff) addBreaking(changeType BreakingChangeType, format string, a ...interface{}) {
+	d.breaking = append(d.breaking, BreakingChange{
+		Type:        changeType,
+		Description: fmt.Sprintf(format, a...),
+	})
+}
+
+func (d *schemaDiff) addDangerous(changeType DangerousChangeType, format string, a ...interface{}) {
+	d.dangerous = append(d.dangerous, DangerousChange{
+		Type:        changeType,
+		Description: fmt.Sprintf(format, a...),
+	})
+}
+
+func (d *schemaDiff) diff() {
+	oldTypeMap := d.oldSchema.TypeMap()
+	newTypeMap := d.newSchema.TypeMap()
+	typeNames := []string{}
+	for typeName := range oldTypeMap {
+		typeNames = append(typeNames, typeName)
+	}
+	sort.Strings(typeNames)
+
+	for _, typeName := range typeNames {
+		oldType := oldTypeMap[typeName]
+		newType, ok := newTypeMap[typeName]
+		if !ok {
+			d.addBreaking(BreakingChangeTypeRemoved, "%v was removed.", typeName)
+			continue
+		}
+		if typeKindName(oldType) != typeKindName(newType) {
+			d.addBreaking(BreakingChangeTypeChangedKind, "%v changed from %v to %v.",
+				typeName, typeKindName(oldType), typeKindName(newType))
+			continue
+		}
+		switch oldType := oldType.(type) {
+		case *Object:
+			newType := newType.(*Object)
+			d.diffInterfaces(oldType, newType)
+			d.diffFields(typeName, oldType.Fields(), newType.Fields())
+		case *Interface:
+			newType := newType.(*Interface)
+			d.diffInterfaces(oldType, newType)
+			d.diffFields(typeName, oldType.Fields(), newType.Fields())
+		case *Union:
+			d.diffUnion(oldType, newType.(*Union))
+		case *Enum:
+			d.diffEnum(oldType, newType.(*Enum))
+		case *InputObject:
+			d.diffInputObject(oldType, newType.(*InputObject))
+		}
+	}
+
+	d.diffDirectives()
+}
+
+func typeKindName(ttype Type) string {
+	switch ttype.(type) {
+	case *Scalar:
+		return "a Scalar type"
+	case *Object:
+		return "an Object type"
+	case *Interface:
+		return "an Interface type"
+	case *Union:
+		return "a Union type"
+	case *Enum:
+		return "an Enum type"
+	case *InputObject:
+		return "an Input type"
+	}
+	return fmt.Sprintf("%T", ttype)
+}
+
+func (d *schemaDiff) diffInterfaces(oldType, newType implementingType) {
+	oldInterfaces := map[string]bool{}
+	for _, iface := range oldType.Interfaces() {
+		oldInterfaces[iface.Name()] = true
+	}
+	newInterfaces := map[string]bool{}
+	for _, iface := range newType.Interfaces() {
+		newInterfaces[iface.Name()] = true
+	}
+	for _, iface := range oldType.Interfaces() {
+		if !newInterfaces[iface.Name()] {
+			d.addBreaking(BreakingChangeInterfaceRemovedFromObject, "%v no longer implements interface %v.",
+				oldType.Name(), iface.Name())
+		}
+	}
+	for _, iface := range newType.Interfaces() {
+		if !oldInterfaces[iface.Name()] {
+			d.addDangerous(DangerousChangeInterfaceAddedToObject, "%v added to interfaces implemented by %v.",
+				iface.Name(), newType.Name())
+		}
+	}
+}
+
+func (d *schemaDiff) diffFields(typeName string, oldFields, newFields FieldDefinitionMap) {
+	fieldNames := []string{}
+	for fieldName := range oldFields {
+		fieldNames = append(fieldNames, fieldName)
+	}
+	sort.Strings(fieldNames)
+
+	for _, fieldName := range fieldNames {
+		oldField := oldFields[fieldName]
+		newField, ok := newFields[fieldName]
+		if !ok {
+			d.addBreaking(BreakingChangeFieldRemoved, "%v.%v was removed.", typeName, fieldName)
+			continue
+		}
+		if !isChangeSafeForOutputType(oldField.Type, newField.Type) {
+			d.addBreaking(BreakingChangeFieldChangedKind, "%v.%v changed type from %v to %v.",
+				typeName, fieldName, oldField.Type, newField.Type)
+		}
+		d.diffArgs(fmt.Sprintf("%v.%v", typeName, fieldName), oldField.Args, newField.Args)
+	}
+}
+
+func (d *schemaDiff) diffArgs(coordinate string, oldArgs, newArgs []*Argument) {
+	newArgMap := map[string]*Argument{}
+	for _, arg := range newArgs {
+		newArgMap[arg.Name()] = arg
+	}
+	oldArgMap := map[string]*Argument{}
+	for _, arg := range sortedArgs(oldArgs) {
+		oldArgMap[arg.Name()] = arg
+		newArg, ok := newArgMap[arg.Name()]
+		if !ok {
+			d.addBreaking(BreakingChangeArgRemoved, "%v arg %v was removed.", coordinate, arg.Name())
+			continue
+		}
+		if !isChangeSafeForInputType(arg.Type, newArg.Type) {
+			d.addBreaking(BreakingChangeArgChangedKind, "%v arg %v has changed type from %v to %v.",
+				coordinate, arg.Name(), arg.Type, newArg.Type)
+		} else if arg.DefaultValue != nil && printDefaultValue(arg.DefaultValue, arg.Type) != printDefaultValue(newArg.DefaultValue, newArg.Type) {
+			d.addDangerous(DangerousChangeArgDefaultValueChange, "%v arg %v has changed defaultValue.",
+				coordinate, arg.Name())
+		}
+	}
+	for _, arg := range sortedArgs(newArgs) {
+		if _, ok := oldArgMap[arg.Name()]; ok {
+			continue
+		}
+		if _, ok := arg.Type.(*NonNull); ok && arg.DefaultValue == nil {
+			d.addBreaking(BreakingChangeNonNullArgAdded, "A non-null arg %v on %v was added.", arg.Name(), coordinate)
+		} else {
+			d.addDangerous(DangerousChangeNullableArgAdded, "A nullable arg %v on %v was added.", arg.Name(), coordinate)
+		}
+	}
+}
+
+func sortedArgs(args []*Argument) []*Argument {
+	args = append([]*Argument{}, args...)
+	sort.Slice(args, func(i, j int) bool {
+		return args[i].Name() < args[j].Name()
+	})
+	return args
+}
+
+func printDefaultValue(value interface{}, ttype Input) string {
+	if value == nil {
+		return ""
+	}
+	valueAST := astFromValue(value, ttype)
+	if valueAST == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", printer.Print(valueAST))
+}
+
+func (d *schemaDiff) diffUnion(oldType, newType *Union) {
+	oldMembers := map[string]bool{}
+	for _, object := range oldType.Types() {
+		oldMembers[object.Name()] = true
+	}
+	newMembers := map[string]bool{}
+	for _, object := range newType.Types() {
+		newMembers[object.Name()] = true
+	}
+	for _, object := range oldType.Types() {
+		if !newMembers[object.Name()] {
+			d.addBreaking(BreakingChangeTypeRemovedFromUnion, "%v was removed from union type %v.",
+				object.Name(), oldType.Name())
+		}
+	}
+	for _, object := range newType.Types() {
+		if !oldMembers[object.Name()] {
+			d.addDangerous(DangerousChangeTypeAddedToUnion, "%v was added to union type %v.",
+				object.Name(), newType.Name())
+		}
+	}
+}
+
+func (d *schemaDiff) diffEnum(oldType, newType *Enum) {
+	oldValues := map[string]bool{}
+	for _, value := range oldType.Values() {
+		oldValues[value.Name] = true
+	}
+	newValues := map[string]bool{}
+	for _, value := range newType.Values() {
+		newValues[value.Name] = true
+	}
+	for _, value := range sortedEnumValues(oldType) {
+		if !newValues[value.Name] {
+			d.addBreaking(BreakingChangeValueRemovedFromEnum, "%v was removed from enum type %v.",
+				value.Name, oldType.Name())
+		}
+	}
+	for _, value := range sortedEnumValues(newType) {
+		if !oldValues[value.Name] {
+			d.addDangerous(DangerousChangeValueAddedToEnum, "%v was added to enum type %v.",
+				value.Name, newType.Name())
+		}
+	}
+}
+
+func sortedEnumValues(enum *Enum) []*EnumValueDefinition {
+	values := append([]*EnumValueDefinition{}, enum.Values()...)
+	sort.Slice(values, func(i, j int) bool {
+		return values[i].Name < values[j].Name
+	})
+	return values
+}
+
+func (d *schemaDiff) diffInputObject(oldType, newType *InputObject) {
+	oldFields := oldType.Fields()
+	newFields := newType.Fields()
+
+	fieldNames := []string{}
+	for fieldName := range oldFields {
+		fieldNames = append(fieldNames, fieldName)
+	}
+	sort.Strings(fieldNames)
+	for _, fieldName := range fieldNames {
+		newField, ok := newFields[fieldName]
+		if !ok {
+			d.addBreaking(BreakingChangeFieldRemoved, "%v.%v was removed.", oldType.Name(), fieldName)
+			continue
+		}
+		if oldField := oldFields[fieldName]; !isChangeSafeForInputType(oldField.Type, newField.Type) {
+			d.addBreaking(BreakingChangeFieldChangedKind, "%v.%v changed type from %v to %v.",
+				oldType.Name(), fieldName, oldField.Type, newField.Type)
+		}
+	}
+
+	fieldNames = []string{}
+	for fieldName := range newFields {
+		fieldNames = append(fieldNames, fieldName)
+	}
+	sort.Strings(fieldNames)
+	for _, fieldName := range fieldNames {
+		if _, ok := oldFields[fieldName]; ok {
+			continue
+		}
+		newField := newFields[fieldName]
+		if _, ok := newField.Type.(*NonNull); ok && newField.DefaultValue == nil {
+			d.addBreaking(BreakingChangeNonNullInputFieldAdded, "A non-null field %v on input type %v was added.",
+				fieldName, newType.Name())
+		} else {
+			d.addDangerous(DangerousChangeNullableInputFieldAdded, "A nullable field %v on input type %v was added.",
+				fieldName, newType.Name())
+		}
+	}
+}
+
+func (d *schemaDiff) diffDirectives() {
+	newDirectives := map[string]*Directive{}
+	for _, directive := range d.newSchema.Directives() {
+		newDirectives[directive.Name] = directive
+	}
+	oldDirectives := append([]*Directive{}, d.oldSchema.Directives()...)
+	sort.Slice(oldDirectives, func(i, j int) bool {
+		return oldDirectives[i].Name < oldDirectives[j].Name
+	})
+
+	for _, oldDirective := range oldDirectives {
+		newDirective, ok := newDirectives[oldDirective.Name]
+		if !ok {
+			d.addBreaking(BreakingChangeDirectiveRemoved, "%v was removed.", oldDirective.Name)
+			continue
+		}
+
+		oldArgs := map[string]bool{}
+		for _, arg := range sortedArgs(oldDirective.Args) {
+			oldArgs[arg.Name()] = true
+		}
+		newArgs := map[string]bool{}
+		for _, arg := range sortedArgs(newDirective.Args) {
+			newArgs[arg.Name()] = true
+			if _, ok := arg.Type.(*NonNull); ok && !oldArgs[arg.Name()] && arg.DefaultValue == nil {
+				d.addBreaking(BreakingChangeNonNullDirectiveArgAdded, "A non-null arg %v on directive %v was added.",
+					arg.Name(), newDirective.Name)
+			}
+		}
+		for _, arg := range sortedArgs(oldDirective.Args) {
+			if !newArgs[arg.Name()] {
+				d.addBreaking(BreakingChangeDirectiveArgRemoved, "%v was removed from %v.", arg.Name(), oldDirective.Name)
+			}
+		}
+
+		if oldDirective.IsRepeatable && !newDirective.IsRepeatable {
+			d.addBreaking(BreakingChangeDirectiveRepeatableRemoved, "Repeatable flag was removed from %v.", oldDirective.Name)
+		}
+
+		newLocations := map[string]bool{}
+		for _, location := range newDirective.Locations {
+			newLocations[location] = true
+		}
+		for _, location := range oldDirective.Locations {
+			if !newLocations[location] {
+				d.addBreaking(BreakingChangeDirectiveLocationRemoved, "%v was removed from %v.", location, oldDirective.Name)
+			}
+		}
+	}
+}
+
+// isChangeSafeForOutputType reports whether a field of the old type may
+// return the new type, which holds when the new type is equal to the old
+// type or is a non-null variant of it.
+//
+// Named types are compared by name as the types belong to different schemas.
+func isChangeSafeForOutputType(oldType, newType Type) bool {
+	switch oldType := oldType.(type) {
+	case *List:
+		if newType, ok := newType.(*List); ok {
+			return isChangeSafeForOutputType(oldType.OfType, newType.OfType)
+		}
+	case *NonNull:
+		if newType, ok := newType.(*NonNull); ok {
+			return isChangeSafeForOutputType(oldType.OfType, newType.OfType)
+		}
+		return false
+	default:
+		if isNamedType(newType) && oldType.Name() == newType.Name() {
+			return true
+		}
+	}
+	if newType, ok := newType.(*NonNull); ok {
+		return isChangeSafeForOutputType(oldType, newType.OfType)
+	}
+	return false
+}
+
+// isChangeSafeForInputType reports whether an argument or input field of the
+// old type may accept the new type, which holds when the new type is equal to
+// the old type or is a nullable variant of it.
+//
+// Named types are compared by name as the types belong to different schemas.
+func isChangeSafeForInputType(oldType, newType Type) bool {
+	switch oldType := oldType.(type) {
+	case *List:
+		if newType, ok := newType.(*List); ok {
+			return isChangeSafeForInputType(oldType.OfType, newType.OfType)
+		}
+		return false
+	case *NonNull:
+		if newType, ok := newType.(*NonNull); ok {
+			return isChangeSafeForInputType(oldType.OfType, newType.OfType)
+		}
+		return isChangeSafeForInputType(oldType.OfType, newType)
+	}
+	return isNamedType(newType) && oldType.Name() == newType.Name()
+}
+
+func isNamedType(ttype Type) bool {
+	switch ttype.(type) {
+	case *List, *NonNull:
+		return false
+	}
+	return ttype != nil
+}
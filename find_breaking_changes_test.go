@@ -0,0 +1,161 @@
+package graphql_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+const findChangesOldSDL = `
+	directive @cached(ttl: Int, scope: String) repeatable on FIELD | QUERY
+	directive @removed on FIELD
+
+	type Query {
+		user(id: ID!, verbose: Boolean = false): User
+		users(first: Int = 10): [User]
+		search: SearchResult
+		removedField: String
+		nonNullable: String!
+		nullable: String
+	}
+
+	interface Node {
+		id: ID!
+	}
+
+	type User implements Node {
+		id: ID!
+		name: String
+	}
+
+	type Photo {
+		url: String
+	}
+
+	type Post {
+		title: String
+	}
+
+	union SearchResult = User | Photo
+
+	enum Role {
+		ADMIN
+		GUEST
+	}
+
+	input UserInput {
+		name: String
+		age: Int!
+	}
+
+	scalar Removed
+	scalar ChangesKind
+`
+
+const findChangesNewSDL = `
+	directive @cached(ttl: Int, region: String!) on FIELD
+
+	type Query {
+		user(id: String, verbose: Boolean = true, limit: Int!): User
+		users(first: Int = 10, after: String): [User]
+		search: SearchResult
+		nonNullable: String
+		nullable: String!
+	}
+
+	interface Node {
+		id: ID!
+	}
+
+	interface Entity {
+		id: ID!
+	}
+
+	type User implements Entity {
+		id: ID!
+		name: String
+	}
+
+	type Photo {
+		url: String
+	}
+
+	type Post {
+		title: String
+	}
+
+	union SearchResult = User | Post
+
+	enum Role {
+		ADMIN
+		USER
+	}
+
+	input UserInput {
+		name: Int
+		age: Int
+		email: String!
+		nickname: String
+	}
+
+	type ChangesKind {
+		value: String
+	}
+`
+
+func TestFindBreakingChanges(t *testing.T) {
+	oldSchema := buildSchemaOrFail(t, findChangesOldSDL)
+	newSchema := buildSchemaOrFail(t, findChangesNewSDL)
+
+	expected := []graphql.BreakingChange{
+		{Type: graphql.BreakingChangeTypeChangedKind, Description: "ChangesKind changed from a Scalar type to an Object type."},
+		{Type: graphql.BreakingChangeFieldChangedKind, Description: "Query.nonNullable changed type from String! to String."},
+		{Type: graphql.BreakingChangeFieldRemoved, Description: "Query.removedField was removed."},
+		{Type: graphql.BreakingChangeArgChangedKind, Description: "Query.user arg id has changed type from ID! to String."},
+		{Type: graphql.BreakingChangeNonNullArgAdded, Description: "A non-null arg limit on Query.user was added."},
+		{Type: graphql.BreakingChangeTypeRemoved, Description: "Removed was removed."},
+		{Type: graphql.BreakingChangeValueRemovedFromEnum, Description: "GUEST was removed from enum type Role."},
+		{Type: graphql.BreakingChangeTypeRemovedFromUnion, Description: "Photo was removed from union type SearchResult."},
+		{Type: graphql.BreakingChangeInterfaceRemovedFromObject, Description: "User no longer implements interface Node."},
+		{Type: graphql.BreakingChangeFieldChangedKind, Description: "UserInput.name changed type from String to Int."},
+		{Type: graphql.BreakingChangeNonNullInputFieldAdded, Description: "A non-null field email on input type UserInput was added."},
+		{Type: graphql.BreakingChangeNonNullDirectiveArgAdded, Description: "A non-null arg region on directive cached was added."},
+		{Type: graphql.BreakingChangeDirectiveArgRemoved, Description: "scope was removed from cached."},
+		{Type: graphql.BreakingChangeDirectiveRepeatableRemoved, Description: "Repeatable flag was removed from cached."},
+		{Type: graphql.BreakingChangeDirectiveLocationRemoved, Description: "QUERY was removed from cached."},
+		{Type: graphql.BreakingChangeDirectiveRemoved, Description: "removed was removed."},
+	}
+	if changes := graphql.FindBreakingChanges(oldSchema, newSchema); !reflect.DeepEqual(expected, changes) {
+		t.Fatalf("Unexpected breaking changes, Diff: %v", testutil.Diff(expected, changes))
+	}
+}
+
+func TestFindDangerousChanges(t *testing.T) {
+	oldSchema := buildSchemaOrFail(t, findChangesOldSDL)
+	newSchema := buildSchemaOrFail(t, findChangesNewSDL)
+
+	expected := []graphql.DangerousChange{
+		{Type: graphql.DangerousChangeArgDefaultValueChange, Description: "Query.user arg verbose has changed defaultValue."},
+		{Type: graphql.DangerousChangeNullableArgAdded, Description: "A nullable arg after on Query.users was added."},
+		{Type: graphql.DangerousChangeValueAddedToEnum, Description: "USER was added to enum type Role."},
+		{Type: graphql.DangerousChangeTypeAddedToUnion, Description: "Post was added to union type SearchResult."},
+		{Type: graphql.DangerousChangeInterfaceAddedToObject, Description: "Entity added to interfaces implemented by User."},
+		{Type: graphql.DangerousChangeNullableInputFieldAdded, Description: "A nullable field nickname on input type UserInput was added."},
+	}
+	if changes := graphql.FindDangerousChanges(oldSchema, newSchema); !reflect.DeepEqual(expected, changes) {
+		t.Fatalf("Unexpected dangerous changes, Diff: %v", testutil.Diff(expected, changes))
+	}
+}
+
+func TestFindChanges_IdenticalSchemas(t *testing.T) {
+	oldSchema := buildSchemaOrFail(t, findChangesOldSDL)
+	newSchema := buildSchemaOrFail(t, findChangesOldSDL)
+	if changes := graphql.FindBreakingChanges(oldSchema, newSchema); len(changes) != 0 {
+		t.Fatalf("expected no breaking changes, got: %v", changes)
+	}
+	if changes := graphql.FindDangerousChanges(oldSchema, newSchema); len(changes) != 0 {
+		t.Fatalf("expected no dangerous changes, got: %v", changes)
+	}
+}
@@ -2,8 +2,10 @@ package graphql
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
 	"github.com/graphql-go/graphql/language/parser"
 	"github.com/graphql-go/graphql/language/source"
 )
@@ -15,6 +17,10 @@ type Params struct {
 	// A GraphQL language formatted string representing the requested operation.
 	RequestString string
 
+	// ParseOptions limits the tokens, the depth and the size of the request
+	// string when parsed, see parser.ParseOptions.
+	ParseOptions parser.ParseOptions
+
 	// The value provided as the first argument to resolver functions on the top
 	// level type (e.g. the query object type).
 	RootObject map[string]interface{}
@@ -28,63 +34,162 @@ type Params struct {
 	// one operation.
 	OperationName string
 
+	// AllowedOperations, when not empty, restricts the requests to operations
+	// of the given types, such as ast.OperationTypeQuery, the others getting
+	// an OperationNotAllowedError without being executed.
+	AllowedOperations []string
+
 	// Context may be provided to pass application-specific per-request
 	// information to resolve functions.
 	Context context.Context
+
+	// Concurrency opts into resolving sibling fields and list items
+	// concurrently, see ExecuteParams.Concurrency.
+	Concurrency int
+
+	// UnorderedResults opts out of results whose objects keep the order of
+	// their fields, see ExecuteParams.UnorderedResults.
+	UnorderedResults bool
+
+	// ValidationRules replaces SpecifiedRules to validate the request with
+	// when not empty, e.g. to append QueryComplexityRule to them.
+	ValidationRules []ValidationRuleFn
+
+	// ValidationRulesKey, when set, identifies ValidationRules in the keys of
+	// DocumentCache in place of the slice holding them, so that requests
+	// building their rules anew share the cached documents. Requests with the
+	// same key must be validated with the same rules.
+	ValidationRulesKey string
+
+	// DocumentCache, when set, caches the parsed and validated document of
+	// the request string, see NewLRUDocumentCache. Extensions are notified
+	// of the parse and the validation of cached documents too, and can tell
+	// them apart with DocumentCacheHitFromContext.
+	DocumentCache DocumentCache
+
+	// Extensions holds the extensions of the request. Its "persistedQuery"
+	// extension carries the hash of the request string, see
+	// PersistedQueryHash, following the automatic persisted queries protocol:
+	// requests may send the hash alone once its request string is stored,
+	// and get ErrPersistedQueryNotFound otherwise.
+	Extensions map[string]interface{}
+
+	// PersistedQueries stores the request strings sent along with their hash,
+	// see NewLRUPersistedQueryStore.
+	PersistedQueries PersistedQueryStore
+
+	// OperationRegistry, when set, restricts the requests to the documents
+	// it holds, which are executed without being parsed and validated again.
+	// Requests sending another request string get ErrOperationNotRegistered,
+	// and those sending a hash look it up in the registry rather than in
+	// PersistedQueries.
+	OperationRegistry *OperationRegistry
 }
 
 func Do(p Params) *Result {
+	AST, prepared, errs := prepareRequest(&p)
+	if len(errs) != 0 {
+		return &Result{
+			Errors: errs,
+		}
+	}
+
+	return Execute(ExecuteParams{
+		Schema:            p.Schema,
+		Root:              p.RootObject,
+		AST:               AST,
+		OperationName:     p.OperationName,
+		Args:              p.VariableValues,
+		Context:           p.Context,
+		Concurrency:       p.Concurrency,
+		PreparedOperation: prepared,
+		UnorderedResults:  p.UnorderedResults,
+	})
+}
+
+// prepareRequest resolves the document of the request, parsing and
+// validating its request string unless cached or registered, and checks the
+// complexity of the operation to execute, which is prepared when the document
+// is cached, notifying the extensions along the way. The context of the
+// request is updated with the outcome of the cache lookup and the complexity.
+func prepareRequest(p *Params) (*ast.Document, *PreparedOperation, []gqlerrors.FormattedError) {
+	// resolve the request string of persisted queries, and the document of
+	// registered operations
+	registered, err := resolvePersistedQuery(p)
+	if err != nil {
+		return nil, nil, formatPersistedQueryError(err)
+	}
+
+	// run init on the extensions
+	extErrs := handleExtensionsInits(p)
+	if len(extErrs) != 0 {
+		return nil, nil, extErrs
+	}
+
 	source := source.NewSource(&source.Source{
 		Body: []byte(p.RequestString),
 		Name: "GraphQL request",
 	})
 
-	// run init on the extensions
-	extErrs := handleExtensionsInits(&p)
-	if len(extErrs) != 0 {
-		return &Result{
-			Errors: extErrs,
-		}
+	// look the document up before the extensions are notified of its parse
+	var (
+		cacheKey DocumentCacheKey
+		cached   = registered
+	)
+	if cached == nil && p.DocumentCache != nil {
+		var hit bool
+		cacheKey = NewDocumentCacheKey(*p)
+		cached, hit = p.DocumentCache.Get(cacheKey)
+		p.Context = withDocumentCacheHit(p.Context, hit)
 	}
 
-	extErrs, parseFinishFn := handleExtensionsParseDidStart(&p)
+	extErrs, parseFinishFn := handleExtensionsParseDidStart(p)
 	if len(extErrs) != 0 {
-		return &Result{
-			Errors: extErrs,
-		}
+		return nil, nil, extErrs
 	}
 
 	// parse the source
-	AST, err := parser.Parse(parser.ParseParams{Source: source})
+	var AST *ast.Document
+	if cached != nil {
+		AST = cached.Document
+	} else {
+		AST, err = parser.Parse(parser.ParseParams{Source: source, Options: p.ParseOptions})
+	}
 	if err != nil {
 		// run parseFinishFuncs for extensions
 		extErrs = parseFinishFn(err)
 
 		// merge the errors from extensions and the original error from parser
 		extErrs = append(extErrs, gqlerrors.FormatErrors(err)...)
-		return &Result{
-			Errors: extErrs,
-		}
+		return nil, nil, extErrs
 	}
 
 	// run parseFinish functions for extensions
 	extErrs = parseFinishFn(err)
 	if len(extErrs) != 0 {
-		return &Result{
-			Errors: extErrs,
-		}
+		return nil, nil, extErrs
 	}
 
 	// notify extensions about the start of the validation
-	extErrs, validationFinishFn := handleExtensionsValidationDidStart(&p)
+	extErrs, validationFinishFn := handleExtensionsValidationDidStart(p)
 	if len(extErrs) != 0 {
-		return &Result{
-			Errors: extErrs,
-		}
+		return nil, nil, extErrs
 	}
 
 	// validate document
-	validationResult := ValidateDocument(&p.Schema, AST, nil)
+	var validationResult ValidationResult
+	if cached != nil {
+		validationResult = cached.ValidationResult
+	} else {
+		validationResult = ValidateDocument(&p.Schema, AST, p.ValidationRules)
+		if p.DocumentCache != nil {
+			cached = &CachedDocument{
+				Document:         AST,
+				ValidationResult: validationResult,
+			}
+			p.DocumentCache.Add(cacheKey, cached)
+		}
+	}
 
 	if !validationResult.IsValid {
 		// run validation finish functions for extensions
@@ -92,25 +197,93 @@ func Do(p Params) *Result {
 
 		// merge the errors from extensions and the original error from parser
 		extErrs = append(extErrs, validationResult.Errors...)
-		return &Result{
-			Errors: extErrs,
-		}
+		return nil, nil, extErrs
 	}
 
 	// run the validationFinishFuncs for extensions
 	extErrs = validationFinishFn(validationResult.Errors)
 	if len(extErrs) != 0 {
-		return &Result{
-			Errors: extErrs,
+		return nil, nil, extErrs
+	}
+
+	if errs := checkOperationType(p, AST); len(errs) != 0 {
+		return nil, nil, errs
+	}
+
+	if errs := checkComplexity(p, validationResult); len(errs) != 0 {
+		return nil, nil, errs
+	}
+
+	// cached documents keep their operations prepared, while errors
+	// preparing them are reported by Execute
+	var prepared *PreparedOperation
+	if cached != nil {
+		prepared, _ = cached.prepare(p.Schema, p.OperationName)
+	}
+
+	return AST, prepared, nil
+}
+
+// OperationNotAllowedError is reported for requests whose operation is of a
+// type Params.AllowedOperations does not hold.
+type OperationNotAllowedError struct {
+	Operation string
+}
+
+func (e *OperationNotAllowedError) Error() string {
+	return fmt.Sprintf(`Operations of type "%v" are not allowed.`, e.Operation)
+}
+
+// checkOperationType returns the error of a request whose operation to execute
+// is not of a type AllowedOperations holds, or cannot be told.
+func checkOperationType(p *Params, document *ast.Document) []gqlerrors.FormattedError {
+	if len(p.AllowedOperations) == 0 {
+		return nil
+	}
+	operation, _, err := getOperation(document, p.OperationName)
+	if err != nil {
+		return gqlerrors.FormatErrors(err)
+	}
+	for _, allowed := range p.AllowedOperations {
+		if operation.Operation == allowed {
+			return nil
 		}
 	}
+	return gqlerrors.FormatErrors(NewLocatedError(
+		&OperationNotAllowedError{Operation: operation.Operation},
+		[]ast.Node{operation},
+	))
+}
 
-	return Execute(ExecuteParams{
-		Schema:        p.Schema,
-		Root:          p.RootObject,
-		AST:           AST,
-		OperationName: p.OperationName,
-		Args:          p.VariableValues,
-		Context:       p.Context,
-	})
+// checkComplexity sets the cost of the operation to execute, which is the
+// only operation of the document when no name is given, in the context of the
+// request. The cost of operations with variables is computed again with their
+// values, returning the error of an operation exceeding its maximum cost;
+// invalid variables are left to Execute to report.
+func checkComplexity(p *Params, validationResult ValidationResult) []gqlerrors.FormattedError {
+	operationName := p.OperationName
+	if operationName == "" && len(validationResult.Complexity) == 1 {
+		for name := range validationResult.Complexity {
+			operationName = name
+		}
+	}
+	cost, ok := validationResult.Complexity[operationName]
+	if !ok {
+		return nil
+	}
+	check := validationResult.complexityChecks[operationName]
+	if check != nil && len(check.operation.VariableDefinitions) > 0 {
+		variables, err := getVariableValues(p.Schema, check.operation.VariableDefinitions, p.VariableValues)
+		if err == nil {
+			cost = check.cost(variables)
+			if cost > check.maxCost {
+				return gqlerrors.FormatErrors(newValidationError(
+					QueryComplexityMessage(operationName, cost, check.maxCost),
+					[]ast.Node{check.operation},
+				))
+			}
+		}
+	}
+	p.Context = withComplexity(p.Context, cost)
+	return nil
 }
@@ -5,6 +5,7 @@ import (
 
 	"github.com/graphql-go/graphql"
 	"github.com/graphql-go/graphql/benchutil"
+	"github.com/graphql-go/graphql/language/parser"
 )
 
 type B struct {
@@ -69,6 +70,35 @@ func nItemsListQueryBenchmark(x int) func(b *testing.B) {
 	}
 }
 
+func BenchmarkListQueryPrepared_1(b *testing.B) {
+	nItemsListQueryPreparedBenchmark(1)(b)
+}
+
+func BenchmarkListQueryPrepared_100(b *testing.B) {
+	nItemsListQueryPreparedBenchmark(100)(b)
+}
+
+func BenchmarkListQueryPrepared_1K(b *testing.B) {
+	nItemsListQueryPreparedBenchmark(1000)(b)
+}
+
+func nItemsListQueryPreparedBenchmark(x int) func(b *testing.B) {
+	return func(b *testing.B) {
+		schema := benchutil.ListSchemaWithXItems(x)
+		query := `
+			query {
+				colors {
+					hex
+					r
+					g
+					b
+				}
+			}
+		`
+		benchPrepared(schema, query, b)
+	}
+}
+
 func BenchmarkWideQuery_1_1(b *testing.B) {
 	nFieldsyItemsQueryBenchmark(1, 1)(b)
 }
@@ -122,3 +152,47 @@ func nFieldsyItemsQueryBenchmark(x int, y int) func(b *testing.B) {
 		}
 	}
 }
+
+func BenchmarkWideQueryPrepared_10_10(b *testing.B) {
+	nFieldsyItemsQueryPreparedBenchmark(10, 10)(b)
+}
+
+func BenchmarkWideQueryPrepared_100_10(b *testing.B) {
+	nFieldsyItemsQueryPreparedBenchmark(100, 10)(b)
+}
+
+func BenchmarkWideQueryPrepared_1K_10(b *testing.B) {
+	nFieldsyItemsQueryPreparedBenchmark(1000, 10)(b)
+}
+
+func nFieldsyItemsQueryPreparedBenchmark(x int, y int) func(b *testing.B) {
+	return func(b *testing.B) {
+		schema := benchutil.WideSchemaWithXFieldsAndYItems(x, y)
+		query := benchutil.WideSchemaQuery(x)
+		benchPrepared(schema, query, b)
+	}
+}
+
+// benchPrepared prepares the query once, then benchmarks its executions.
+func benchPrepared(schema graphql.Schema, query string, b *testing.B) {
+	document, err := parser.Parse(parser.ParseParams{Source: query})
+	if err != nil {
+		b.Fatalf("unexpected parse error: %v", err)
+	}
+	operation, err := graphql.Prepare(schema, document, "")
+	if err != nil {
+		b.Fatalf("unexpected prepare error: %v", err)
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		result := graphql.Execute(graphql.ExecuteParams{
+			Schema:            schema,
+			PreparedOperation: operation,
+		})
+		if len(result.Errors) > 0 {
+			b.Fatalf("wrong result, unexpected errors: %v", result.Errors)
+		}
+	}
+}
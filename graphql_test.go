@@ -3,9 +3,13 @@ package graphql_test
 import (
 	"context"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/location"
+	"github.com/graphql-go/graphql/language/parser"
 	"github.com/graphql-go/graphql/testutil"
 )
 
@@ -109,7 +113,7 @@ func testGraphql(test T, p graphql.Params, t *testing.T) {
 	if len(result.Errors) > 0 {
 		t.Fatalf("wrong result, unexpected errors: %v", result.Errors)
 	}
-	if !reflect.DeepEqual(result, test.Expected) {
+	if !testutil.EqualResults(test.Expected.(*graphql.Result), result) {
 		t.Fatalf("wrong result, query: %v, graphql result diff: %v", test.Query, testutil.Diff(test.Expected, result))
 	}
 }
@@ -149,7 +153,7 @@ func TestBasicGraphQLExample(t *testing.T) {
 	if len(result.Errors) > 0 {
 		t.Fatalf("wrong result, unexpected errors: %v", result.Errors)
 	}
-	if !reflect.DeepEqual(result.Data, expected) {
+	if !testutil.EqualData(expected, result.Data) {
 		t.Fatalf("wrong result, query: %v, graphql result diff: %v", query, testutil.Diff(expected, result))
 	}
 
@@ -188,7 +192,7 @@ func TestThreadsContextFromParamsThrough(t *testing.T) {
 		t.Fatalf("wrong result, unexpected errors: %v", result.Errors)
 	}
 	expected := map[string]interface{}{"value": "xyz"}
-	if !reflect.DeepEqual(result.Data, expected) {
+	if !testutil.EqualData(expected, result.Data) {
 		t.Fatalf("wrong result, query: %v, graphql result diff: %v", query, testutil.Diff(expected, result))
 	}
 
@@ -264,7 +268,61 @@ func TestEmptyStringIsNotNull(t *testing.T) {
 		t.Fatalf("wrong result, unexpected errors: %v", result.Errors)
 	}
 	expected := map[string]interface{}{"checkEmptyArg": "yay", "checkEmptyResult": ""}
-	if !reflect.DeepEqual(result.Data, expected) {
+	if !testutil.EqualData(expected, result.Data) {
 		t.Errorf("wrong result, query: %v, graphql result diff: %v", query, testutil.Diff(expected, result))
 	}
 }
+
+func TestDoLimitsTheParseOfTheRequestString(t *testing.T) {
+	result := graphql.Do(graphql.Params{
+		Schema:        testutil.StarWarsSchema,
+		RequestString: `{ hero { friends { name } } }`,
+		ParseOptions:  parser.ParseOptions{MaxDepth: 2},
+	})
+	if len(result.Errors) != 1 || result.Data != nil {
+		t.Fatalf("Expected a single error, got: %v", result)
+	}
+	expected := "Syntax Error GraphQL request (1:18) Document exceeds the maximum nesting depth of 2."
+	if err := result.Errors[0]; !strings.HasPrefix(err.Message, expected) ||
+		!reflect.DeepEqual(err.Locations, []location.SourceLocation{{Line: 1, Column: 18}}) {
+		t.Fatalf("Unexpected error: %v, %v", err.Message, err.Locations)
+	}
+}
+
+func TestDoOnlyExecutesAllowedOperations(t *testing.T) {
+	result := graphql.Do(graphql.Params{
+		Schema:            testutil.StarWarsSchema,
+		RequestString:     `query Hero { hero { name } }`,
+		AllowedOperations: []string{"query"},
+	})
+	if result.HasErrors() || result.Data == nil {
+		t.Fatalf("Unexpected result: %v", result)
+	}
+
+	result = graphql.Do(graphql.Params{
+		Schema:            testutil.StarWarsSchema,
+		RequestString:     `query Hero { hero { name } }`,
+		AllowedOperations: []string{"mutation", "subscription"},
+	})
+	if len(result.Errors) != 1 || result.Data != nil {
+		t.Fatalf("Expected a single error, got: %v", result)
+	}
+	if err := result.Errors[0]; err.Message != `Operations of type "query" are not allowed.` ||
+		!reflect.DeepEqual(err.Locations, []location.SourceLocation{{Line: 1, Column: 1}}) {
+		t.Fatalf("Unexpected error: %v, %v", err.Message, err.Locations)
+	}
+	if _, ok := result.Errors[0].OriginalError().(*gqlerrors.Error).OriginalError.(*graphql.OperationNotAllowedError); !ok {
+		t.Fatalf("Expected an OperationNotAllowedError, got %#v", result.Errors[0].OriginalError())
+	}
+
+	// requests whose operation cannot be told are rejected too
+	result = graphql.Do(graphql.Params{
+		Schema:            testutil.StarWarsSchema,
+		RequestString:     `query A { hero { name } } query B { hero { id } }`,
+		AllowedOperations: []string{"query"},
+	})
+	if len(result.Errors) != 1 || result.Data != nil ||
+		result.Errors[0].Message != "Must provide operation name if query contains multiple operations." {
+		t.Fatalf("Unexpected result: %v", result)
+	}
+}
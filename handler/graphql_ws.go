@@ -0,0 +1,293 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/graphql-go/graphql"
+)
+
+// GraphQLTransportWSProtocol is the WebSocket subprotocol spoken by
+// WebSocketHandler.
+const GraphQLTransportWSProtocol = "graphql-transport-ws"
+
+// DefaultConnectionInitTimeout is how long WebSocketHandler waits for the
+// connection_init message of connections whose Config sets no
+// ConnectionInitTimeout.
+const DefaultConnectionInitTimeout = 3 * time.Second
+
+// Message types of the graphql-transport-ws protocol.
+const (
+	wsConnectionInit = "connection_init"
+	wsConnectionAck  = "connection_ack"
+	wsPing           = "ping"
+	wsPong           = "pong"
+	wsSubscribe      = "subscribe"
+	wsNext           = "next"
+	wsError          = "error"
+	wsComplete       = "complete"
+)
+
+// Close codes of the graphql-transport-ws protocol.
+const (
+	closeBadRequest                = 4400
+	closeUnauthorized              = 4401
+	closeForbidden                 = 4403
+	closeSubprotocolNotAcceptable  = 4406
+	closeConnectionInitTimeout     = 4408
+	closeSubscriberAlreadyExists   = 4409
+	closeTooManyInitialiseRequests = 4429
+)
+
+// OnConnectFn authorizes a WebSocket connection with the payload of its
+// connection_init message, returning the context of its operations, which
+// derives from the given one, or an error closing it with 4403 Forbidden.
+type OnConnectFn func(ctx context.Context, payload map[string]interface{}) (context.Context, error)
+
+// WebSocketHandler is an http.Handler executing GraphQL operations, notably
+// subscriptions, over WebSocket connections speaking the graphql-transport-ws
+// protocol:
+//
+//	http.Handle("/graphql/ws", handler.NewWebSocket(&handler.Config{
+//		Schema: &schema,
+//	}))
+//
+// Subscriptions are executed with graphql.Subscribe, each of their results
+// being sent in a next message, and other operations with graphql.Do. The
+// context of an operation is cancelled once the client completes it or
+// disconnects. Connections opened from other origins are refused, unless
+// CheckOrigin allows them.
+type WebSocketHandler struct {
+	config Config
+}
+
+// NewWebSocket returns a WebSocketHandler executing operations as
+// configured.
+func NewWebSocket(config *Config) *WebSocketHandler {
+	return &WebSocketHandler{
+		config: *config,
+	}
+}
+
+// wsMessage is a message of the graphql-transport-ws protocol.
+type wsMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// ServeHTTP upgrades the request to a WebSocket connection, and executes the
+// operations of the connection until it is closed.
+func (h *WebSocketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	checkOrigin := h.config.CheckOrigin
+	if checkOrigin == nil {
+		checkOrigin = sameOrigin
+	}
+	conn, protocol, err := upgradeWebSocket(w, r, []string{GraphQLTransportWSProtocol}, checkOrigin)
+	if err != nil {
+		return
+	}
+	conn.maxMessageBytes = h.config.maxBodyBytes()
+
+	s := &wsSession{
+		config:     &h.config,
+		conn:       conn,
+		request:    r,
+		ctx:        h.config.context(r),
+		operations: map[string]*wsOperation{},
+	}
+	defer s.cancelOperations()
+	if protocol != GraphQLTransportWSProtocol {
+		conn.close(closeSubprotocolNotAcceptable, "Subprotocol not acceptable")
+		return
+	}
+	s.serve()
+}
+
+// wsSession is the state of a WebSocket connection of a WebSocketHandler.
+type wsSession struct {
+	config  *Config
+	conn    *wsConn
+	request *http.Request
+
+	// ctx is the context of the operations of the connection, set by
+	// OnConnect.
+	ctx          context.Context
+	initialized  bool
+	acknowledged bool
+
+	mu         sync.Mutex
+	operations map[string]*wsOperation
+}
+
+// wsOperation is an operation being executed, by id.
+type wsOperation struct {
+	cancel context.CancelFunc
+}
+
+// serve reads the messages of the connection until it is closed.
+func (s *wsSession) serve() {
+	initTimeout := s.config.ConnectionInitTimeout
+	if initTimeout == 0 {
+		initTimeout = DefaultConnectionInitTimeout
+	}
+	s.conn.conn.SetReadDeadline(time.Now().Add(initTimeout))
+	for {
+		message, err := s.conn.readMessage()
+		if err != nil {
+			if closeErr, ok := err.(*wsCloseError); ok {
+				s.conn.close(closeErr.code, closeErr.reason)
+			} else if netErr, ok := err.(net.Error); ok && netErr.Timeout() && !s.initialized {
+				s.conn.close(closeConnectionInitTimeout, "Connection initialisation timeout")
+			} else {
+				s.conn.conn.Close()
+			}
+			return
+		}
+		if closeErr := s.handle(message); closeErr != nil {
+			s.conn.close(closeErr.code, closeErr.reason)
+			return
+		}
+	}
+}
+
+// handle handles the given message, returning the close of the connection it
+// requires, if any.
+func (s *wsSession) handle(data []byte) *wsCloseError {
+	message := &wsMessage{}
+	if err := json.Unmarshal(data, message); err != nil || message.Type == "" {
+		return &wsCloseError{code: closeBadRequest, reason: "Invalid message received"}
+	}
+
+	switch message.Type {
+	case wsConnectionInit:
+		if s.initialized {
+			return &wsCloseError{code: closeTooManyInitialiseRequests, reason: "Too many initialisation requests"}
+		}
+		s.initialized = true
+		payload := map[string]interface{}{}
+		if len(message.Payload) > 0 && string(message.Payload) != "null" {
+			if err := json.Unmarshal(message.Payload, &payload); err != nil {
+				return &wsCloseError{code: closeBadRequest, reason: "Invalid message received"}
+			}
+		}
+		if s.config.OnConnect != nil {
+			ctx, err := s.config.OnConnect(s.ctx, payload)
+			if err != nil {
+				return &wsCloseError{code: closeForbidden, reason: "Forbidden"}
+			}
+			s.ctx = ctx
+		}
+		s.conn.conn.SetReadDeadline(time.Time{})
+		s.acknowledged = true
+		s.send("", wsConnectionAck, nil)
+	case wsPing:
+		s.send("", wsPong, nil)
+	case wsPong:
+	case wsSubscribe:
+		if !s.acknowledged {
+			return &wsCloseError{code: closeUnauthorized, reason: "Unauthorized"}
+		}
+		opts := &RequestOptions{}
+		if message.ID == "" || json.Unmarshal(message.Payload, opts) != nil {
+			return &wsCloseError{code: closeBadRequest, reason: "Invalid message received"}
+		}
+		s.mu.Lock()
+		if _, ok := s.operations[message.ID]; ok {
+			s.mu.Unlock()
+			return &wsCloseError{
+				code:   closeSubscriberAlreadyExists,
+				reason: fmt.Sprintf("Subscriber for %v already exists", message.ID),
+			}
+		}
+		ctx, cancel := context.WithCancel(s.ctx)
+		operation := &wsOperation{cancel: cancel}
+		s.operations[message.ID] = operation
+		s.mu.Unlock()
+		go s.execute(ctx, message.ID, operation, opts)
+	case wsComplete:
+		s.mu.Lock()
+		operation, ok := s.operations[message.ID]
+		delete(s.operations, message.ID)
+		s.mu.Unlock()
+		if ok {
+			operation.cancel()
+		}
+	default:
+		return &wsCloseError{
+			code:   closeBadRequest,
+			reason: fmt.Sprintf("Unexpected message of type %v received", message.Type),
+		}
+	}
+	return nil
+}
+
+// execute executes the given operation, sending its results until its context
+// is cancelled. Operations failing before their execution, such as invalid
+// ones, get an error message, others next messages and a complete message.
+func (s *wsSession) execute(ctx context.Context, id string, operation *wsOperation, opts *RequestOptions) {
+	defer func() {
+		s.mu.Lock()
+		if s.operations[id] == operation {
+			delete(s.operations, id)
+		}
+		s.mu.Unlock()
+		operation.cancel()
+	}()
+
+	params := s.config.params(ctx, s.request, opts)
+	failed := false
+	first := true
+	// the results are drained once cancelled, letting the subscription end
+	for result := range graphql.Subscribe(params) {
+		if ctx.Err() != nil || failed {
+			continue
+		}
+		if first && isRequestError(result) {
+			s.send(id, wsError, result.Errors)
+			failed = true
+			continue
+		}
+		first = false
+		s.send(id, wsNext, result)
+	}
+	if ctx.Err() == nil && !failed {
+		s.send(id, wsComplete, nil)
+	}
+}
+
+// send writes a message of the given type, whose write errors are left to
+// the reading of the connection to notice.
+func (s *wsSession) send(id string, messageType string, payload interface{}) {
+	message := &wsMessage{
+		ID:   id,
+		Type: messageType,
+	}
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return
+		}
+		message.Payload = data
+	}
+	data, err := json.Marshal(message)
+	if err != nil {
+		return
+	}
+	s.conn.writeMessage(data)
+}
+
+// cancelOperations cancels the operations of the connection once closed.
+func (s *wsSession) cancelOperations() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, operation := range s.operations {
+		operation.cancel()
+		delete(s.operations, id)
+	}
+}
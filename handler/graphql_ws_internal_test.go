@@ -0,0 +1,445 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/graphql-go/graphql"
+)
+
+type wsUserKey struct{}
+
+type wsCancelledKey struct{}
+
+var wsTestSchema = func() graphql.Schema {
+	source := func(p graphql.ResolveParams) (interface{}, error) {
+		return p.Source, nil
+	}
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"hello": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return "world", nil
+					},
+				},
+			},
+		}),
+		Subscription: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Subscription",
+			Fields: graphql.Fields{
+				"count": &graphql.Field{
+					Type: graphql.Int,
+					Args: graphql.FieldConfigArgument{
+						"to": &graphql.ArgumentConfig{
+							Type: graphql.Int,
+						},
+					},
+					Resolve: source,
+					Subscribe: func(p graphql.ResolveParams) (interface{}, error) {
+						events := make(chan interface{})
+						go func() {
+							defer close(events)
+							for i := 1; i <= p.Args["to"].(int); i++ {
+								events <- i
+							}
+						}()
+						return events, nil
+					},
+				},
+				"user": &graphql.Field{
+					Type:    graphql.String,
+					Resolve: source,
+					Subscribe: func(p graphql.ResolveParams) (interface{}, error) {
+						events := make(chan interface{}, 1)
+						events <- p.Context.Value(wsUserKey{})
+						close(events)
+						return events, nil
+					},
+				},
+				"wait": &graphql.Field{
+					Type:    graphql.String,
+					Resolve: source,
+					Subscribe: func(p graphql.ResolveParams) (interface{}, error) {
+						go func() {
+							<-p.Context.Done()
+							close(p.Context.Value(wsCancelledKey{}).(chan struct{}))
+						}()
+						return make(chan interface{}), nil
+					},
+				},
+				"fail": &graphql.Field{
+					Type:    graphql.String,
+					Resolve: source,
+					Subscribe: func(p graphql.ResolveParams) (interface{}, error) {
+						return nil, errors.New("cannot subscribe")
+					},
+				},
+			},
+		}),
+	})
+	if err != nil {
+		panic(err)
+	}
+	return schema
+}()
+
+func newWSTestServer(config *Config) *httptest.Server {
+	if config.Schema == nil {
+		config.Schema = &wsTestSchema
+	}
+	return httptest.NewServer(NewWebSocket(config))
+}
+
+// dialWS opens a WebSocket connection to the given server offering the given
+// subprotocol.
+func dialWS(t *testing.T, server *httptest.Server, protocol string) *wsConn {
+	conn, err := net.Dial("tcp", server.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	key := "dGhlIHNhbXBsZSBub25jZQ=="
+	request, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	request.Header.Set("Connection", "Upgrade")
+	request.Header.Set("Upgrade", "websocket")
+	request.Header.Set("Sec-WebSocket-Version", "13")
+	request.Header.Set("Sec-WebSocket-Key", key)
+	request.Header.Set("Sec-WebSocket-Protocol", protocol)
+	request.Header.Set("Origin", server.URL)
+	if err := request.Write(conn); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	reader := bufio.NewReader(conn)
+	response, err := http.ReadResponse(reader, request)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("Expected status 101, got %v", response.StatusCode)
+	}
+	if accept := response.Header.Get("Sec-WebSocket-Accept"); accept != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("Unexpected Sec-WebSocket-Accept %v", accept)
+	}
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	return &wsConn{
+		conn:   conn,
+		reader: reader,
+		client: true,
+	}
+}
+
+func sendWS(t *testing.T, c *wsConn, message string) {
+	if err := c.writeMessage([]byte(message)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func expectWS(t *testing.T, c *wsConn, expected string) {
+	message, err := c.readMessage()
+	if err != nil {
+		t.Fatalf("Expected %v, got %v", expected, err)
+	}
+	var expectedValue, value interface{}
+	if err := json.Unmarshal([]byte(expected), &expectedValue); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := json.Unmarshal(message, &value); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(expectedValue, value) {
+		t.Fatalf("Expected %v, got %s", expected, message)
+	}
+}
+
+func expectWSClose(t *testing.T, c *wsConn, code int, reason string) {
+	_, err := c.readMessage()
+	closeErr, ok := err.(*wsCloseError)
+	if !ok {
+		t.Fatalf("Expected close %v, got %v", code, err)
+	}
+	if closeErr.code != code || closeErr.reason != reason {
+		t.Fatalf("Expected close %v %q, got %v %q", code, reason, closeErr.code, closeErr.reason)
+	}
+	c.close(code, "")
+}
+
+func initWS(t *testing.T, server *httptest.Server) *wsConn {
+	c := dialWS(t, server, GraphQLTransportWSProtocol)
+	sendWS(t, c, `{"type": "connection_init"}`)
+	expectWS(t, c, `{"type": "connection_ack"}`)
+	return c
+}
+
+func TestWebSocketHandler_ExecutesOperations(t *testing.T) {
+	server := newWSTestServer(&Config{})
+	defer server.Close()
+	c := initWS(t, server)
+	defer c.close(closeNormal, "")
+
+	sendWS(t, c, `{"id": "1", "type": "subscribe", "payload": {"query": "subscription ($to: Int) { count(to: $to) }", "variables": {"to": 3}}}`)
+	expectWS(t, c, `{"id": "1", "type": "next", "payload": {"data": {"count": 1}}}`)
+	expectWS(t, c, `{"id": "1", "type": "next", "payload": {"data": {"count": 2}}}`)
+	expectWS(t, c, `{"id": "1", "type": "next", "payload": {"data": {"count": 3}}}`)
+	expectWS(t, c, `{"id": "1", "type": "complete"}`)
+
+	sendWS(t, c, `{"id": "1", "type": "subscribe", "payload": {"query": "{ hello }"}}`)
+	expectWS(t, c, `{"id": "1", "type": "next", "payload": {"data": {"hello": "world"}}}`)
+	expectWS(t, c, `{"id": "1", "type": "complete"}`)
+
+	sendWS(t, c, `{"type": "ping"}`)
+	expectWS(t, c, `{"type": "pong"}`)
+}
+
+func TestWebSocketHandler_ReportsErrorsOfOperations(t *testing.T) {
+	server := newWSTestServer(&Config{})
+	defer server.Close()
+	c := initWS(t, server)
+	defer c.close(closeNormal, "")
+
+	sendWS(t, c, `{"id": "1", "type": "subscribe", "payload": {"query": "subscription { unknown }"}}`)
+	expectWS(t, c, `{"id": "1", "type": "error", "payload": [{"message": "Cannot query field \"unknown\" on type \"Subscription\".", "locations": [{"line": 1, "column": 16}]}]}`)
+
+	sendWS(t, c, `{"id": "2", "type": "subscribe", "payload": {"query": "subscription { fail }"}}`)
+	expectWS(t, c, `{"id": "2", "type": "error", "payload": [{"message": "cannot subscribe", "locations": []}]}`)
+
+	sendWS(t, c, `{"id": "3", "type": "subscribe", "payload": {"query": "{ hello "}}`)
+	expectWS(t, c, `{"id": "3", "type": "error", "payload": [{"message": "Syntax Error GraphQL request (1:9) Expected Name, found EOF\n\n1: { hello \n           ^\n", "locations": [{"line": 1, "column": 9}]}]}`)
+}
+
+func TestWebSocketHandler_CancelsCompletedOperations(t *testing.T) {
+	cancelled := make(chan struct{})
+	server := newWSTestServer(&Config{
+		ContextFn: func(r *http.Request) context.Context {
+			return context.WithValue(r.Context(), wsCancelledKey{}, cancelled)
+		},
+	})
+	defer server.Close()
+	c := initWS(t, server)
+	defer c.close(closeNormal, "")
+
+	sendWS(t, c, `{"id": "1", "type": "subscribe", "payload": {"query": "subscription { wait }"}}`)
+	sendWS(t, c, `{"id": "1", "type": "complete"}`)
+	select {
+	case <-cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected the operation to be cancelled")
+	}
+
+	// the id of a completed operation may be reused
+	sendWS(t, c, `{"id": "1", "type": "subscribe", "payload": {"query": "{ hello }"}}`)
+	expectWS(t, c, `{"id": "1", "type": "next", "payload": {"data": {"hello": "world"}}}`)
+	expectWS(t, c, `{"id": "1", "type": "complete"}`)
+}
+
+func TestWebSocketHandler_CancelsOperationsOnDisconnect(t *testing.T) {
+	cancelled := make(chan struct{})
+	server := newWSTestServer(&Config{
+		ContextFn: func(r *http.Request) context.Context {
+			return context.WithValue(context.Background(), wsCancelledKey{}, cancelled)
+		},
+	})
+	defer server.Close()
+	c := initWS(t, server)
+
+	sendWS(t, c, `{"id": "1", "type": "subscribe", "payload": {"query": "subscription { wait }"}}`)
+	sendWS(t, c, `{"type": "ping"}`)
+	expectWS(t, c, `{"type": "pong"}`)
+	c.conn.Close()
+	select {
+	case <-cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected the operation to be cancelled")
+	}
+}
+
+func TestWebSocketHandler_AuthorizesConnections(t *testing.T) {
+	server := newWSTestServer(&Config{
+		OnConnect: func(ctx context.Context, payload map[string]interface{}) (context.Context, error) {
+			user, ok := payload["token"].(string)
+			if !ok {
+				return nil, errors.New("missing token")
+			}
+			return context.WithValue(ctx, wsUserKey{}, user), nil
+		},
+	})
+	defer server.Close()
+
+	c := dialWS(t, server, GraphQLTransportWSProtocol)
+	sendWS(t, c, `{"type": "connection_init"}`)
+	expectWSClose(t, c, closeForbidden, "Forbidden")
+
+	c = dialWS(t, server, GraphQLTransportWSProtocol)
+	defer c.close(closeNormal, "")
+	sendWS(t, c, `{"type": "connection_init", "payload": {"token": "luke"}}`)
+	expectWS(t, c, `{"type": "connection_ack"}`)
+	sendWS(t, c, `{"id": "1", "type": "subscribe", "payload": {"query": "subscription { user }"}}`)
+	expectWS(t, c, `{"id": "1", "type": "next", "payload": {"data": {"user": "luke"}}}`)
+	expectWS(t, c, `{"id": "1", "type": "complete"}`)
+}
+
+func TestWebSocketHandler_ClosesConnectionsViolatingTheProtocol(t *testing.T) {
+	cancelled := make(chan struct{})
+	server := newWSTestServer(&Config{
+		ConnectionInitTimeout: 50 * time.Millisecond,
+		MaxBodyBytes:          128,
+		ContextFn: func(r *http.Request) context.Context {
+			return context.WithValue(r.Context(), wsCancelledKey{}, cancelled)
+		},
+	})
+	defer server.Close()
+
+	c := dialWS(t, server, "graphql-ws")
+	expectWSClose(t, c, closeSubprotocolNotAcceptable, "Subprotocol not acceptable")
+
+	c = dialWS(t, server, GraphQLTransportWSProtocol)
+	expectWSClose(t, c, closeConnectionInitTimeout, "Connection initialisation timeout")
+
+	c = dialWS(t, server, GraphQLTransportWSProtocol)
+	sendWS(t, c, `{"id": "1", "type": "subscribe", "payload": {"query": "{ hello }"}}`)
+	expectWSClose(t, c, closeUnauthorized, "Unauthorized")
+
+	c = initWS(t, server)
+	sendWS(t, c, `{"type": "connection_init"}`)
+	expectWSClose(t, c, closeTooManyInitialiseRequests, "Too many initialisation requests")
+
+	c = initWS(t, server)
+	sendWS(t, c, `{"type": "connection_ack"}`)
+	expectWSClose(t, c, closeBadRequest, "Unexpected message of type connection_ack received")
+
+	c = initWS(t, server)
+	sendWS(t, c, `{"type": `)
+	expectWSClose(t, c, closeBadRequest, "Invalid message received")
+
+	c = initWS(t, server)
+	sendWS(t, c, `{"type": "subscribe", "payload": {"query": "`+strings.Repeat("a", 128)+`"}}`)
+	expectWSClose(t, c, closeMessageTooBig, "Message too big")
+
+	c = initWS(t, server)
+	sendWS(t, c, "{\"type\": \"ping\", \"payload\": \"\xff\"}")
+	expectWSClose(t, c, closeInvalidPayload, "Invalid UTF-8 in text message")
+
+	c = initWS(t, server)
+	sendWS(t, c, `{"id": "1", "type": "subscribe", "payload": {"query": "subscription { wait }"}}`)
+	sendWS(t, c, `{"id": "1", "type": "subscribe", "payload": {"query": "{ hello }"}}`)
+	expectWSClose(t, c, closeSubscriberAlreadyExists, "Subscriber for 1 already exists")
+	select {
+	case <-cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected the operation to be cancelled")
+	}
+}
+
+func TestWebSocketHandler_LimitsMessagesOfUnlimitedBodies(t *testing.T) {
+	server := newWSTestServer(&Config{MaxBodyBytes: -1})
+	defer server.Close()
+
+	c := initWS(t, server)
+	// a masked text frame claiming a 2^62 bytes payload
+	frame := []byte{0x81, 0x80 | 127, 0x40, 0, 0, 0, 0, 0, 0, 0, 1, 2, 3, 4}
+	if _, err := c.conn.Write(frame); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectWSClose(t, c, closeMessageTooBig, "Message too big")
+}
+
+func TestWebSocketHandler_RejectsRequestsOtherThanHandshakes(t *testing.T) {
+	server := newWSTestServer(&Config{})
+	defer server.Close()
+
+	response, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusUpgradeRequired {
+		t.Fatalf("Expected status 426, got %v", response.StatusCode)
+	}
+}
+
+func TestWebSocketHandler_ExecutesOperationsAsDo(t *testing.T) {
+	registry := graphql.NewOperationRegistry(wsTestSchema, nil)
+	hash, err := registry.Register(`subscription { count(to: 1) }`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	server := newWSTestServer(&Config{
+		OperationRegistry: registry,
+	})
+	defer server.Close()
+	c := initWS(t, server)
+	defer c.close(closeNormal, "")
+
+	sendWS(t, c, `{"id": "1", "type": "subscribe", "payload": {"extensions": {"persistedQuery": {"version": 1, "sha256Hash": "`+strings.ToUpper(hash)+`"}}}}`)
+	expectWS(t, c, `{"id": "1", "type": "next", "payload": {"data": {"count": 1}}}`)
+	expectWS(t, c, `{"id": "1", "type": "complete"}`)
+
+	sendWS(t, c, `{"id": "2", "type": "subscribe", "payload": {"query": "subscription { count(to: 2) }"}}`)
+	expectWS(t, c, `{"id": "2", "type": "error", "payload": [{"message": "Operation is not registered", "locations": [], "extensions": {"code": "OPERATION_NOT_REGISTERED"}}]}`)
+
+	server = newWSTestServer(&Config{
+		ValidationRules: append([]graphql.ValidationRuleFn{graphql.MaxAliasesRule(0)}, graphql.SpecifiedRules...),
+	})
+	defer server.Close()
+	c = initWS(t, server)
+	defer c.close(closeNormal, "")
+
+	sendWS(t, c, `{"id": "1", "type": "subscribe", "payload": {"query": "subscription { n: count(to: 1) }"}}`)
+	expectWS(t, c, `{"id": "1", "type": "error", "payload": [{"message": "Alias \"n\" exceeds the maximum of 0 aliases.", "locations": [{"line": 1, "column": 16}]}]}`)
+}
+
+func TestWebSocketHandler_ChecksTheOriginOfConnections(t *testing.T) {
+	handshake := func(server *httptest.Server, origin string) int {
+		request, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		request.Header.Set("Connection", "Upgrade")
+		request.Header.Set("Upgrade", "websocket")
+		request.Header.Set("Sec-WebSocket-Version", "13")
+		request.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+		request.Header.Set("Sec-WebSocket-Protocol", GraphQLTransportWSProtocol)
+		request.Header.Set("Origin", origin)
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		response.Body.Close()
+		return response.StatusCode
+	}
+
+	server := newWSTestServer(&Config{})
+	defer server.Close()
+	if status := handshake(server, "https://example.com"); status != http.StatusForbidden {
+		t.Fatalf("Expected status 403, got %v", status)
+	}
+	if status := handshake(server, server.URL); status != http.StatusSwitchingProtocols {
+		t.Fatalf("Expected status 101, got %v", status)
+	}
+
+	server = newWSTestServer(&Config{
+		CheckOrigin: func(r *http.Request) bool {
+			return r.Header.Get("Origin") == "https://example.com"
+		},
+	})
+	defer server.Close()
+	if status := handshake(server, "https://example.com"); status != http.StatusSwitchingProtocols {
+		t.Fatalf("Expected status 101, got %v", status)
+	}
+	if status := handshake(server, server.URL); status != http.StatusForbidden {
+		t.Fatalf("Expected status 403, got %v", status)
+	}
+}
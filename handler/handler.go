@@ -0,0 +1,375 @@
+// Package handler serves GraphQL requests over HTTP, following the
+// GraphQL-over-HTTP specification:
+//
+//	http.Handle("/graphql", handler.New(&handler.Config{
+//		Schema: &schema,
+//	}))
+//
+// Queries are executed for GET requests, whose parameters are sent in the
+// query string, and every operation for POST requests, whose parameters are
+// sent as an application/json body, or whose query is sent as an
+// application/graphql body. Responses are encoded as
+// application/graphql-response+json when the client accepts it, and as
+// application/json otherwise.
+//
+// Subscriptions are served over WebSocket connections by a WebSocketHandler,
+// see NewWebSocket, and as Server-Sent Events by an SSEHandler, see NewSSE.
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+const (
+	ContentTypeJSON            = "application/json"
+	ContentTypeGraphQL         = "application/graphql"
+	ContentTypeGraphQLResponse = "application/graphql-response+json"
+	ContentTypeEventStream     = "text/event-stream"
+)
+
+// DefaultMaxBodyBytes is the size of the largest request body read by
+// handlers whose Config sets no MaxBodyBytes.
+const DefaultMaxBodyBytes = 1 << 20
+
+// RootObjectFn returns the root object of the given request.
+type RootObjectFn func(ctx context.Context, r *http.Request) map[string]interface{}
+
+// ContextFn returns the context of the given request, which resolvers
+// receive.
+type ContextFn func(r *http.Request) context.Context
+
+// Config configures a Handler.
+type Config struct {
+	Schema *graphql.Schema
+
+	// ContextFn builds the context of the requests, which is the context of
+	// the http.Request when nil.
+	ContextFn ContextFn
+
+	// RootObjectFn builds the root object of the requests, if set.
+	RootObjectFn RootObjectFn
+
+	// MaxBodyBytes is the size of the largest request body read, requests
+	// with larger bodies getting 413 Request Entity Too Large, and of the
+	// largest WebSocket message read. It is DefaultMaxBodyBytes when zero,
+	// and unlimited when negative, except for WebSocket messages, which are
+	// then limited to 64 MiB.
+	MaxBodyBytes int64
+
+	// OnConnect authorizes the WebSocket connections of a WebSocketHandler,
+	// if set.
+	OnConnect OnConnectFn
+
+	// CheckOrigin authorizes the origin of the WebSocket connections of a
+	// WebSocketHandler, those whose Origin header is not the host of the
+	// handler getting 403 Forbidden when nil.
+	CheckOrigin func(r *http.Request) bool
+
+	// ConnectionInitTimeout is how long a WebSocketHandler waits for the
+	// connection_init message of its connections, which is
+	// DefaultConnectionInitTimeout when zero.
+	ConnectionInitTimeout time.Duration
+
+	// Concurrency, ParseOptions, ValidationRules, DocumentCache,
+	// PersistedQueries and OperationRegistry are passed to graphql.Do, see
+	// graphql.Params.
+	Concurrency       int
+	ParseOptions      parser.ParseOptions
+	ValidationRules   []graphql.ValidationRuleFn
+	DocumentCache     graphql.DocumentCache
+	PersistedQueries  graphql.PersistedQueryStore
+	OperationRegistry *graphql.OperationRegistry
+}
+
+// Handler is an http.Handler executing GraphQL requests, whose results keep
+// the fields in the order they were requested.
+type Handler struct {
+	config Config
+}
+
+// New returns a Handler executing requests as configured.
+func New(config *Config) *Handler {
+	return &Handler{
+		config: *config,
+	}
+}
+
+// RequestOptions are the parameters of a GraphQL request.
+type RequestOptions struct {
+	Query         string                 `json:"query"`
+	Variables     map[string]interface{} `json:"variables"`
+	OperationName string                 `json:"operationName"`
+	Extensions    map[string]interface{} `json:"extensions"`
+}
+
+// requestError is an error of a request preventing its execution, reported
+// with the given status.
+type requestError struct {
+	status  int
+	message string
+}
+
+func (e *requestError) Error() string {
+	return e.message
+}
+
+func newRequestError(status int, format string, args ...interface{}) *requestError {
+	return &requestError{
+		status:  status,
+		message: fmt.Sprintf(format, args...),
+	}
+}
+
+// ServeHTTP executes the GraphQL request.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	contentType, ok := negotiateContentType(r.Header.Get("Accept"))
+	if !ok {
+		writeError(w, ContentTypeJSON, newRequestError(http.StatusNotAcceptable,
+			"Accept must allow %v or %v", ContentTypeGraphQLResponse, ContentTypeJSON))
+		return
+	}
+
+	opts, err := h.config.requestOptions(w, r)
+	if err != nil {
+		writeError(w, contentType, err)
+		return
+	}
+	params := h.config.params(h.config.context(r), r, opts)
+	if r.Method == http.MethodGet {
+		params.AllowedOperations = []string{ast.OperationTypeQuery}
+	}
+
+	result := graphql.Do(params)
+	if operation, ok := notAllowedOperation(result); ok {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, contentType, newRequestError(http.StatusMethodNotAllowed,
+			"Can only perform a %v operation from a POST request", operation))
+		return
+	}
+	if isRequestError(result) {
+		status := http.StatusOK
+		if contentType == ContentTypeGraphQLResponse {
+			status = http.StatusBadRequest
+		}
+		writeJSON(w, contentType, status, &errorsResponse{Errors: result.Errors})
+		return
+	}
+	writeJSON(w, contentType, http.StatusOK, result)
+}
+
+// context returns the context of the given request.
+func (c *Config) context(r *http.Request) context.Context {
+	if c.ContextFn != nil {
+		return c.ContextFn(r)
+	}
+	return r.Context()
+}
+
+func (c *Config) maxBodyBytes() int64 {
+	if c.MaxBodyBytes == 0 {
+		return DefaultMaxBodyBytes
+	}
+	return c.MaxBodyBytes
+}
+
+// params returns the parameters executing the given request with the given
+// context.
+func (c *Config) params(ctx context.Context, r *http.Request, opts *RequestOptions) graphql.Params {
+	var rootObject map[string]interface{}
+	if c.RootObjectFn != nil {
+		rootObject = c.RootObjectFn(ctx, r)
+	}
+	return graphql.Params{
+		Schema:            *c.Schema,
+		RequestString:     opts.Query,
+		RootObject:        rootObject,
+		VariableValues:    opts.Variables,
+		OperationName:     opts.OperationName,
+		Context:           ctx,
+		ParseOptions:      c.ParseOptions,
+		Concurrency:       c.Concurrency,
+		ValidationRules:   c.ValidationRules,
+		DocumentCache:     c.DocumentCache,
+		Extensions:        opts.Extensions,
+		PersistedQueries:  c.PersistedQueries,
+		OperationRegistry: c.OperationRegistry,
+	}
+}
+
+// errorsResponse is the response of requests failing before their execution,
+// which holds no data.
+type errorsResponse struct {
+	Errors []gqlerrors.FormattedError `json:"errors"`
+}
+
+// requestOptions returns the parameters of the given request.
+func (c *Config) requestOptions(w http.ResponseWriter, r *http.Request) (*RequestOptions, error) {
+	switch r.Method {
+	case http.MethodGet:
+		return optionsFromQuery(r.URL.Query())
+	case http.MethodPost:
+	default:
+		w.Header().Set("Allow", strings.Join([]string{http.MethodGet, http.MethodPost}, ", "))
+		return nil, newRequestError(http.StatusMethodNotAllowed, "GraphQL requests must use GET or POST")
+	}
+
+	maxBodyBytes := c.maxBodyBytes()
+	var body io.Reader = r.Body
+	if maxBodyBytes > 0 {
+		// a byte past the limit tells bodies exceeding it apart
+		body = io.LimitReader(r.Body, maxBodyBytes+1)
+	}
+	data, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, newRequestError(http.StatusBadRequest, "Could not read request body: %v", err)
+	}
+	if maxBodyBytes > 0 && int64(len(data)) > maxBodyBytes {
+		return nil, newRequestError(http.StatusRequestEntityTooLarge,
+			"Request body must not exceed %v bytes", maxBodyBytes)
+	}
+
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		mediaType = ""
+	}
+	switch mediaType {
+	case ContentTypeJSON:
+		opts := &RequestOptions{}
+		if err := json.Unmarshal(data, opts); err != nil {
+			return nil, newRequestError(http.StatusBadRequest, "Request body must be a JSON object: %v", err)
+		}
+		return opts, nil
+	case ContentTypeGraphQL:
+		opts, err := optionsFromQuery(r.URL.Query())
+		if err != nil {
+			return nil, err
+		}
+		opts.Query = string(data)
+		return opts, nil
+	default:
+		return nil, newRequestError(http.StatusUnsupportedMediaType,
+			"Content-Type must be %v or %v", ContentTypeJSON, ContentTypeGraphQL)
+	}
+}
+
+// optionsFromQuery returns the parameters of a request sent in a query string.
+func optionsFromQuery(values url.Values) (*RequestOptions, error) {
+	opts := &RequestOptions{
+		Query:         values.Get("query"),
+		OperationName: values.Get("operationName"),
+	}
+	if variables := values.Get("variables"); variables != "" {
+		if err := json.Unmarshal([]byte(variables), &opts.Variables); err != nil {
+			return nil, newRequestError(http.StatusBadRequest, "Variables must be a JSON object: %v", err)
+		}
+	}
+	if extensions := values.Get("extensions"); extensions != "" {
+		if err := json.Unmarshal([]byte(extensions), &opts.Extensions); err != nil {
+			return nil, newRequestError(http.StatusBadRequest, "Extensions must be a JSON object: %v", err)
+		}
+	}
+	return opts, nil
+}
+
+// notAllowedOperation returns the type of the operation of a request which
+// graphql.Do rejected as not allowed, if it did.
+func notAllowedOperation(result *graphql.Result) (string, bool) {
+	if len(result.Errors) != 1 {
+		return "", false
+	}
+	err, ok := result.Errors[0].OriginalError().(*gqlerrors.Error)
+	if !ok {
+		return "", false
+	}
+	notAllowed, ok := err.OriginalError.(*graphql.OperationNotAllowedError)
+	if !ok {
+		return "", false
+	}
+	return notAllowed.Operation, true
+}
+
+// negotiateContentType returns the media type of the response accepted by
+// the given Accept header, application/json being assumed for requests
+// without one, and whether there is one.
+func negotiateContentType(accept string) (string, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return ContentTypeJSON, true
+	}
+	best, bestQuality := "", 0.0
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+		switch mediaType {
+		case ContentTypeGraphQLResponse:
+		case ContentTypeJSON, "application/*", "*/*":
+			mediaType = ContentTypeJSON
+		default:
+			continue
+		}
+		// application/graphql-response+json is preferred at equal quality
+		if quality > bestQuality || quality == bestQuality && quality > 0 && mediaType == ContentTypeGraphQLResponse {
+			best, bestQuality = mediaType, quality
+		}
+	}
+	return best, bestQuality > 0
+}
+
+// isRequestError reports whether the result is the one of a request which
+// failed before its execution, such as a request failing validation, rather
+// than one holding data, possibly null, along with field errors, which hold
+// their path.
+func isRequestError(result *graphql.Result) bool {
+	if result.Data != nil || len(result.Errors) == 0 {
+		return false
+	}
+	for _, err := range result.Errors {
+		if len(err.Path) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func writeError(w http.ResponseWriter, contentType string, err error) {
+	status := http.StatusBadRequest
+	if err, ok := err.(*requestError); ok {
+		status = err.status
+	}
+	writeJSON(w, contentType, status, &errorsResponse{
+		Errors: []gqlerrors.FormattedError{gqlerrors.NewFormattedError(err.Error())},
+	})
+}
+
+func writeJSON(w http.ResponseWriter, contentType string, status int, response interface{}) {
+	body, err := json.Marshal(response)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", contentType+"; charset=utf-8")
+	w.WriteHeader(status)
+	w.Write(body)
+}
@@ -0,0 +1,294 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/handler"
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+type greetingKey struct{}
+
+var testSchema = func() graphql.Schema {
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"hello": &graphql.Field{
+					Type: graphql.String,
+					Args: graphql.FieldConfigArgument{
+						"name": &graphql.ArgumentConfig{
+							Type:         graphql.String,
+							DefaultValue: "World",
+						},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						greeting, ok := p.Context.Value(greetingKey{}).(string)
+						if !ok {
+							greeting = "Hello"
+						}
+						return greeting + ", " + p.Args["name"].(string) + "!", nil
+					},
+				},
+				"root": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						root, _ := p.Info.RootValue.(map[string]interface{})
+						return root["value"], nil
+					},
+				},
+			},
+		}),
+		Mutation: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Mutation",
+			Fields: graphql.Fields{
+				"echo": &graphql.Field{
+					Type: graphql.String,
+					Args: graphql.FieldConfigArgument{
+						"text": &graphql.ArgumentConfig{
+							Type: graphql.String,
+						},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return p.Args["text"], nil
+					},
+				},
+			},
+		}),
+	})
+	if err != nil {
+		panic(err)
+	}
+	return schema
+}()
+
+// serve serves the given request with a handler of the given config, and
+// returns the response and its decoded body.
+func serve(t *testing.T, config *handler.Config, r *http.Request) (*httptest.ResponseRecorder, map[string]interface{}) {
+	if config.Schema == nil {
+		config.Schema = &testSchema
+	}
+	w := httptest.NewRecorder()
+	handler.New(config).ServeHTTP(w, r)
+	body := map[string]interface{}{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Unexpected body %q: %v", w.Body.String(), err)
+	}
+	return w, body
+}
+
+func expectResponse(t *testing.T, w *httptest.ResponseRecorder, body map[string]interface{}, status int, contentType string, expected string) {
+	if w.Code != status {
+		t.Fatalf("Expected status %v, got %v: %v", status, w.Code, w.Body.String())
+	}
+	if got := w.Header().Get("Content-Type"); got != contentType+"; charset=utf-8" {
+		t.Fatalf("Expected content type %v, got %v", contentType, got)
+	}
+	expectedBody := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(expected), &expectedBody); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(expectedBody, body) {
+		t.Fatalf("Expected body %v, got %v", expected, w.Body.String())
+	}
+}
+
+func getRequest(values url.Values) *http.Request {
+	return httptest.NewRequest(http.MethodGet, "/graphql?"+values.Encode(), nil)
+}
+
+func postRequest(contentType string, body string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body))
+	r.Header.Set("Content-Type", contentType)
+	return r
+}
+
+func TestHandler_ExecutesQueriesOfGetRequests(t *testing.T) {
+	w, body := serve(t, &handler.Config{}, getRequest(url.Values{
+		"query":         {`query A { a: hello } query B($name: String) { b: hello(name: $name) }`},
+		"operationName": {"B"},
+		"variables":     {`{"name": "Luke"}`},
+	}))
+	expectResponse(t, w, body, http.StatusOK, handler.ContentTypeJSON, `{"data": {"b": "Hello, Luke!"}}`)
+}
+
+func TestHandler_RejectsMutationsOfGetRequests(t *testing.T) {
+	w, body := serve(t, &handler.Config{}, getRequest(url.Values{
+		"query": {`mutation { echo(text: "hi") }`},
+	}))
+	expectResponse(t, w, body, http.StatusMethodNotAllowed, handler.ContentTypeJSON,
+		`{"errors": [{"message": "Can only perform a mutation operation from a POST request", "locations": []}]}`)
+	if allow := w.Header().Get("Allow"); allow != http.MethodPost {
+		t.Fatalf("Expected POST to be allowed, got %v", allow)
+	}
+}
+
+func TestHandler_RejectsPersistedMutationsOfGetRequests(t *testing.T) {
+	store := graphql.NewLRUPersistedQueryStore(10)
+	mutation := `mutation { echo(text: "hi") }`
+	hash := graphql.PersistedQueryHash(mutation)
+	store.Add(hash, mutation)
+	registry := graphql.NewOperationRegistry(testSchema, nil)
+	if _, err := registry.Register(mutation); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	configs := []*handler.Config{
+		{PersistedQueries: store},
+		{OperationRegistry: registry},
+	}
+	for _, config := range configs {
+		// hashes are looked up case-insensitively
+		for _, hash := range []string{hash, strings.ToUpper(hash)} {
+			w, body := serve(t, config, getRequest(url.Values{
+				"extensions": {`{"persistedQuery": {"version": 1, "sha256Hash": "` + hash + `"}}`},
+			}))
+			expectResponse(t, w, body, http.StatusMethodNotAllowed, handler.ContentTypeJSON,
+				`{"errors": [{"message": "Can only perform a mutation operation from a POST request", "locations": []}]}`)
+		}
+	}
+}
+
+func TestHandler_RejectsGetRequestsWhoseOperationCannotBeTold(t *testing.T) {
+	w, body := serve(t, &handler.Config{}, getRequest(url.Values{
+		"query": {`query A { hello } mutation B { echo(text: "hi") }`},
+	}))
+	expectResponse(t, w, body, http.StatusOK, handler.ContentTypeJSON,
+		`{"errors": [{"message": "Must provide operation name if query contains multiple operations.", "locations": []}]}`)
+}
+
+func TestHandler_ExecutesOperationsOfPostRequests(t *testing.T) {
+	w, body := serve(t, &handler.Config{}, postRequest("application/json; charset=utf-8", `{
+		"query": "mutation ($text: String) { echo(text: $text) }",
+		"variables": {"text": "hi"}
+	}`))
+	expectResponse(t, w, body, http.StatusOK, handler.ContentTypeJSON, `{"data": {"echo": "hi"}}`)
+
+	r := postRequest(handler.ContentTypeGraphQL, `query ($name: String) { hello(name: $name) }`)
+	r.URL.RawQuery = url.Values{"variables": {`{"name": "Leia"}`}}.Encode()
+	w, body = serve(t, &handler.Config{}, r)
+	expectResponse(t, w, body, http.StatusOK, handler.ContentTypeJSON, `{"data": {"hello": "Hello, Leia!"}}`)
+
+	// bodies of the largest size allowed are read
+	w, body = serve(t, &handler.Config{MaxBodyBytes: 32}, postRequest(handler.ContentTypeGraphQL, `{ hello(name: "`+strings.Repeat("a", 13)+`") }`))
+	expectResponse(t, w, body, http.StatusOK, handler.ContentTypeJSON, `{"data": {"hello": "Hello, aaaaaaaaaaaaa!"}}`)
+}
+
+func TestHandler_RejectsMalformedRequests(t *testing.T) {
+	cases := []struct {
+		request  *http.Request
+		status   int
+		expected string
+	}{
+		{
+			request:  postRequest("text/plain", `{ hello }`),
+			status:   http.StatusUnsupportedMediaType,
+			expected: `{"errors": [{"message": "Content-Type must be application/json or application/graphql", "locations": []}]}`,
+		},
+		{
+			request:  postRequest(handler.ContentTypeJSON, `[]`),
+			status:   http.StatusBadRequest,
+			expected: `{"errors": [{"message": "Request body must be a JSON object: json: cannot unmarshal array into Go value of type handler.RequestOptions", "locations": []}]}`,
+		},
+		{
+			request:  getRequest(url.Values{"query": {`{ hello }`}, "variables": {`1`}}),
+			status:   http.StatusBadRequest,
+			expected: `{"errors": [{"message": "Variables must be a JSON object: json: cannot unmarshal number into Go value of type map[string]interface {}", "locations": []}]}`,
+		},
+		{
+			request:  httptest.NewRequest(http.MethodPut, "/graphql", nil),
+			status:   http.StatusMethodNotAllowed,
+			expected: `{"errors": [{"message": "GraphQL requests must use GET or POST", "locations": []}]}`,
+		},
+		{
+			request:  postRequest(handler.ContentTypeGraphQL, `{ hello(name: "`+strings.Repeat("a", 64)+`") }`),
+			status:   http.StatusRequestEntityTooLarge,
+			expected: `{"errors": [{"message": "Request body must not exceed 32 bytes", "locations": []}]}`,
+		},
+		{
+			// a byte past the limit
+			request:  postRequest(handler.ContentTypeGraphQL, `{ hello(name: "`+strings.Repeat("a", 14)+`") }`),
+			status:   http.StatusRequestEntityTooLarge,
+			expected: `{"errors": [{"message": "Request body must not exceed 32 bytes", "locations": []}]}`,
+		},
+	}
+	for _, c := range cases {
+		w, body := serve(t, &handler.Config{MaxBodyBytes: 32}, c.request)
+		expectResponse(t, w, body, c.status, handler.ContentTypeJSON, c.expected)
+	}
+}
+
+func TestHandler_NegotiatesTheContentTypeOfResponses(t *testing.T) {
+	invalid := url.Values{"query": {`{ unknown }`}}
+	expected := `{"errors": [{"message": "Cannot query field \"unknown\" on type \"Query\".", "locations": [{"line": 1, "column": 3}]}]}`
+	cases := []struct {
+		accept      string
+		status      int
+		contentType string
+	}{
+		{"", http.StatusOK, handler.ContentTypeJSON},
+		{"application/json", http.StatusOK, handler.ContentTypeJSON},
+		{"*/*", http.StatusOK, handler.ContentTypeJSON},
+		{"application/graphql-response+json, application/json;q=0.9", http.StatusBadRequest, handler.ContentTypeGraphQLResponse},
+		{"application/json, application/graphql-response+json", http.StatusBadRequest, handler.ContentTypeGraphQLResponse},
+		{"application/json, application/graphql-response+json;q=0.5", http.StatusOK, handler.ContentTypeJSON},
+	}
+	for _, c := range cases {
+		r := getRequest(invalid)
+		r.Header.Set("Accept", c.accept)
+		w, body := serve(t, &handler.Config{}, r)
+		expectResponse(t, w, body, c.status, c.contentType, expected)
+	}
+
+	r := getRequest(url.Values{"query": {`{ hello }`}})
+	r.Header.Set("Accept", "text/html")
+	w, body := serve(t, &handler.Config{}, r)
+	expectResponse(t, w, body, http.StatusNotAcceptable, handler.ContentTypeJSON,
+		`{"errors": [{"message": "Accept must allow application/graphql-response+json or application/json", "locations": []}]}`)
+
+	r = getRequest(url.Values{"query": {`{ hello }`}})
+	r.Header.Set("Accept", handler.ContentTypeGraphQLResponse)
+	w, body = serve(t, &handler.Config{}, r)
+	expectResponse(t, w, body, http.StatusOK, handler.ContentTypeGraphQLResponse, `{"data": {"hello": "Hello, World!"}}`)
+}
+
+func TestHandler_BuildsTheContextAndRootObjectOfRequests(t *testing.T) {
+	config := &handler.Config{
+		ContextFn: func(r *http.Request) context.Context {
+			return context.WithValue(r.Context(), greetingKey{}, r.Header.Get("X-Greeting"))
+		},
+		RootObjectFn: func(ctx context.Context, r *http.Request) map[string]interface{} {
+			return map[string]interface{}{
+				"value": ctx.Value(greetingKey{}),
+			}
+		},
+	}
+	r := getRequest(url.Values{"query": {`{ hello root }`}})
+	r.Header.Set("X-Greeting", "Hi")
+	w, body := serve(t, config, r)
+	expectResponse(t, w, body, http.StatusOK, handler.ContentTypeJSON, `{"data": {"hello": "Hi, World!", "root": "Hi"}}`)
+}
+
+func TestHandler_LimitsTheParseOfRequests(t *testing.T) {
+	w, body := serve(t, &handler.Config{
+		ParseOptions: parser.ParseOptions{MaxTokens: 4},
+	}, getRequest(url.Values{"query": {`{ hello(name: "you") }`}}))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %v: %v", w.Code, w.Body.String())
+	}
+	errs, _ := body["errors"].([]interface{})
+	if len(errs) != 1 {
+		t.Fatalf("Expected a single error, got %v", w.Body.String())
+	}
+	message, _ := errs[0].(map[string]interface{})["message"].(string)
+	if expected := "Syntax Error GraphQL request (1:13) Document exceeds the maximum of 4 tokens."; !strings.HasPrefix(message, expected) {
+		t.Fatalf("Expected error %q, got %q", expected, message)
+	}
+}
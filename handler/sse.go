@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// SSEHandler is an http.Handler executing GraphQL operations, notably
+// subscriptions, whose results are streamed as Server-Sent Events, following
+// the distinct connections mode of the GraphQL over SSE protocol:
+//
+//	http.Handle("/graphql/stream", handler.NewSSE(&handler.Config{
+//		Schema: &schema,
+//	}))
+//
+// Requests are sent as to a Handler, queries and subscriptions being executed
+// for GET requests as well. Each result is sent in a next event, followed by
+// a complete event once the operation ends. Requests failing before their
+// execution, such as invalid ones, get an application/json response instead,
+// so that the event stream only starts along with the first result.
+// The context of an operation is cancelled once the client disconnects.
+type SSEHandler struct {
+	config Config
+}
+
+// NewSSE returns a SSEHandler executing operations as configured.
+func NewSSE(config *Config) *SSEHandler {
+	return &SSEHandler{
+		config: *config,
+	}
+}
+
+// ServeHTTP executes the GraphQL request, streaming its results.
+func (h *SSEHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !acceptsEventStream(r.Header.Get("Accept")) {
+		writeError(w, ContentTypeJSON, newRequestError(http.StatusNotAcceptable,
+			"Accept must allow %v", ContentTypeEventStream))
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, ContentTypeJSON, newRequestError(http.StatusInternalServerError,
+			"Streaming is not supported"))
+		return
+	}
+
+	opts, err := h.config.requestOptions(w, r)
+	if err != nil {
+		writeError(w, ContentTypeJSON, err)
+		return
+	}
+	ctx, cancel := context.WithCancel(h.config.context(r))
+	defer cancel()
+	params := h.config.params(ctx, r, opts)
+	params.AllowedOperations = []string{ast.OperationTypeQuery, ast.OperationTypeMutation, ast.OperationTypeSubscription}
+	if r.Method == http.MethodGet {
+		params.AllowedOperations = []string{ast.OperationTypeQuery, ast.OperationTypeSubscription}
+	}
+
+	results := graphql.Subscribe(params)
+	started := false
+	for {
+		select {
+		case <-r.Context().Done():
+			cancel()
+			// the results are drained, letting the subscription end
+			go func() {
+				for range results {
+				}
+			}()
+			return
+		case result, ok := <-results:
+			if !started {
+				// requests failing before their execution are told apart by
+				// their first result, which the event stream starts with
+				if ok && h.reject(w, result) {
+					return
+				}
+				startEventStream(w)
+				started = true
+			}
+			if !ok {
+				writeEvent(w, flusher, "complete", nil)
+				return
+			}
+			writeEvent(w, flusher, "next", result)
+		}
+	}
+}
+
+// reject writes the application/json response of the request whose first
+// result is given if it failed before its execution, reporting whether it
+// did.
+func (h *SSEHandler) reject(w http.ResponseWriter, result *graphql.Result) bool {
+	if operation, ok := notAllowedOperation(result); ok && operation == ast.OperationTypeMutation {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, ContentTypeJSON, newRequestError(http.StatusMethodNotAllowed,
+			"Can only perform a %v operation from a POST request", operation))
+		return true
+	}
+	if isRequestError(result) {
+		writeJSON(w, ContentTypeJSON, http.StatusBadRequest, &errorsResponse{Errors: result.Errors})
+		return true
+	}
+	return false
+}
+
+// acceptsEventStream reports whether the given Accept header allows
+// text/event-stream, which requests without one are assumed to.
+func acceptsEventStream(accept string) bool {
+	if strings.TrimSpace(accept) == "" {
+		return true
+	}
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		if q, ok := params["q"]; ok {
+			if quality, err := strconv.ParseFloat(q, 64); err != nil || quality <= 0 {
+				continue
+			}
+		}
+		switch mediaType {
+		case ContentTypeEventStream, "text/*", "*/*":
+			return true
+		}
+	}
+	return false
+}
+
+func startEventStream(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", ContentTypeEventStream+"; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	// disables the buffering of the response by nginx
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+}
+
+// writeEvent writes an event of the given type whose data is the given
+// result encoded as JSON, if any.
+func writeEvent(w http.ResponseWriter, flusher http.Flusher, event string, result *graphql.Result) {
+	data := []byte{}
+	if result != nil {
+		var err error
+		if data, err = json.Marshal(result); err != nil {
+			data, _ = json.Marshal(&errorsResponse{
+				Errors: []gqlerrors.FormattedError{gqlerrors.NewFormattedError(err.Error())},
+			})
+		}
+	}
+	if len(data) == 0 {
+		fmt.Fprintf(w, "event: %v\ndata:\n\n", event)
+	} else {
+		fmt.Fprintf(w, "event: %v\ndata: %s\n\n", event, data)
+	}
+	flusher.Flush()
+}
@@ -0,0 +1,266 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/handler"
+)
+
+type cancelledKey struct{}
+
+var sseTestSchema = func() graphql.Schema {
+	source := func(p graphql.ResolveParams) (interface{}, error) {
+		return p.Source, nil
+	}
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"hello": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return "world", nil
+					},
+				},
+			},
+		}),
+		Mutation: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Mutation",
+			Fields: graphql.Fields{
+				"echo": &graphql.Field{
+					Type: graphql.String,
+					Args: graphql.FieldConfigArgument{
+						"text": &graphql.ArgumentConfig{
+							Type: graphql.String,
+						},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return p.Args["text"], nil
+					},
+				},
+			},
+		}),
+		Subscription: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Subscription",
+			Fields: graphql.Fields{
+				"count": &graphql.Field{
+					Type: graphql.Int,
+					Args: graphql.FieldConfigArgument{
+						"to": &graphql.ArgumentConfig{
+							Type: graphql.Int,
+						},
+					},
+					Resolve: source,
+					Subscribe: func(p graphql.ResolveParams) (interface{}, error) {
+						events := make(chan interface{})
+						go func() {
+							defer close(events)
+							for i := 1; i <= p.Args["to"].(int); i++ {
+								events <- i
+							}
+						}()
+						return events, nil
+					},
+				},
+				"wait": &graphql.Field{
+					Type:    graphql.String,
+					Resolve: source,
+					Subscribe: func(p graphql.ResolveParams) (interface{}, error) {
+						// the first event starts the stream, the subscription
+						// then waiting to be cancelled
+						events := make(chan interface{}, 1)
+						events <- "waiting"
+						go func() {
+							<-p.Context.Done()
+							close(p.Context.Value(cancelledKey{}).(chan struct{}))
+						}()
+						return events, nil
+					},
+				},
+			},
+		}),
+	})
+	if err != nil {
+		panic(err)
+	}
+	return schema
+}()
+
+func serveSSE(config *handler.Config, r *http.Request) *httptest.ResponseRecorder {
+	config.Schema = &sseTestSchema
+	w := httptest.NewRecorder()
+	handler.NewSSE(config).ServeHTTP(w, r)
+	return w
+}
+
+func expectEventStream(t *testing.T, w *httptest.ResponseRecorder, expected string) {
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %v: %v", w.Code, w.Body.String())
+	}
+	if contentType := w.Header().Get("Content-Type"); contentType != "text/event-stream; charset=utf-8" {
+		t.Fatalf("Unexpected content type %v", contentType)
+	}
+	if body := w.Body.String(); body != expected {
+		t.Fatalf("Expected body %q, got %q", expected, body)
+	}
+}
+
+func TestSSEHandler_StreamsSubscriptions(t *testing.T) {
+	r := getRequest(url.Values{"query": {`subscription { count(to: 3) }`}})
+	r.Header.Set("Accept", handler.ContentTypeEventStream)
+	w := serveSSE(&handler.Config{}, r)
+	expectEventStream(t, w, "event: next\ndata: {\"data\":{\"count\":1}}\n\n"+
+		"event: next\ndata: {\"data\":{\"count\":2}}\n\n"+
+		"event: next\ndata: {\"data\":{\"count\":3}}\n\n"+
+		"event: complete\ndata:\n\n")
+}
+
+func TestSSEHandler_StreamsTheResultOfQueries(t *testing.T) {
+	r := postRequest(handler.ContentTypeJSON, `{"query": "{ hello }"}`)
+	w := serveSSE(&handler.Config{}, r)
+	expectEventStream(t, w, "event: next\ndata: {\"data\":{\"hello\":\"world\"}}\n\n"+
+		"event: complete\ndata:\n\n")
+}
+
+func TestSSEHandler_RejectsRequestsFailingBeforeTheirExecution(t *testing.T) {
+	cases := []struct {
+		request  *http.Request
+		status   int
+		expected string
+	}{
+		{
+			request:  getRequest(url.Values{"query": {`subscription { unknown }`}}),
+			status:   http.StatusBadRequest,
+			expected: `{"errors": [{"message": "Cannot query field \"unknown\" on type \"Subscription\".", "locations": [{"line": 1, "column": 16}]}]}`,
+		},
+		{
+			request:  postRequest(handler.ContentTypeJSON, `{"query": "{ unknown }"}`),
+			status:   http.StatusBadRequest,
+			expected: `{"errors": [{"message": "Cannot query field \"unknown\" on type \"Query\".", "locations": [{"line": 1, "column": 3}]}]}`,
+		},
+		{
+			request: func() *http.Request {
+				r := getRequest(url.Values{"query": {`{ hello }`}})
+				r.Header.Set("Accept", handler.ContentTypeJSON)
+				return r
+			}(),
+			status:   http.StatusNotAcceptable,
+			expected: `{"errors": [{"message": "Accept must allow text/event-stream", "locations": []}]}`,
+		},
+	}
+	for _, c := range cases {
+		w := serveSSE(&handler.Config{}, c.request)
+		body := map[string]interface{}{}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("Unexpected body %q: %v", w.Body.String(), err)
+		}
+		expectResponse(t, w, body, c.status, handler.ContentTypeJSON, c.expected)
+	}
+}
+
+func TestSSEHandler_ValidatesSubscriptionsOnce(t *testing.T) {
+	validations := 0
+	rules := append([]graphql.ValidationRuleFn{func(context *graphql.ValidationContext) *graphql.ValidationRuleInstance {
+		validations++
+		return &graphql.ValidationRuleInstance{}
+	}}, graphql.SpecifiedRules...)
+	cache := graphql.NewLRUDocumentCache(10)
+	for i := 0; i < 2; i++ {
+		r := getRequest(url.Values{"query": {`subscription { count(to: 1) }`}})
+		w := serveSSE(&handler.Config{ValidationRules: rules, DocumentCache: cache}, r)
+		expectEventStream(t, w, "event: next\ndata: {\"data\":{\"count\":1}}\n\n"+
+			"event: complete\ndata:\n\n")
+	}
+	if validations != 1 || cache.Hits() != 1 {
+		t.Fatalf("Expected the subscription to be validated once, got %v validations, %v cache hits", validations, cache.Hits())
+	}
+}
+
+func TestSSEHandler_ResolvesPersistedQueries(t *testing.T) {
+	store := graphql.NewLRUPersistedQueryStore(10)
+	subscription := `subscription { count(to: 1) }`
+	mutation := `mutation { echo(text: "hi") }`
+	store.Add(graphql.PersistedQueryHash(subscription), subscription)
+	store.Add(graphql.PersistedQueryHash(mutation), mutation)
+	invalid := `subscription { unknown }`
+	store.Add(graphql.PersistedQueryHash(invalid), invalid)
+	persistedQuery := func(query string) url.Values {
+		// hashes are looked up case-insensitively
+		hash := strings.ToUpper(graphql.PersistedQueryHash(query))
+		return url.Values{"extensions": {`{"persistedQuery": {"version": 1, "sha256Hash": "` + hash + `"}}`}}
+	}
+
+	w := serveSSE(&handler.Config{PersistedQueries: store}, getRequest(persistedQuery(subscription)))
+	expectEventStream(t, w, "event: next\ndata: {\"data\":{\"count\":1}}\n\n"+
+		"event: complete\ndata:\n\n")
+
+	cases := []struct {
+		request  *http.Request
+		status   int
+		expected string
+	}{
+		{
+			request:  getRequest(persistedQuery(mutation)),
+			status:   http.StatusMethodNotAllowed,
+			expected: `{"errors": [{"message": "Can only perform a mutation operation from a POST request", "locations": []}]}`,
+		},
+		{
+			request:  getRequest(persistedQuery(invalid)),
+			status:   http.StatusBadRequest,
+			expected: `{"errors": [{"message": "Cannot query field \"unknown\" on type \"Subscription\".", "locations": [{"line": 1, "column": 16}]}]}`,
+		},
+		{
+			request:  getRequest(persistedQuery(`subscription { count(to: 2) }`)),
+			status:   http.StatusBadRequest,
+			expected: `{"errors": [{"message": "PersistedQueryNotFound", "locations": [], "extensions": {"code": "PERSISTED_QUERY_NOT_FOUND"}}]}`,
+		},
+	}
+	for _, c := range cases {
+		w := serveSSE(&handler.Config{PersistedQueries: store}, c.request)
+		body := map[string]interface{}{}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("Unexpected body %q: %v", w.Body.String(), err)
+		}
+		expectResponse(t, w, body, c.status, handler.ContentTypeJSON, c.expected)
+	}
+}
+
+func TestSSEHandler_CancelsSubscriptionsOnDisconnect(t *testing.T) {
+	cancelled := make(chan struct{})
+	server := httptest.NewServer(handler.NewSSE(&handler.Config{
+		Schema: &sseTestSchema,
+		ContextFn: func(r *http.Request) context.Context {
+			return context.WithValue(context.Background(), cancelledKey{}, cancelled)
+		},
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(`{"query": "subscription { wait }"}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	r.Header.Set("Content-Type", handler.ContentTypeJSON)
+	response, err := http.DefaultClient.Do(r.WithContext(ctx))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %v", response.StatusCode)
+	}
+	cancel()
+	response.Body.Close()
+	select {
+	case <-cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected the subscription to be cancelled")
+	}
+}
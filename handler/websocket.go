@@ -0,0 +1,391 @@
+package handler
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// websocketGUID is the GUID of the opening handshake, RFC 6455 section 1.3.
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// Opcodes of WebSocket frames.
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+)
+
+// Status codes of WebSocket close frames.
+const (
+	closeNormal         = 1000
+	closeProtocolError  = 1002
+	closeNoStatus       = 1005
+	closeInvalidPayload = 1007
+	closeMessageTooBig  = 1009
+)
+
+// maxUnlimitedMessageBytes is the size of the largest message read by
+// connections setting no limit of their own, as the length of the frames is
+// chosen by the peer.
+const maxUnlimitedMessageBytes = 64 << 20
+
+// writeTimeout is how long writing a frame may take before the connection is
+// given up on.
+const writeTimeout = 10 * time.Second
+
+// closeTimeout is how long a connection waits for its peer to acknowledge the
+// close frame it sent before closing.
+const closeTimeout = time.Second
+
+var errWebSocketClosed = errors.New("websocket: connection closed")
+
+// wsCloseError is a close of a WebSocket connection, either required by a
+// violation of the protocol or received from the peer.
+type wsCloseError struct {
+	code   int
+	reason string
+}
+
+func (e *wsCloseError) Error() string {
+	return "websocket: close " + e.reason
+}
+
+// wsConn is a WebSocket connection, RFC 6455, exchanging messages. Messages
+// may be written concurrently, but must be read by a single goroutine, which
+// also closes the connection.
+type wsConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+
+	// client is whether this is the client end of the connection, which
+	// masks the frames it writes.
+	client bool
+
+	// maxMessageBytes is the size of the largest message read, if positive,
+	// maxUnlimitedMessageBytes otherwise.
+	maxMessageBytes int64
+
+	mu            sync.Mutex
+	sentClose     bool
+	receivedClose bool
+}
+
+// upgradeWebSocket performs the opening handshake of the WebSocket connection
+// requested, selecting the first of the given subprotocols offered by the
+// client, if any. Invalid handshakes get 400 Bad Request, and those whose
+// origin checkOrigin rejects 403 Forbidden.
+func upgradeWebSocket(w http.ResponseWriter, r *http.Request, protocols []string, checkOrigin func(r *http.Request) bool) (*wsConn, string, error) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	switch {
+	case r.Method != http.MethodGet,
+		!headerContainsToken(r.Header, "Connection", "upgrade"),
+		!headerContainsToken(r.Header, "Upgrade", "websocket"):
+		w.Header().Set("Upgrade", "websocket")
+		http.Error(w, "Request must be a WebSocket handshake", http.StatusUpgradeRequired)
+		return nil, "", errors.New("websocket: not a handshake")
+	case r.Header.Get("Sec-WebSocket-Version") != "13":
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "Sec-WebSocket-Version must be 13", http.StatusBadRequest)
+		return nil, "", errors.New("websocket: unsupported version")
+	case key == "":
+		http.Error(w, "Sec-WebSocket-Key must be set", http.StatusBadRequest)
+		return nil, "", errors.New("websocket: missing key")
+	case !checkOrigin(r):
+		http.Error(w, "Origin not allowed", http.StatusForbidden)
+		return nil, "", errors.New("websocket: origin not allowed")
+	}
+
+	protocol := ""
+	for _, offered := range headerTokens(r.Header, "Sec-WebSocket-Protocol") {
+		for _, supported := range protocols {
+			if protocol == "" && offered == supported {
+				protocol = supported
+			}
+		}
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "WebSocket connections are not supported", http.StatusInternalServerError)
+		return nil, "", errors.New("websocket: response does not implement http.Hijacker")
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, "", err
+	}
+	response := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + websocketAccept(key) + "\r\n"
+	if protocol != "" {
+		response += "Sec-WebSocket-Protocol: " + protocol + "\r\n"
+	}
+	if _, err := conn.Write([]byte(response + "\r\n")); err != nil {
+		conn.Close()
+		return nil, "", err
+	}
+	return &wsConn{
+		conn:   conn,
+		reader: rw.Reader,
+	}, protocol, nil
+}
+
+// sameOrigin reports whether the Origin header of the given request, if any,
+// is the host the request was sent to, which browsers set for cross-origin
+// requests.
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Host, r.Host)
+}
+
+// websocketAccept returns the Sec-WebSocket-Accept header of the handshake of
+// the given key.
+func websocketAccept(key string) string {
+	hash := sha1.Sum([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(hash[:])
+}
+
+// headerTokens returns the comma separated tokens of the given header.
+func headerTokens(header http.Header, name string) []string {
+	tokens := []string{}
+	for _, value := range header[http.CanonicalHeaderKey(name)] {
+		for _, token := range strings.Split(value, ",") {
+			if token = strings.TrimSpace(token); token != "" {
+				tokens = append(tokens, token)
+			}
+		}
+	}
+	return tokens
+}
+
+func headerContainsToken(header http.Header, name string, token string) bool {
+	for _, t := range headerTokens(header, name) {
+		if strings.EqualFold(t, token) {
+			return true
+		}
+	}
+	return false
+}
+
+// readMessage returns the next data message of the connection, answering the
+// pings and close frames received meanwhile. A close frame received is
+// returned as a *wsCloseError, as are the violations of the protocol.
+func (c *wsConn) readMessage() ([]byte, error) {
+	var message []byte
+	started := false
+	text := false
+	for {
+		fin, opcode, payload, err := c.readFrame()
+		if err != nil {
+			return nil, err
+		}
+		switch opcode {
+		case opPing:
+			if err := c.writeFrame(opPong, payload); err != nil {
+				return nil, err
+			}
+			continue
+		case opPong:
+			continue
+		case opClose:
+			c.mu.Lock()
+			c.receivedClose = true
+			c.mu.Unlock()
+			closeErr := &wsCloseError{code: closeNoStatus}
+			if len(payload) >= 2 {
+				closeErr.code = int(binary.BigEndian.Uint16(payload))
+				closeErr.reason = string(payload[2:])
+			}
+			return nil, closeErr
+		case opText, opBinary:
+			if started {
+				return nil, &wsCloseError{code: closeProtocolError, reason: "Expected a continuation frame"}
+			}
+			started = true
+			text = opcode == opText
+		case opContinuation:
+			if !started {
+				return nil, &wsCloseError{code: closeProtocolError, reason: "Unexpected continuation frame"}
+			}
+		default:
+			return nil, &wsCloseError{code: closeProtocolError, reason: "Unknown opcode"}
+		}
+		message = append(message, payload...)
+		if int64(len(message)) > c.messageLimit() {
+			return nil, &wsCloseError{code: closeMessageTooBig, reason: "Message too big"}
+		}
+		if fin {
+			if text && !utf8.Valid(message) {
+				return nil, &wsCloseError{code: closeInvalidPayload, reason: "Invalid UTF-8 in text message"}
+			}
+			return message, nil
+		}
+	}
+}
+
+// readFrame returns the next frame of the connection, unmasked.
+func (c *wsConn) readFrame() (fin bool, opcode byte, payload []byte, err error) {
+	header := make([]byte, 2, 8)
+	if _, err := io.ReadFull(c.reader, header); err != nil {
+		return false, 0, nil, err
+	}
+	fin = header[0]&0x80 != 0
+	opcode = header[0] & 0x0f
+	masked := header[1]&0x80 != 0
+	if header[0]&0x70 != 0 {
+		return false, 0, nil, &wsCloseError{code: closeProtocolError, reason: "Reserved bits must be unset"}
+	}
+	if masked == c.client {
+		return false, 0, nil, &wsCloseError{code: closeProtocolError, reason: "Frames must be masked by clients only"}
+	}
+
+	length := uint64(header[1] & 0x7f)
+	switch length {
+	case 126:
+		if _, err := io.ReadFull(c.reader, header[:2]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(header[:2]))
+	case 127:
+		if _, err := io.ReadFull(c.reader, header[:8]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(header[:8])
+	}
+	if opcode >= opClose && (!fin || length > 125) {
+		return false, 0, nil, &wsCloseError{code: closeProtocolError, reason: "Control frames must not be fragmented"}
+	}
+	if length > uint64(c.messageLimit()) {
+		return false, 0, nil, &wsCloseError{code: closeMessageTooBig, reason: "Message too big"}
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(c.reader, mask[:]); err != nil {
+			return false, 0, nil, err
+		}
+	}
+	// the payload grows as it is read rather than trusting the length sent
+	payload, err = ioutil.ReadAll(io.LimitReader(c.reader, int64(length)))
+	if err != nil {
+		return false, 0, nil, err
+	}
+	if uint64(len(payload)) != length {
+		return false, 0, nil, io.ErrUnexpectedEOF
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+	return fin, opcode, payload, nil
+}
+
+// messageLimit returns the size of the largest message read.
+func (c *wsConn) messageLimit() int64 {
+	if c.maxMessageBytes > 0 {
+		return c.maxMessageBytes
+	}
+	return maxUnlimitedMessageBytes
+}
+
+// writeMessage writes the given text message.
+func (c *wsConn) writeMessage(message []byte) error {
+	return c.writeFrame(opText, message)
+}
+
+func (c *wsConn) writeFrame(opcode byte, payload []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.sentClose {
+		return errWebSocketClosed
+	}
+	if opcode == opClose {
+		c.sentClose = true
+	}
+
+	frame := make([]byte, 0, 14+len(payload))
+	frame = append(frame, 0x80|opcode)
+	var maskBit byte
+	if c.client {
+		maskBit = 0x80
+	}
+	switch length := len(payload); {
+	case length <= 125:
+		frame = append(frame, maskBit|byte(length))
+	case length <= 0xffff:
+		frame = append(frame, maskBit|126, 0, 0)
+		binary.BigEndian.PutUint16(frame[len(frame)-2:], uint16(length))
+	default:
+		frame = append(frame, maskBit|127, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(frame[len(frame)-8:], uint64(length))
+	}
+	if c.client {
+		var mask [4]byte
+		if _, err := rand.Read(mask[:]); err != nil {
+			return err
+		}
+		frame = append(frame, mask[:]...)
+		for i, b := range payload {
+			frame = append(frame, b^mask[i%4])
+		}
+	} else {
+		frame = append(frame, payload...)
+	}
+	// a peer which stops reading must not block the writers forever
+	c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	_, err := c.conn.Write(frame)
+	return err
+}
+
+// close performs the closing handshake of the connection with the given code
+// and reason, waiting for the peer to acknowledge the close frame sent unless
+// it sent one itself, and closes it.
+func (c *wsConn) close(code int, reason string) error {
+	payload := []byte{}
+	if code != closeNoStatus {
+		if len(reason) > 123 {
+			reason = reason[:123]
+		}
+		payload = make([]byte, 2, 2+len(reason))
+		binary.BigEndian.PutUint16(payload, uint16(code))
+		payload = append(payload, reason...)
+	}
+	c.writeFrame(opClose, payload)
+
+	c.mu.Lock()
+	receivedClose := c.receivedClose
+	c.mu.Unlock()
+	if !receivedClose {
+		c.conn.SetReadDeadline(time.Now().Add(closeTimeout))
+		for {
+			if _, opcode, _, err := c.readFrame(); err != nil || opcode == opClose {
+				break
+			}
+		}
+	}
+	return c.conn.Close()
+}
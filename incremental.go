@@ -0,0 +1,390 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// IncrementalPayload is a payload delivered after the initial result of an
+// incremental execution, see ExecuteIncremental: the fields of a fragment
+// deferred by @defer, or an item of a list streamed by @stream.
+type IncrementalPayload struct {
+	// Data holds the fields of a deferred fragment, to be merged into the
+	// object at Path. It is nil if a non-null field of the fragment was null.
+	Data interface{}
+
+	// Items holds the item of a streamed list at Path, which is the path of
+	// the list followed by the index of the item. It is nil if the item was
+	// null while the items of the list are non-null.
+	Items []interface{}
+
+	Path   []interface{}
+	Label  string
+	Errors []gqlerrors.FormattedError
+
+	// HasNext is false for the last payload of the execution.
+	HasNext bool
+
+	stream bool
+}
+
+// MarshalJSON encodes the payload as the data of a deferred fragment or the
+// items of a streamed list.
+func (p IncrementalPayload) MarshalJSON() ([]byte, error) {
+	type common struct {
+		Path    []interface{}              `json:"path"`
+		Label   string                     `json:"label,omitempty"`
+		Errors  []gqlerrors.FormattedError `json:"errors,omitempty"`
+		HasNext bool                       `json:"hasNext"`
+	}
+	if p.stream {
+		return json.Marshal(struct {
+			Items []interface{} `json:"items"`
+			common
+		}{p.Items, common{p.Path, p.Label, p.Errors, p.HasNext}})
+	}
+	return json.Marshal(struct {
+		Data interface{} `json:"data"`
+		common
+	}{p.Data, common{p.Path, p.Label, p.Errors, p.HasNext}})
+}
+
+// ExecuteIncremental executes the operation as Execute does, while
+// delivering the fragments deferred by @defer and the items of lists beyond
+// the initialCount of @stream in payloads following the initial result, see
+// IncrementalDirectives. The payloads are received from the returned channel,
+// which is nil when the initial result is complete, in the order they were
+// deferred; the channel is closed after the last one, whose HasNext is false.
+//
+// Deferred fragments and streamed items are executed one after another once
+// the initial result is, until the context of the execution is done. Callers
+// no longer receiving the payloads must cancel the context so the execution
+// stops. Deferred fragments and streamed items beneath a field nulled by an
+// error are not delivered, and extensions are only notified of the execution
+// of the initial result.
+func ExecuteIncremental(p ExecuteParams) (*Result, <-chan *IncrementalPayload) {
+	if p.Context == nil {
+		p.Context = context.Background()
+	}
+	incremental := &incrementalExecution{
+		initial: &incrementalResult{},
+	}
+	p.incremental = incremental
+	result := Execute(p)
+	if incremental.eCtx == nil {
+		return result, nil
+	}
+	incremental.initial.data = result.Data
+	if !incremental.prune() {
+		return result, nil
+	}
+	payloads := make(chan *IncrementalPayload)
+	go incremental.run(p.Context, payloads)
+	return result, payloads
+}
+
+// incrementalExecution holds the work deferred by an incremental execution.
+type incrementalExecution struct {
+	// eCtx is the context of the execution of the initial result, and
+	// initial the initial result.
+	eCtx    *executionContext
+	initial *incrementalResult
+
+	mu      sync.Mutex
+	pending []*incrementalWork
+}
+
+// incrementalResult is the data of the initial result or of a payload,
+// rooted at path, which is known once the fields of the result are executed.
+type incrementalResult struct {
+	path []interface{}
+	data interface{}
+}
+
+// incrementalWork is a deferred fragment or a streamed item, to execute for
+// the payload at path.
+type incrementalWork struct {
+	path   *ResponsePath
+	label  string
+	stream bool
+
+	// within is the result holding the object of the fragment, or the list
+	// of the item.
+	within  *incrementalResult
+	execute func(eCtx *executionContext) interface{}
+}
+
+// deferredFragment is a fragment deferred by @defer.
+type deferredFragment struct {
+	label        string
+	selectionSet *ast.SelectionSet
+
+	// directives are the directives of the fragment and its enclosing
+	// fragments, see collectFieldsParams.Directives.
+	directives []*ast.Directive
+}
+
+// deferLabel returns the label of the fragment used with the given
+// directives, and whether it is deferred.
+func (p collectFieldsParams) deferLabel(directives []*ast.Directive) (string, bool) {
+	if p.Deferred == nil {
+		return "", false
+	}
+	for _, directive := range directives {
+		if directive == nil || directive.Name == nil || directive.Name.Value != DeferDirective.Name {
+			continue
+		}
+		args := getArgumentValues(DeferDirective.Args, directive.Arguments, p.ExeContext.VariableValues)
+		if deferIf, ok := args["if"].(bool); ok && !deferIf {
+			return "", false
+		}
+		label, _ := args["label"].(string)
+		return label, true
+	}
+	return "", false
+}
+
+// streamArgs returns the initialCount and the label of the @stream directive
+// of the list field at the given path, and whether the field is streamed.
+func streamArgs(eCtx *executionContext, fieldASTs []*ast.Field, path *ResponsePath) (int, string, bool) {
+	if eCtx.incremental == nil || path == nil || len(fieldASTs) == 0 {
+		return 0, "", false
+	}
+	// only the outermost list of the field is streamed
+	if _, ok := path.Key.(string); !ok {
+		return 0, "", false
+	}
+	for _, directive := range fieldASTs[0].Directives {
+		if directive == nil || directive.Name == nil || directive.Name.Value != StreamDirective.Name {
+			continue
+		}
+		args := getArgumentValues(StreamDirective.Args, directive.Arguments, eCtx.VariableValues)
+		if streamIf, ok := args["if"].(bool); ok && !streamIf {
+			return 0, "", false
+		}
+		initialCount, _ := args["initialCount"].(int)
+		if initialCount < 0 {
+			panic(gqlerrors.NewFormattedError(
+				fmt.Sprintf(`initialCount of @stream must be a positive integer, got %v.`, initialCount),
+			))
+		}
+		label, _ := args["label"].(string)
+		return initialCount, label, true
+	}
+	return 0, "", false
+}
+
+// deferFragments defers the execution of the given fragments on the object
+// at the given path.
+func (eCtx *executionContext) deferFragments(runtimeType *Object, source interface{}, path *ResponsePath, fragments []*deferredFragment) {
+	for _, fragment := range fragments {
+		fragment := fragment
+		eCtx.deferWork(&incrementalWork{
+			path:  path,
+			label: fragment.label,
+			execute: func(eCtx *executionContext) interface{} {
+				collected := &collectedFields{
+					fragmentDirectives: newFragmentDirectives(eCtx),
+				}
+				fields := map[string][]*ast.Field{}
+				responseNames := []string{}
+				collectFields(collectFieldsParams{
+					ExeContext:         eCtx,
+					RuntimeType:        runtimeType,
+					SelectionSet:       fragment.selectionSet,
+					Fields:             fields,
+					ResponseNames:      &responseNames,
+					Directives:         fragment.directives,
+					FragmentDirectives: collected.fragmentDirectives,
+					Deferred:           &collected.deferred,
+				})
+				collected.fields = orderedFields(fields, responseNames)
+				eCtx.deferFragments(runtimeType, source, path, collected.deferred)
+
+				data := executeSubFields(executeFieldsParams{
+					ExecutionContext:   eCtx,
+					ParentType:         runtimeType,
+					Source:             source,
+					OrderedFields:      collected.fields,
+					Path:               path,
+					FragmentDirectives: collected.fragmentDirectives,
+				})
+				dethunkMapWithBreadthFirstTraversal(eCtx, data)
+				return data
+			},
+		})
+	}
+}
+
+// streamItem defers the completion of the item at the given path of a
+// streamed list.
+func (eCtx *executionContext) streamItem(itemType Type, fieldASTs []*ast.Field, info ResolveInfo, path *ResponsePath, label string, item interface{}) {
+	eCtx.deferWork(&incrementalWork{
+		path:   path,
+		label:  label,
+		stream: true,
+		execute: func(eCtx *executionContext) interface{} {
+			items := []interface{}{
+				completeValueCatchingError(eCtx, itemType, fieldASTs, info, path, item),
+			}
+			dethunkListWithBreadthFirstTraversal(eCtx, items)
+			return items
+		},
+	})
+}
+
+func (eCtx *executionContext) deferWork(work *incrementalWork) {
+	work.within = eCtx.payload
+	eCtx.incremental.mu.Lock()
+	defer eCtx.incremental.mu.Unlock()
+	eCtx.incremental.pending = append(eCtx.incremental.pending, work)
+}
+
+// run executes the pending work, sending a payload for each.
+func (inc *incrementalExecution) run(ctx context.Context, payloads chan<- *IncrementalPayload) {
+	defer close(payloads)
+	for {
+		work := inc.next()
+		if work == nil {
+			return
+		}
+		payload := inc.execute(work)
+		payload.HasNext = inc.prune()
+		select {
+		case payloads <- payload:
+		case <-ctx.Done():
+			return
+		}
+		if !payload.HasNext {
+			return
+		}
+	}
+}
+
+func (inc *incrementalExecution) next() *incrementalWork {
+	inc.mu.Lock()
+	defer inc.mu.Unlock()
+	if len(inc.pending) == 0 {
+		return nil
+	}
+	work := inc.pending[0]
+	inc.pending = inc.pending[1:]
+	return work
+}
+
+// prune drops the pending work beneath fields nulled by errors, and reports
+// whether there is work left. The results holding the pending work are
+// complete, as the work is deferred by the result being executed.
+func (inc *incrementalExecution) prune() bool {
+	inc.mu.Lock()
+	defer inc.mu.Unlock()
+	pending := inc.pending[:0]
+	for _, work := range inc.pending {
+		// streamed items need their list, deferred fragments their object
+		path := work.path
+		if work.stream {
+			path = path.Prev
+		}
+		if work.within.holds(path.AsArray()) {
+			pending = append(pending, work)
+		}
+	}
+	inc.pending = pending
+	return len(pending) > 0
+}
+
+// execute executes the given work for its payload.
+func (inc *incrementalExecution) execute(work *incrementalWork) *IncrementalPayload {
+	payload := &IncrementalPayload{
+		Path:   work.path.AsArray(),
+		Label:  work.label,
+		stream: work.stream,
+	}
+	if payload.Path == nil {
+		payload.Path = []interface{}{}
+	}
+	result := &incrementalResult{path: payload.Path}
+	eCtx := inc.eCtx.fork(result)
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				if r == errFieldCut {
+					return
+				}
+				err, ok := r.(error)
+				if !ok {
+					err = fmt.Errorf("%v", r)
+				}
+				eCtx.addErrors(gqlerrors.FormatError(err))
+			}
+		}()
+		result.data = work.execute(eCtx)
+	}()
+
+	if work.stream {
+		items, _ := result.data.([]interface{})
+		payload.Items = items
+		if len(items) == 1 {
+			// the deferred work within the item is rooted at its path
+			result.data = items[0]
+		}
+	} else {
+		payload.Data = result.data
+	}
+	payload.Errors = eCtx.Errors
+	return payload
+}
+
+// holds reports whether the data of the result has a value at the given
+// path.
+func (r *incrementalResult) holds(path []interface{}) bool {
+	if len(path) < len(r.path) {
+		return false
+	}
+	value := r.data
+	for _, key := range path[len(r.path):] {
+		switch key := key.(type) {
+		case string:
+			switch object := value.(type) {
+			case map[string]interface{}:
+				value = object[key]
+			case *OrderedMap:
+				value, _ = object.Get(key)
+			default:
+				return false
+			}
+		case int:
+			list, ok := value.([]interface{})
+			if !ok || key >= len(list) {
+				return false
+			}
+			value = list[key]
+		}
+	}
+	return value != nil
+}
+
+// fork returns a context executing the fields of the given payload, which
+// records the errors of the payload.
+func (eCtx *executionContext) fork(payload *incrementalResult) *executionContext {
+	return &executionContext{
+		Schema:                eCtx.Schema,
+		Fragments:             eCtx.Fragments,
+		Root:                  eCtx.Root,
+		Operation:             eCtx.Operation,
+		VariableValues:        eCtx.VariableValues,
+		Context:               eCtx.context(),
+		hasDirectiveResolvers: eCtx.hasDirectiveResolvers,
+		dispatcher:            eCtx.dispatcher,
+		ordered:               eCtx.ordered,
+		workers:               eCtx.workers,
+		prepared:              eCtx.prepared,
+		incremental:           eCtx.incremental,
+		payload:               payload,
+	}
+}
@@ -0,0 +1,303 @@
+package graphql_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+type incrementalHero struct {
+	Name    string
+	Friends []*incrementalHero
+}
+
+var incrementalSchema = func() graphql.Schema {
+	heroType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Hero",
+		Fields: graphql.Fields{
+			"name": &graphql.Field{
+				Type: graphql.String,
+			},
+			"slow": &graphql.Field{
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return "slow " + p.Source.(*incrementalHero).Name, nil
+				},
+			},
+			"boom": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return nil, errors.New("boom")
+				},
+			},
+		},
+	})
+	heroType.AddFieldConfig("friends", &graphql.Field{
+		Type: graphql.NewList(heroType),
+	})
+	luke := &incrementalHero{Name: "Luke"}
+	han := &incrementalHero{Name: "Han"}
+	leia := &incrementalHero{Name: "Leia"}
+	luke.Friends = []*incrementalHero{han, leia}
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"hero": &graphql.Field{
+					Type: heroType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return luke, nil
+					},
+				},
+			},
+		}),
+		Directives: append(append([]*graphql.Directive{}, graphql.SpecifiedDirectives...), graphql.IncrementalDirectives...),
+	})
+	if err != nil {
+		panic(err)
+	}
+	return schema
+}()
+
+// executeIncremental executes the query incrementally, returning the initial
+// result and the payloads encoded as JSON.
+func executeIncremental(t *testing.T, query string, args map[string]interface{}) (*graphql.Result, []string) {
+	result, payloads := graphql.ExecuteIncremental(graphql.ExecuteParams{
+		Schema: incrementalSchema,
+		AST:    testutil.TestParse(t, query),
+		Args:   args,
+	})
+	if payloads == nil {
+		return result, nil
+	}
+	encoded := []string{}
+	for payload := range payloads {
+		b, err := json.Marshal(payload)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		encoded = append(encoded, string(b))
+	}
+	return result, encoded
+}
+
+func expectPayloads(t *testing.T, payloads []string, expected []string) {
+	if !reflect.DeepEqual(payloads, expected) {
+		t.Fatalf("Unexpected payloads, Diff: %v", testutil.Diff(expected, payloads))
+	}
+}
+
+func TestExecuteIncremental_DefersFragments(t *testing.T) {
+	result, payloads := executeIncremental(t, `
+		query {
+			hero {
+				name
+				... @defer(label: "slow") {
+					slow
+				}
+				...Friends @defer
+			}
+		}
+		fragment Friends on Hero {
+			friends {
+				name
+			}
+		}
+	`, nil)
+	expected := &graphql.Result{
+		Data: map[string]interface{}{
+			"hero": map[string]interface{}{
+				"name": "Luke",
+			},
+		},
+	}
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+	expectPayloads(t, payloads, []string{
+		`{"data":{"slow":"slow Luke"},"path":["hero"],"label":"slow","hasNext":true}`,
+		`{"data":{"friends":[{"name":"Han"},{"name":"Leia"}]},"path":["hero"],"hasNext":false}`,
+	})
+}
+
+func TestExecuteIncremental_StreamsListItems(t *testing.T) {
+	result, payloads := executeIncremental(t, `
+		query {
+			hero {
+				friends @stream(initialCount: 1, label: "friends") {
+					name
+					... @defer {
+						slow
+					}
+				}
+			}
+		}
+	`, nil)
+	expected := &graphql.Result{
+		Data: map[string]interface{}{
+			"hero": map[string]interface{}{
+				"friends": []interface{}{
+					map[string]interface{}{
+						"name": "Han",
+					},
+				},
+			},
+		},
+	}
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+	expectPayloads(t, payloads, []string{
+		`{"data":{"slow":"slow Han"},"path":["hero","friends",0],"hasNext":true}`,
+		`{"items":[{"name":"Leia"}],"path":["hero","friends",1],"label":"friends","hasNext":true}`,
+		`{"data":{"slow":"slow Leia"},"path":["hero","friends",1],"hasNext":false}`,
+	})
+}
+
+func TestExecuteIncremental_IgnoresDisabledDirectives(t *testing.T) {
+	query := `
+		query ($defer: Boolean!) {
+			hero {
+				friends @stream(if: $defer) {
+					name
+				}
+				... @defer(if: $defer) {
+					slow
+				}
+			}
+		}
+	`
+	expected := &graphql.Result{
+		Data: map[string]interface{}{
+			"hero": map[string]interface{}{
+				"friends": []interface{}{
+					map[string]interface{}{
+						"name": "Han",
+					},
+					map[string]interface{}{
+						"name": "Leia",
+					},
+				},
+				"slow": "slow Luke",
+			},
+		},
+	}
+	result, payloads := executeIncremental(t, query, map[string]interface{}{"defer": false})
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+	if payloads != nil {
+		t.Fatalf("Expected no payloads, got %v", payloads)
+	}
+
+	// Execute does not deliver results incrementally
+	result = graphql.Execute(graphql.ExecuteParams{
+		Schema: incrementalSchema,
+		AST:    testutil.TestParse(t, query),
+		Args:   map[string]interface{}{"defer": true},
+	})
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
+
+func TestExecuteIncremental_ReportsErrorsInPayloads(t *testing.T) {
+	result, payloads := executeIncremental(t, `
+		query {
+			hero {
+				name
+				... @defer {
+					slow
+					boom
+				}
+			}
+		}
+	`, nil)
+	if result.HasErrors() {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	expectPayloads(t, payloads, []string{
+		`{"data":null,"path":["hero"],"errors":[{"message":"boom","locations":[{"line":7,"column":6}],"path":["hero","boom"]}],"hasNext":false}`,
+	})
+}
+
+func TestExecuteIncremental_DropsPayloadsBeneathNulledFields(t *testing.T) {
+	result, payloads := executeIncremental(t, `
+		query {
+			hero {
+				boom
+				... @defer {
+					slow
+				}
+			}
+		}
+	`, nil)
+	if len(result.Errors) != 1 || result.Errors[0].Message != "boom" {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	if payloads != nil {
+		t.Fatalf("Expected no payloads, got %v", payloads)
+	}
+}
+
+func TestExecuteIncremental_PreparedOperationsDeferFragments(t *testing.T) {
+	operation, err := graphql.Prepare(incrementalSchema, testutil.TestParse(t, `
+		query {
+			hero {
+				name
+				... @defer {
+					slow
+				}
+			}
+		}
+	`), "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		result := graphql.Execute(graphql.ExecuteParams{
+			Schema:            incrementalSchema,
+			PreparedOperation: operation,
+		})
+		expected := &graphql.Result{
+			Data: map[string]interface{}{
+				"hero": map[string]interface{}{
+					"name": "Luke",
+					"slow": "slow Luke",
+				},
+			},
+		}
+		if !testutil.EqualResults(expected, result) {
+			t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+		}
+
+		result, payloads := graphql.ExecuteIncremental(graphql.ExecuteParams{
+			Schema:            incrementalSchema,
+			PreparedOperation: operation,
+		})
+		expected = &graphql.Result{
+			Data: map[string]interface{}{
+				"hero": map[string]interface{}{
+					"name": "Luke",
+				},
+			},
+		}
+		if !testutil.EqualResults(expected, result) {
+			t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+		}
+		count := 0
+		for payload := range payloads {
+			if payload.HasNext || payload.Data == nil {
+				t.Fatalf("Unexpected payload: %v", payload)
+			}
+			count++
+		}
+		if count != 1 {
+			t.Fatalf("Expected a payload, got %v", count)
+		}
+	}
+}
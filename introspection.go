@@ -262,14 +262,14 @@ func init() {
 						if isNullish(inputVal.DefaultValue) {
 							return nil, nil
 						}
-						astVal := astFromValue(inputVal.DefaultValue, inputVal)
+						astVal := astFromValue(inputVal.DefaultValue, inputVal.Type)
 						return printer.Print(astVal), nil
 					}
 					if inputVal, ok := p.Source.(*InputObjectField); ok {
 						if inputVal.DefaultValue == nil {
 							return nil, nil
 						}
-						astVal := astFromValue(inputVal.DefaultValue, inputVal)
+						astVal := astFromValue(inputVal.DefaultValue, inputVal.Type)
 						return printer.Print(astVal), nil
 					}
 					return nil, nil
@@ -349,6 +349,9 @@ func init() {
 					NewNonNull(InputValueType),
 				)),
 			},
+			"isRepeatable": &Field{
+				Type: NewNonNull(Boolean),
+			},
 			// NOTE: the following three fields are deprecated and are no longer part
 			// of the GraphQL specification.
 			"onOperation": &Field{
@@ -566,7 +569,10 @@ func init() {
 	TypeType.AddFieldConfig("interfaces", &Field{
 		Type: NewList(NewNonNull(TypeType)),
 		Resolve: func(p ResolveParams) (interface{}, error) {
-			if ttype, ok := p.Source.(*Object); ok {
+			switch ttype := p.Source.(type) {
+			case *Object:
+				return ttype.Interfaces(), nil
+			case *Interface:
 				return ttype.Interfaces(), nil
 			}
 			return nil, nil
@@ -735,8 +741,41 @@ func astFromValue(value interface{}, ttype Type) ast.Value {
 		return val
 	}
 
-	if valueVal.Type().Kind() == reflect.Map {
-		// TODO: implement astFromValue from Map to Value
+	// Enum values are printed by name, the internal value is serialized to it.
+	if ttype, ok := ttype.(*Enum); ok {
+		if name, ok := ttype.Serialize(value).(string); ok {
+			return ast.NewEnumValue(&ast.EnumValue{
+				Value: name,
+			})
+		}
+	}
+
+	// Populate the fields of the input object by creating ASTs from each value
+	// in the map according to the fields in the input type.
+	if ttype, ok := ttype.(*InputObject); ok && valueVal.Kind() == reflect.Map && valueVal.Type().Key().Kind() == reflect.String {
+		fieldNames := []string{}
+		for fieldName := range ttype.Fields() {
+			fieldNames = append(fieldNames, fieldName)
+		}
+		sort.Strings(fieldNames)
+		fields := []*ast.ObjectField{}
+		for _, fieldName := range fieldNames {
+			fieldVal := valueVal.MapIndex(reflect.ValueOf(fieldName).Convert(valueVal.Type().Key()))
+			if !fieldVal.IsValid() {
+				continue
+			}
+			fieldAST := astFromValue(fieldVal.Interface(), ttype.Fields()[fieldName].Type)
+			if fieldAST == nil {
+				continue
+			}
+			fields = append(fields, ast.NewObjectField(&ast.ObjectField{
+				Name:  ast.NewName(&ast.Name{Value: fieldName}),
+				Value: fieldAST,
+			}))
+		}
+		return ast.NewObjectValue(&ast.ObjectValue{
+			Fields: fields,
+		})
 	}
 
 	if value, ok := value.(bool); ok {
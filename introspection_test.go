@@ -669,6 +669,21 @@ func TestIntrospection_ExecutesAnIntrospectionQuery(t *testing.T) {
 							"isDeprecated":      false,
 							"deprecationReason": nil,
 						},
+						map[string]interface{}{
+							"name": "isRepeatable",
+							"args": []interface{}{},
+							"type": map[string]interface{}{
+								"kind": "NON_NULL",
+								"name": nil,
+								"ofType": map[string]interface{}{
+									"kind":   "SCALAR",
+									"name":   "Boolean",
+									"ofType": nil,
+								},
+							},
+							"isDeprecated":      false,
+							"deprecationReason": nil,
+						},
 						map[string]interface{}{
 							"name": "onOperation",
 							"args": []interface{}{},
@@ -789,6 +804,7 @@ func TestIntrospection_ExecutesAnIntrospectionQuery(t *testing.T) {
 							},
 						},
 					},
+					"isRepeatable": false,
 					// deprecated, but included for coverage till removed
 					"onOperation": false,
 					"onFragment":  true,
@@ -816,6 +832,7 @@ func TestIntrospection_ExecutesAnIntrospectionQuery(t *testing.T) {
 							},
 						},
 					},
+					"isRepeatable": false,
 					// deprecated, but included for coverage till removed
 					"onOperation": false,
 					"onFragment":  true,
@@ -828,7 +845,7 @@ func TestIntrospection_ExecutesAnIntrospectionQuery(t *testing.T) {
 		Schema:        emptySchema,
 		RequestString: testutil.IntrospectionQuery,
 	})
-	if !testutil.ContainSubset(result.Data.(map[string]interface{}), expectedDataSubSet) {
+	if !testutil.ContainSubset(result.Data.(*graphql.OrderedMap).Map(), expectedDataSubSet) {
 		t.Fatalf("unexpected, result does not contain subset of expected data")
 	}
 }
@@ -940,7 +957,7 @@ func TestIntrospection_ExecutesAnInputObject(t *testing.T) {
 		Schema:        schema,
 		RequestString: query,
 	})
-	if !testutil.ContainSubset(result.Data.(map[string]interface{}), expectedDataSubSet) {
+	if !testutil.ContainSubset(result.Data.(*graphql.OrderedMap).Map(), expectedDataSubSet) {
 		t.Fatalf("unexpected, result does not contain subset of expected data")
 	}
 }
@@ -1038,7 +1055,7 @@ func TestIntrospection_IdentifiesDeprecatedFields(t *testing.T) {
 		Schema:        schema,
 		RequestString: query,
 	})
-	if !testutil.ContainSubset(result.Data.(map[string]interface{}), expected.Data.(map[string]interface{})) {
+	if !testutil.ContainSubset(result.Data.(*graphql.OrderedMap).Map(), expected.Data.(map[string]interface{})) {
 		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
 	}
 }
@@ -1107,7 +1124,7 @@ func TestIntrospection_RespectsTheIncludeDeprecatedParameterForFields(t *testing
 		Schema:        schema,
 		RequestString: query,
 	})
-	if !testutil.ContainSubset(result.Data.(map[string]interface{}), expected.Data.(map[string]interface{})) {
+	if !testutil.ContainSubset(result.Data.(*graphql.OrderedMap).Map(), expected.Data.(map[string]interface{})) {
 		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
 	}
 }
@@ -1182,7 +1199,7 @@ func TestIntrospection_IdentifiesDeprecatedEnumValues(t *testing.T) {
 		Schema:        schema,
 		RequestString: query,
 	})
-	if !testutil.ContainSubset(result.Data.(map[string]interface{}), expected.Data.(map[string]interface{})) {
+	if !testutil.ContainSubset(result.Data.(*graphql.OrderedMap).Map(), expected.Data.(map[string]interface{})) {
 		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
 	}
 }
@@ -1271,7 +1288,7 @@ func TestIntrospection_RespectsTheIncludeDeprecatedParameterForEnumValues(t *tes
 		Schema:        schema,
 		RequestString: query,
 	})
-	if !testutil.ContainSubset(result.Data.(map[string]interface{}), expected.Data.(map[string]interface{})) {
+	if !testutil.ContainSubset(result.Data.(*graphql.OrderedMap).Map(), expected.Data.(map[string]interface{})) {
 		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
 	}
 }
@@ -1386,7 +1403,7 @@ func TestIntrospection_ExposesDescriptionsOnTypesAndFields(t *testing.T) {
 		Schema:        schema,
 		RequestString: query,
 	})
-	if !testutil.ContainSubset(result.Data.(map[string]interface{}), expected.Data.(map[string]interface{})) {
+	if !testutil.ContainSubset(result.Data.(*graphql.OrderedMap).Map(), expected.Data.(map[string]interface{})) {
 		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
 	}
 }
@@ -1464,7 +1481,7 @@ func TestIntrospection_ExposesDescriptionsOnEnums(t *testing.T) {
 		Schema:        schema,
 		RequestString: query,
 	})
-	if !testutil.ContainSubset(result.Data.(map[string]interface{}), expected.Data.(map[string]interface{})) {
+	if !testutil.ContainSubset(result.Data.(*graphql.OrderedMap).Map(), expected.Data.(map[string]interface{})) {
 		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
 	}
 }
@@ -186,6 +186,234 @@ func (def *TypeExtensionDefinition) GetOperation() string {
 	return ""
 }
 
+// SchemaExtensionDefinition implements Node, Definition
+type SchemaExtensionDefinition struct {
+	Kind       string
+	Loc        *Location
+	Definition *SchemaDefinition
+}
+
+func NewSchemaExtensionDefinition(def *SchemaExtensionDefinition) *SchemaExtensionDefinition {
+	if def == nil {
+		def = &SchemaExtensionDefinition{}
+	}
+	return &SchemaExtensionDefinition{
+		Kind:       kinds.SchemaExtensionDefinition,
+		Loc:        def.Loc,
+		Definition: def.Definition,
+	}
+}
+
+func (def *SchemaExtensionDefinition) GetKind() string {
+	return def.Kind
+}
+
+func (def *SchemaExtensionDefinition) GetLoc() *Location {
+	return def.Loc
+}
+
+func (def *SchemaExtensionDefinition) GetVariableDefinitions() []*VariableDefinition {
+	return []*VariableDefinition{}
+}
+
+func (def *SchemaExtensionDefinition) GetSelectionSet() *SelectionSet {
+	return &SelectionSet{}
+}
+
+func (def *SchemaExtensionDefinition) GetOperation() string {
+	return ""
+}
+
+// ScalarExtensionDefinition implements Node, Definition
+type ScalarExtensionDefinition struct {
+	Kind       string
+	Loc        *Location
+	Definition *ScalarDefinition
+}
+
+func NewScalarExtensionDefinition(def *ScalarExtensionDefinition) *ScalarExtensionDefinition {
+	if def == nil {
+		def = &ScalarExtensionDefinition{}
+	}
+	return &ScalarExtensionDefinition{
+		Kind:       kinds.ScalarExtensionDefinition,
+		Loc:        def.Loc,
+		Definition: def.Definition,
+	}
+}
+
+func (def *ScalarExtensionDefinition) GetKind() string {
+	return def.Kind
+}
+
+func (def *ScalarExtensionDefinition) GetLoc() *Location {
+	return def.Loc
+}
+
+func (def *ScalarExtensionDefinition) GetVariableDefinitions() []*VariableDefinition {
+	return []*VariableDefinition{}
+}
+
+func (def *ScalarExtensionDefinition) GetSelectionSet() *SelectionSet {
+	return &SelectionSet{}
+}
+
+func (def *ScalarExtensionDefinition) GetOperation() string {
+	return ""
+}
+
+// InterfaceExtensionDefinition implements Node, Definition
+type InterfaceExtensionDefinition struct {
+	Kind       string
+	Loc        *Location
+	Definition *InterfaceDefinition
+}
+
+func NewInterfaceExtensionDefinition(def *InterfaceExtensionDefinition) *InterfaceExtensionDefinition {
+	if def == nil {
+		def = &InterfaceExtensionDefinition{}
+	}
+	return &InterfaceExtensionDefinition{
+		Kind:       kinds.InterfaceExtensionDefinition,
+		Loc:        def.Loc,
+		Definition: def.Definition,
+	}
+}
+
+func (def *InterfaceExtensionDefinition) GetKind() string {
+	return def.Kind
+}
+
+func (def *InterfaceExtensionDefinition) GetLoc() *Location {
+	return def.Loc
+}
+
+func (def *InterfaceExtensionDefinition) GetVariableDefinitions() []*VariableDefinition {
+	return []*VariableDefinition{}
+}
+
+func (def *InterfaceExtensionDefinition) GetSelectionSet() *SelectionSet {
+	return &SelectionSet{}
+}
+
+func (def *InterfaceExtensionDefinition) GetOperation() string {
+	return ""
+}
+
+// UnionExtensionDefinition implements Node, Definition
+type UnionExtensionDefinition struct {
+	Kind       string
+	Loc        *Location
+	Definition *UnionDefinition
+}
+
+func NewUnionExtensionDefinition(def *UnionExtensionDefinition) *UnionExtensionDefinition {
+	if def == nil {
+		def = &UnionExtensionDefinition{}
+	}
+	return &UnionExtensionDefinition{
+		Kind:       kinds.UnionExtensionDefinition,
+		Loc:        def.Loc,
+		Definition: def.Definition,
+	}
+}
+
+func (def *UnionExtensionDefinition) GetKind() string {
+	return def.Kind
+}
+
+func (def *UnionExtensionDefinition) GetLoc() *Location {
+	return def.Loc
+}
+
+func (def *UnionExtensionDefinition) GetVariableDefinitions() []*VariableDefinition {
+	return []*VariableDefinition{}
+}
+
+func (def *UnionExtensionDefinition) GetSelectionSet() *SelectionSet {
+	return &SelectionSet{}
+}
+
+func (def *UnionExtensionDefinition) GetOperation() string {
+	return ""
+}
+
+// EnumExtensionDefinition implements Node, Definition
+type EnumExtensionDefinition struct {
+	Kind       string
+	Loc        *Location
+	Definition *EnumDefinition
+}
+
+func NewEnumExtensionDefinition(def *EnumExtensionDefinition) *EnumExtensionDefinition {
+	if def == nil {
+		def = &EnumExtensionDefinition{}
+	}
+	return &EnumExtensionDefinition{
+		Kind:       kinds.EnumExtensionDefinition,
+		Loc:        def.Loc,
+		Definition: def.Definition,
+	}
+}
+
+func (def *EnumExtensionDefinition) GetKind() string {
+	return def.Kind
+}
+
+func (def *EnumExtensionDefinition) GetLoc() *Location {
+	return def.Loc
+}
+
+func (def *EnumExtensionDefinition) GetVariableDefinitions() []*VariableDefinition {
+	return []*VariableDefinition{}
+}
+
+func (def *EnumExtensionDefinition) GetSelectionSet() *SelectionSet {
+	return &SelectionSet{}
+}
+
+func (def *EnumExtensionDefinition) GetOperation() string {
+	return ""
+}
+
+// InputObjectExtensionDefinition implements Node, Definition
+type InputObjectExtensionDefinition struct {
+	Kind       string
+	Loc        *Location
+	Definition *InputObjectDefinition
+}
+
+func NewInputObjectExtensionDefinition(def *InputObjectExtensionDefinition) *InputObjectExtensionDefinition {
+	if def == nil {
+		def = &InputObjectExtensionDefinition{}
+	}
+	return &InputObjectExtensionDefinition{
+		Kind:       kinds.InputObjectExtensionDefinition,
+		Loc:        def.Loc,
+		Definition: def.Definition,
+	}
+}
+
+func (def *InputObjectExtensionDefinition) GetKind() string {
+	return def.Kind
+}
+
+func (def *InputObjectExtensionDefinition) GetLoc() *Location {
+	return def.Loc
+}
+
+func (def *InputObjectExtensionDefinition) GetVariableDefinitions() []*VariableDefinition {
+	return []*VariableDefinition{}
+}
+
+func (def *InputObjectExtensionDefinition) GetSelectionSet() *SelectionSet {
+	return &SelectionSet{}
+}
+
+func (def *InputObjectExtensionDefinition) GetOperation() string {
+	return ""
+}
+
 // DirectiveDefinition implements Node, Definition
 type DirectiveDefinition struct {
 	Kind        string
@@ -193,6 +421,7 @@ type DirectiveDefinition struct {
 	Name        *Name
 	Description *StringValue
 	Arguments   []*InputValueDefinition
+	Repeatable  bool
 	Locations   []*Name
 }
 
@@ -206,6 +435,7 @@ func NewDirectiveDefinition(def *DirectiveDefinition) *DirectiveDefinition {
 		Name:        def.Name,
 		Description: def.Description,
 		Arguments:   def.Arguments,
+		Repeatable:  def.Repeatable,
 		Locations:   def.Locations,
 	}
 }
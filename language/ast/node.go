@@ -42,4 +42,10 @@ var _ Node = (*EnumDefinition)(nil)
 var _ Node = (*EnumValueDefinition)(nil)
 var _ Node = (*InputObjectDefinition)(nil)
 var _ Node = (*TypeExtensionDefinition)(nil)
+var _ Node = (*SchemaExtensionDefinition)(nil)
+var _ Node = (*ScalarExtensionDefinition)(nil)
+var _ Node = (*InterfaceExtensionDefinition)(nil)
+var _ Node = (*UnionExtensionDefinition)(nil)
+var _ Node = (*EnumExtensionDefinition)(nil)
+var _ Node = (*InputObjectExtensionDefinition)(nil)
 var _ Node = (*DirectiveDefinition)(nil)
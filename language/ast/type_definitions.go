@@ -36,6 +36,12 @@ type TypeSystemDefinition interface {
 var _ TypeSystemDefinition = (*SchemaDefinition)(nil)
 var _ TypeSystemDefinition = (TypeDefinition)(nil)
 var _ TypeSystemDefinition = (*TypeExtensionDefinition)(nil)
+var _ TypeSystemDefinition = (*SchemaExtensionDefinition)(nil)
+var _ TypeSystemDefinition = (*ScalarExtensionDefinition)(nil)
+var _ TypeSystemDefinition = (*InterfaceExtensionDefinition)(nil)
+var _ TypeSystemDefinition = (*UnionExtensionDefinition)(nil)
+var _ TypeSystemDefinition = (*EnumExtensionDefinition)(nil)
+var _ TypeSystemDefinition = (*InputObjectExtensionDefinition)(nil)
 var _ TypeSystemDefinition = (*DirectiveDefinition)(nil)
 
 // SchemaDefinition implements Node, Definition
@@ -292,6 +298,7 @@ type InterfaceDefinition struct {
 	Loc         *Location
 	Name        *Name
 	Description *StringValue
+	Interfaces  []*Named
 	Directives  []*Directive
 	Fields      []*FieldDefinition
 }
@@ -305,6 +312,7 @@ func NewInterfaceDefinition(def *InterfaceDefinition) *InterfaceDefinition {
 		Loc:         def.Loc,
 		Name:        def.Name,
 		Description: def.Description,
+		Interfaces:  def.Interfaces,
 		Directives:  def.Directives,
 		Fields:      def.Fields,
 	}
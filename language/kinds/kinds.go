@@ -52,7 +52,13 @@ const (
 	InputObjectDefinition = "InputObjectDefinition" // previously InputObjectTypeDefinition
 
 	// Types Extensions
-	TypeExtensionDefinition = "TypeExtensionDefinition"
+	TypeExtensionDefinition        = "TypeExtensionDefinition" // extends an ObjectDefinition
+	SchemaExtensionDefinition      = "SchemaExtensionDefinition"
+	ScalarExtensionDefinition      = "ScalarExtensionDefinition"
+	InterfaceExtensionDefinition   = "InterfaceExtensionDefinition"
+	UnionExtensionDefinition       = "UnionExtensionDefinition"
+	EnumExtensionDefinition        = "EnumExtensionDefinition"
+	InputObjectExtensionDefinition = "InputObjectExtensionDefinition"
 
 	// Directive Definitions
 	DirectiveDefinition = "DirectiveDefinition"
@@ -78,6 +78,7 @@ const (
 	INPUT        = "input"
 	EXTEND       = "extend"
 	DIRECTIVE    = "directive"
+	REPEATABLE   = "repeatable"
 )
 
 // Token is a representation of a lexed Token. Value only appears for non-punctuation
@@ -91,9 +92,29 @@ type Token struct {
 
 type Lexer func(resetPosition int) (Token, error)
 
-func Lex(s *source.Source) Lexer {
+// LexOptions limits the resources spent lexing a source.
+type LexOptions struct {
+	// MaxTokens, when positive, limits the number of tokens of the source.
+	MaxTokens int
+
+	// MaxBytes, when positive, limits the size of the source in bytes.
+	MaxBytes int
+}
+
+func Lex(s *source.Source, opts ...LexOptions) Lexer {
+	var options LexOptions
+	if len(opts) > 0 {
+		options = opts[0]
+	}
 	var prevPosition int
+	// tokens counts the tokens lexed up to tokensEnd, as a token may be lexed
+	// more than once when looked ahead.
+	var tokens, tokensEnd int
 	return func(resetPosition int) (Token, error) {
+		if options.MaxBytes > 0 && len(s.Body) > options.MaxBytes {
+			description := fmt.Sprintf("Document exceeds the maximum size of %v bytes.", options.MaxBytes)
+			return Token{}, gqlerrors.NewSyntaxError(s, options.MaxBytes, description)
+		}
 		if resetPosition == 0 {
 			resetPosition = prevPosition
 		}
@@ -101,6 +122,13 @@ func Lex(s *source.Source) Lexer {
 		if err != nil {
 			return token, err
 		}
+		if options.MaxTokens > 0 && token.Kind != EOF && token.End > tokensEnd {
+			tokens, tokensEnd = tokens+1, token.End
+			if tokens > options.MaxTokens {
+				description := fmt.Sprintf("Document exceeds the maximum of %v tokens.", options.MaxTokens)
+				return Token{}, gqlerrors.NewSyntaxError(s, token.Start, description)
+			}
+		}
 		prevPosition = token.End
 		return token, nil
 	}
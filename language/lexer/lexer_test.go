@@ -1069,3 +1069,30 @@ func TestLexer_ReportsUsefulInformationForDashesInNames(t *testing.T) {
 		t.Fatalf("unexpected error, token:%v\nexpected:\n%v\n\ngot:\n%v", token, errExpected, err.Error())
 	}
 }
+
+func TestLexer_CountsLookedAheadTokensOnce(t *testing.T) {
+	lex := Lex(createSource("a b"), LexOptions{MaxTokens: 2})
+	a, err := lex(0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// look ahead, then advance
+	for i := 0; i < 2; i++ {
+		if _, err := lex(a.End); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if token, err := lex(0); err != nil || token.Kind != EOF {
+		t.Fatalf("expected EOF, got %v, %v", token, err)
+	}
+
+	lex = Lex(createSource("a b c"), LexOptions{MaxTokens: 2})
+	for i := 0; i < 2; i++ {
+		if _, err := lex(0); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if _, err := lex(0); err == nil {
+		t.Fatalf("expected an error for the third token")
+	}
+}
@@ -44,6 +44,16 @@ func init() {
 type ParseOptions struct {
 	NoLocation bool
 	NoSource   bool
+
+	// MaxTokens, when positive, limits the number of tokens of the source.
+	MaxTokens int
+
+	// MaxDepth, when positive, limits the nesting of selection sets, of list
+	// and object values, and of list types.
+	MaxDepth int
+
+	// MaxBytes, when positive, limits the size of the source in bytes.
+	MaxBytes int
 }
 
 type ParseParams struct {
@@ -57,6 +67,8 @@ type Parser struct {
 	Options  ParseOptions
 	PrevEnd  int
 	Token    lexer.Token
+
+	depth int
 }
 
 func Parse(p ParseParams) (*ast.Document, error) {
@@ -79,8 +91,10 @@ func Parse(p ParseParams) (*ast.Document, error) {
 	return doc, nil
 }
 
-// TODO: test and expose parseValue as a public
-func parseValue(p ParseParams) (ast.Value, error) {
+// ParseValue parses a string containing a GraphQL value (e.g. `[42]`) into
+// its AST node. This is useful within tools that operate upon GraphQL values
+// directly and in isolation of complete GraphQL documents.
+func ParseValue(p ParseParams) (ast.Value, error) {
 	var value ast.Value
 	var sourceObj *source.Source
 	switch src := p.Source.(type) {
@@ -98,6 +112,9 @@ func parseValue(p ParseParams) (ast.Value, error) {
 	if err != nil {
 		return value, err
 	}
+	if _, err = expect(parser, lexer.EOF); err != nil {
+		return value, err
+	}
 	return value, nil
 }
 
@@ -114,7 +131,10 @@ func parseName(parser *Parser) (*ast.Name, error) {
 }
 
 func makeParser(s *source.Source, opts ParseOptions) (*Parser, error) {
-	lexToken := lexer.Lex(s)
+	lexToken := lexer.Lex(s, lexer.LexOptions{
+		MaxTokens: opts.MaxTokens,
+		MaxBytes:  opts.MaxBytes,
+	})
 	token, err := lexToken(0)
 	if err != nil {
 		return &Parser{}, err
@@ -319,6 +339,10 @@ func parseVariable(parser *Parser) (*ast.Variable, error) {
  * SelectionSet : { Selection+ }
  */
 func parseSelectionSet(parser *Parser) (*ast.SelectionSet, error) {
+	if err := enter(parser); err != nil {
+		return nil, err
+	}
+	defer leave(parser)
 	start := parser.Token.Start
 	selections := []ast.Selection{}
 	if iSelections, err := reverse(parser,
@@ -646,6 +670,10 @@ func parseValueValue(parser *Parser) (interface{}, error) {
  *   - [ Value[?Const]+ ]
  */
 func parseList(parser *Parser, isConst bool) (*ast.ListValue, error) {
+	if err := enter(parser); err != nil {
+		return nil, err
+	}
+	defer leave(parser)
 	start := parser.Token.Start
 	var item parseFn = parseValueValue
 	if isConst {
@@ -674,6 +702,10 @@ func parseList(parser *Parser, isConst bool) (*ast.ListValue, error) {
  *   - { ObjectField[?Const]+ }
  */
 func parseObject(parser *Parser, isConst bool) (*ast.ObjectValue, error) {
+	if err := enter(parser); err != nil {
+		return nil, err
+	}
+	defer leave(parser)
 	start := parser.Token.Start
 	if _, err := expect(parser, lexer.BRACE_L); err != nil {
 		return nil, err
@@ -775,6 +807,10 @@ func parseDirective(parser *Parser) (*ast.Directive, error) {
  *   - NonNullType
  */
 func parseType(parser *Parser) (ttype ast.Type, err error) {
+	if err = enter(parser); err != nil {
+		return nil, err
+	}
+	defer leave(parser)
 	token := parser.Token
 	// [ String! ]!
 	switch token.Kind {
@@ -1148,7 +1184,7 @@ func parseInputValueDef(parser *Parser) (interface{}, error) {
 /**
  * InterfaceTypeDefinition :
  *   Description?
- *   interface Name Directives? { FieldDefinition+ }
+ *   interface Name ImplementsInterfaces? Directives? { FieldDefinition+ }
  */
 func parseInterfaceTypeDefinition(parser *Parser) (ast.Node, error) {
 	start := parser.Token.Start
@@ -1164,6 +1200,10 @@ func parseInterfaceTypeDefinition(parser *Parser) (ast.Node, error) {
 	if err != nil {
 		return nil, err
 	}
+	interfaces, err := parseImplementsInterfaces(parser)
+	if err != nil {
+		return nil, err
+	}
 	directives, err := parseDirectives(parser)
 	if err != nil {
 		return nil, err
@@ -1184,6 +1224,7 @@ func parseInterfaceTypeDefinition(parser *Parser) (ast.Node, error) {
 	return ast.NewInterfaceDefinition(&ast.InterfaceDefinition{
 		Name:        name,
 		Description: description,
+		Interfaces:  interfaces,
 		Directives:  directives,
 		Loc:         loc(parser, start),
 		Fields:      fields,
@@ -1365,28 +1406,388 @@ func parseInputObjectTypeDefinition(parser *Parser) (ast.Node, error) {
 }
 
 /**
- * TypeExtensionDefinition : extend ObjectTypeDefinition
+ * TypeExtension :
+ *   - SchemaExtension
+ *   - ScalarTypeExtension
+ *   - ObjectTypeExtension
+ *   - InterfaceTypeExtension
+ *   - UnionTypeExtension
+ *   - EnumTypeExtension
+ *   - InputObjectTypeExtension
  */
 func parseTypeExtensionDefinition(parser *Parser) (ast.Node, error) {
+	keywordToken, err := lookahead(parser)
+	if err != nil {
+		return nil, err
+	}
+	if keywordToken.Kind == lexer.NAME {
+		switch keywordToken.Value {
+		case lexer.SCHEMA:
+			return parseSchemaExtension(parser)
+		case lexer.SCALAR:
+			return parseScalarTypeExtension(parser)
+		case lexer.TYPE:
+			return parseObjectTypeExtension(parser)
+		case lexer.INTERFACE:
+			return parseInterfaceTypeExtension(parser)
+		case lexer.UNION:
+			return parseUnionTypeExtension(parser)
+		case lexer.ENUM:
+			return parseEnumTypeExtension(parser)
+		case lexer.INPUT:
+			return parseInputObjectTypeExtension(parser)
+		}
+	}
+	return nil, unexpected(parser, keywordToken)
+}
+
+/**
+ * SchemaExtension :
+ *   - extend schema Directives? { OperationTypeDefinition+ }
+ *   - extend schema Directives
+ */
+func parseSchemaExtension(parser *Parser) (ast.Node, error) {
 	start := parser.Token.Start
-	_, err := expectKeyWord(parser, lexer.EXTEND)
+	if _, err := expectKeyWord(parser, lexer.EXTEND); err != nil {
+		return nil, err
+	}
+	defStart := parser.Token.Start
+	if _, err := expectKeyWord(parser, lexer.SCHEMA); err != nil {
+		return nil, err
+	}
+	directives, err := parseDirectives(parser)
 	if err != nil {
 		return nil, err
 	}
+	operationTypes := []*ast.OperationTypeDefinition{}
+	if peek(parser, lexer.BRACE_L) {
+		operationTypesI, err := reverse(parser,
+			lexer.BRACE_L, parseOperationTypeDefinition, lexer.BRACE_R,
+			true,
+		)
+		if err != nil {
+			return nil, err
+		}
+		for _, op := range operationTypesI {
+			if op, ok := op.(*ast.OperationTypeDefinition); ok {
+				operationTypes = append(operationTypes, op)
+			}
+		}
+	} else if len(directives) == 0 {
+		return nil, unexpected(parser, lexer.Token{})
+	}
+	return ast.NewSchemaExtensionDefinition(&ast.SchemaExtensionDefinition{
+		Loc: loc(parser, start),
+		Definition: ast.NewSchemaDefinition(&ast.SchemaDefinition{
+			Directives:     directives,
+			OperationTypes: operationTypes,
+			Loc:            loc(parser, defStart),
+		}),
+	}), nil
+}
 
-	definition, err := parseObjectTypeDefinition(parser)
+/**
+ * ScalarTypeExtension : extend scalar Name Directives
+ */
+func parseScalarTypeExtension(parser *Parser) (ast.Node, error) {
+	start := parser.Token.Start
+	if _, err := expectKeyWord(parser, lexer.EXTEND); err != nil {
+		return nil, err
+	}
+	defStart := parser.Token.Start
+	if _, err := expectKeyWord(parser, lexer.SCALAR); err != nil {
+		return nil, err
+	}
+	name, err := parseName(parser)
 	if err != nil {
 		return nil, err
 	}
+	directives, err := parseDirectives(parser)
+	if err != nil {
+		return nil, err
+	}
+	if len(directives) == 0 {
+		return nil, unexpected(parser, lexer.Token{})
+	}
+	return ast.NewScalarExtensionDefinition(&ast.ScalarExtensionDefinition{
+		Loc: loc(parser, start),
+		Definition: ast.NewScalarDefinition(&ast.ScalarDefinition{
+			Name:       name,
+			Directives: directives,
+			Loc:        loc(parser, defStart),
+		}),
+	}), nil
+}
+
+/**
+ * ObjectTypeExtension :
+ *   - extend type Name ImplementsInterfaces? Directives? { FieldDefinition+ }
+ *   - extend type Name ImplementsInterfaces? Directives
+ *   - extend type Name ImplementsInterfaces
+ */
+func parseObjectTypeExtension(parser *Parser) (ast.Node, error) {
+	start := parser.Token.Start
+	if _, err := expectKeyWord(parser, lexer.EXTEND); err != nil {
+		return nil, err
+	}
+	defStart := parser.Token.Start
+	if _, err := expectKeyWord(parser, lexer.TYPE); err != nil {
+		return nil, err
+	}
+	name, err := parseName(parser)
+	if err != nil {
+		return nil, err
+	}
+	interfaces, err := parseImplementsInterfaces(parser)
+	if err != nil {
+		return nil, err
+	}
+	directives, err := parseDirectives(parser)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := parseFieldsDefinition(parser)
+	if err != nil {
+		return nil, err
+	}
+	if len(interfaces) == 0 && len(directives) == 0 && fields == nil {
+		return nil, unexpected(parser, lexer.Token{})
+	}
+	if fields == nil {
+		fields = []*ast.FieldDefinition{}
+	}
 	return ast.NewTypeExtensionDefinition(&ast.TypeExtensionDefinition{
-		Loc:        loc(parser, start),
-		Definition: definition.(*ast.ObjectDefinition),
+		Loc: loc(parser, start),
+		Definition: ast.NewObjectDefinition(&ast.ObjectDefinition{
+			Name:       name,
+			Interfaces: interfaces,
+			Directives: directives,
+			Fields:     fields,
+			Loc:        loc(parser, defStart),
+		}),
+	}), nil
+}
+
+/**
+ * InterfaceTypeExtension :
+ *   - extend interface Name ImplementsInterfaces? Directives? { FieldDefinition+ }
+ *   - extend interface Name ImplementsInterfaces? Directives
+ *   - extend interface Name ImplementsInterfaces
+ */
+func parseInterfaceTypeExtension(parser *Parser) (ast.Node, error) {
+	start := parser.Token.Start
+	if _, err := expectKeyWord(parser, lexer.EXTEND); err != nil {
+		return nil, err
+	}
+	defStart := parser.Token.Start
+	if _, err := expectKeyWord(parser, lexer.INTERFACE); err != nil {
+		return nil, err
+	}
+	name, err := parseName(parser)
+	if err != nil {
+		return nil, err
+	}
+	interfaces, err := parseImplementsInterfaces(parser)
+	if err != nil {
+		return nil, err
+	}
+	directives, err := parseDirectives(parser)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := parseFieldsDefinition(parser)
+	if err != nil {
+		return nil, err
+	}
+	if len(interfaces) == 0 && len(directives) == 0 && fields == nil {
+		return nil, unexpected(parser, lexer.Token{})
+	}
+	if fields == nil {
+		fields = []*ast.FieldDefinition{}
+	}
+	return ast.NewInterfaceExtensionDefinition(&ast.InterfaceExtensionDefinition{
+		Loc: loc(parser, start),
+		Definition: ast.NewInterfaceDefinition(&ast.InterfaceDefinition{
+			Name:       name,
+			Interfaces: interfaces,
+			Directives: directives,
+			Fields:     fields,
+			Loc:        loc(parser, defStart),
+		}),
 	}), nil
 }
 
+/**
+ * UnionTypeExtension :
+ *   - extend union Name Directives? = UnionMembers
+ *   - extend union Name Directives
+ */
+func parseUnionTypeExtension(parser *Parser) (ast.Node, error) {
+	start := parser.Token.Start
+	if _, err := expectKeyWord(parser, lexer.EXTEND); err != nil {
+		return nil, err
+	}
+	defStart := parser.Token.Start
+	if _, err := expectKeyWord(parser, lexer.UNION); err != nil {
+		return nil, err
+	}
+	name, err := parseName(parser)
+	if err != nil {
+		return nil, err
+	}
+	directives, err := parseDirectives(parser)
+	if err != nil {
+		return nil, err
+	}
+	types := []*ast.Named{}
+	if skp, err := skip(parser, lexer.EQUALS); err != nil {
+		return nil, err
+	} else if skp {
+		if types, err = parseUnionMembers(parser); err != nil {
+			return nil, err
+		}
+	} else if len(directives) == 0 {
+		return nil, unexpected(parser, lexer.Token{})
+	}
+	return ast.NewUnionExtensionDefinition(&ast.UnionExtensionDefinition{
+		Loc: loc(parser, start),
+		Definition: ast.NewUnionDefinition(&ast.UnionDefinition{
+			Name:       name,
+			Directives: directives,
+			Types:      types,
+			Loc:        loc(parser, defStart),
+		}),
+	}), nil
+}
+
+/**
+ * EnumTypeExtension :
+ *   - extend enum Name Directives? { EnumValueDefinition+ }
+ *   - extend enum Name Directives
+ */
+func parseEnumTypeExtension(parser *Parser) (ast.Node, error) {
+	start := parser.Token.Start
+	if _, err := expectKeyWord(parser, lexer.EXTEND); err != nil {
+		return nil, err
+	}
+	defStart := parser.Token.Start
+	if _, err := expectKeyWord(parser, lexer.ENUM); err != nil {
+		return nil, err
+	}
+	name, err := parseName(parser)
+	if err != nil {
+		return nil, err
+	}
+	directives, err := parseDirectives(parser)
+	if err != nil {
+		return nil, err
+	}
+	values := []*ast.EnumValueDefinition{}
+	if peek(parser, lexer.BRACE_L) {
+		iEnumValueDefs, err := reverse(parser,
+			lexer.BRACE_L, parseEnumValueDefinition, lexer.BRACE_R,
+			true,
+		)
+		if err != nil {
+			return nil, err
+		}
+		for _, iEnumValueDef := range iEnumValueDefs {
+			if iEnumValueDef != nil {
+				values = append(values, iEnumValueDef.(*ast.EnumValueDefinition))
+			}
+		}
+	} else if len(directives) == 0 {
+		return nil, unexpected(parser, lexer.Token{})
+	}
+	return ast.NewEnumExtensionDefinition(&ast.EnumExtensionDefinition{
+		Loc: loc(parser, start),
+		Definition: ast.NewEnumDefinition(&ast.EnumDefinition{
+			Name:       name,
+			Directives: directives,
+			Values:     values,
+			Loc:        loc(parser, defStart),
+		}),
+	}), nil
+}
+
+/**
+ * InputObjectTypeExtension :
+ *   - extend input Name Directives? { InputValueDefinition+ }
+ *   - extend input Name Directives
+ */
+func parseInputObjectTypeExtension(parser *Parser) (ast.Node, error) {
+	start := parser.Token.Start
+	if _, err := expectKeyWord(parser, lexer.EXTEND); err != nil {
+		return nil, err
+	}
+	defStart := parser.Token.Start
+	if _, err := expectKeyWord(parser, lexer.INPUT); err != nil {
+		return nil, err
+	}
+	name, err := parseName(parser)
+	if err != nil {
+		return nil, err
+	}
+	directives, err := parseDirectives(parser)
+	if err != nil {
+		return nil, err
+	}
+	fields := []*ast.InputValueDefinition{}
+	if peek(parser, lexer.BRACE_L) {
+		iInputValueDefinitions, err := reverse(parser,
+			lexer.BRACE_L, parseInputValueDef, lexer.BRACE_R,
+			true,
+		)
+		if err != nil {
+			return nil, err
+		}
+		for _, iInputValueDefinition := range iInputValueDefinitions {
+			if iInputValueDefinition != nil {
+				fields = append(fields, iInputValueDefinition.(*ast.InputValueDefinition))
+			}
+		}
+	} else if len(directives) == 0 {
+		return nil, unexpected(parser, lexer.Token{})
+	}
+	return ast.NewInputObjectExtensionDefinition(&ast.InputObjectExtensionDefinition{
+		Loc: loc(parser, start),
+		Definition: ast.NewInputObjectDefinition(&ast.InputObjectDefinition{
+			Name:       name,
+			Directives: directives,
+			Fields:     fields,
+			Loc:        loc(parser, defStart),
+		}),
+	}), nil
+}
+
+/**
+ * FieldsDefinition : { FieldDefinition+ }
+ *
+ * Returns nil if the fields are not present, as they are optional in type extensions.
+ */
+func parseFieldsDefinition(parser *Parser) ([]*ast.FieldDefinition, error) {
+	if !peek(parser, lexer.BRACE_L) {
+		return nil, nil
+	}
+	iFields, err := reverse(parser,
+		lexer.BRACE_L, parseFieldDefinition, lexer.BRACE_R,
+		true,
+	)
+	if err != nil {
+		return nil, err
+	}
+	fields := []*ast.FieldDefinition{}
+	for _, iField := range iFields {
+		if iField != nil {
+			fields = append(fields, iField.(*ast.FieldDefinition))
+		}
+	}
+	return fields, nil
+}
+
 /**
  * DirectiveDefinition :
- *   - directive @ Name ArgumentsDefinition? on DirectiveLocations
+ *   - directive @ Name ArgumentsDefinition? repeatable? on DirectiveLocations
  */
 func parseDirectiveDefinition(parser *Parser) (ast.Node, error) {
 	var (
@@ -1394,6 +1795,7 @@ func parseDirectiveDefinition(parser *Parser) (ast.Node, error) {
 		description *ast.StringValue
 		name        *ast.Name
 		args        []*ast.InputValueDefinition
+		repeatable  bool
 		locations   []*ast.Name
 	)
 	start := parser.Token.Start
@@ -1412,6 +1814,9 @@ func parseDirectiveDefinition(parser *Parser) (ast.Node, error) {
 	if args, err = parseArgumentDefs(parser); err != nil {
 		return nil, err
 	}
+	if repeatable, err = skipKeyWord(parser, lexer.REPEATABLE); err != nil {
+		return nil, err
+	}
 	if _, err = expectKeyWord(parser, "on"); err != nil {
 		return nil, err
 	}
@@ -1424,6 +1829,7 @@ func parseDirectiveDefinition(parser *Parser) (ast.Node, error) {
 		Name:        name,
 		Description: description,
 		Arguments:   args,
+		Repeatable:  repeatable,
 		Locations:   locations,
 	}), nil
 }
@@ -1504,6 +1910,23 @@ func advance(parser *Parser) error {
 	return nil
 }
 
+// enter increments the nesting depth of the parser, returning a syntax error
+// at the current token when it exceeds MaxDepth. Each call must be followed by
+// a call of leave.
+func enter(parser *Parser) error {
+	parser.depth++
+	if parser.Options.MaxDepth > 0 && parser.depth > parser.Options.MaxDepth {
+		description := fmt.Sprintf("Document exceeds the maximum nesting depth of %v.", parser.Options.MaxDepth)
+		return gqlerrors.NewSyntaxError(parser.Source, parser.Token.Start, description)
+	}
+	return nil
+}
+
+// leave decrements the nesting depth of the parser.
+func leave(parser *Parser) {
+	parser.depth--
+}
+
 // lookahead retrieves the next token
 func lookahead(parser *Parser) (lexer.Token, error) {
 	return parser.LexToken(parser.Token.End)
@@ -1528,6 +1951,15 @@ func skip(parser *Parser, Kind lexer.TokenKind) (bool, error) {
 	return false, nil
 }
 
+// If the next token is a keyword with the given value, return true after advancing
+// the parser. Otherwise, do not change the parser state and return false.
+func skipKeyWord(parser *Parser, value string) (bool, error) {
+	if parser.Token.Kind == lexer.NAME && parser.Token.Value == value {
+		return true, advance(parser)
+	}
+	return false, nil
+}
+
 // If the next token is of the given kind, return that token after advancing
 // the parser. Otherwise, do not change the parser state and return error.
 func expect(parser *Parser, kind lexer.TokenKind) (lexer.Token, error) {
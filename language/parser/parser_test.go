@@ -800,3 +800,77 @@ func toError(err error) *gqlerrors.Error {
 		return nil
 	}
 }
+
+func TestParseValue(t *testing.T) {
+	value, err := ParseValue(ParseParams{
+		Source:  `{a: [1, "two"], b: ENUM}`,
+		Options: ParseOptions{NoLocation: true},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if printed := printer.Print(value); printed != `{a: [1, "two"], b: ENUM}` {
+		t.Fatalf("unexpected value: %v", printed)
+	}
+
+	_, err = ParseValue(ParseParams{Source: `1 2`})
+	if err == nil || !strings.Contains(err.Error(), `Expected EOF, found Int "2"`) {
+		t.Fatalf("expected error for trailing tokens, got: %v", err)
+	}
+}
+
+func TestParseEnforcesResourceLimits(t *testing.T) {
+	tests := []struct {
+		source          string
+		options         ParseOptions
+		expectedMessage string
+	}{
+		{
+			`{ a b c }`,
+			ParseOptions{MaxTokens: 4},
+			`Syntax Error GraphQL (1:9) Document exceeds the maximum of 4 tokens.`,
+		},
+		{
+			`{ a b c }`,
+			ParseOptions{MaxBytes: 3},
+			`Syntax Error GraphQL (1:4) Document exceeds the maximum size of 3 bytes.`,
+		},
+		{
+			`{ a { b { c } } }`,
+			ParseOptions{MaxDepth: 2},
+			`Syntax Error GraphQL (1:9) Document exceeds the maximum nesting depth of 2.`,
+		},
+		{
+			`{ a(v: [[{ b: 1 }]]) }`,
+			ParseOptions{MaxDepth: 3},
+			`Syntax Error GraphQL (1:10) Document exceeds the maximum nesting depth of 3.`,
+		},
+		{
+			`query ($v: [[Int]]) { a }`,
+			ParseOptions{MaxDepth: 1},
+			`Syntax Error GraphQL (1:13) Document exceeds the maximum nesting depth of 1.`,
+		},
+	}
+	for _, test := range tests {
+		_, err := Parse(ParseParams{Source: test.source, Options: test.options})
+		checkErrorMessage(t, err, test.expectedMessage)
+	}
+
+	_, err := ParseValue(ParseParams{Source: `[[1]]`, Options: ParseOptions{MaxDepth: 1}})
+	checkErrorMessage(t, err, `Syntax Error GraphQL (1:2) Document exceeds the maximum nesting depth of 1.`)
+}
+
+func TestParseAcceptsDocumentsWithinResourceLimits(t *testing.T) {
+	source := `query ($v: [Int]) { a(v: [{ b: 1 }]) { b { c } } }`
+	_, err := Parse(ParseParams{
+		Source: source,
+		Options: ParseOptions{
+			MaxTokens: 29,
+			MaxDepth:  3,
+			MaxBytes:  len(source),
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
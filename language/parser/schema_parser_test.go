@@ -2,6 +2,7 @@ package parser
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/graphql-go/graphql/gqlerrors"
@@ -361,6 +362,7 @@ interface Hello {
 					Value: "Hello",
 					Loc:   testLoc(11, 16),
 				}),
+				Interfaces: []*ast.Named{},
 				Directives: []*ast.Directive{},
 				Fields: []*ast.FieldDefinition{
 					ast.NewFieldDefinition(&ast.FieldDefinition{
@@ -829,3 +831,188 @@ input Hello {
 		t.Fatalf("unexpected document, expected: %v, got: %v", expectedError, err)
 	}
 }
+
+func TestSchemaParser_UnionExtensionWithDirectiveOnly(t *testing.T) {
+	body := `extend union Hello @dir`
+	astDoc := parse(t, body)
+	expected := ast.NewDocument(&ast.Document{
+		Loc: testLoc(0, 23),
+		Definitions: []ast.Node{
+			ast.NewUnionExtensionDefinition(&ast.UnionExtensionDefinition{
+				Loc: testLoc(0, 23),
+				Definition: ast.NewUnionDefinition(&ast.UnionDefinition{
+					Loc: testLoc(7, 23),
+					Name: ast.NewName(&ast.Name{
+						Value: "Hello",
+						Loc:   testLoc(13, 18),
+					}),
+					Directives: []*ast.Directive{
+						ast.NewDirective(&ast.Directive{
+							Loc: testLoc(19, 23),
+							Name: ast.NewName(&ast.Name{
+								Value: "dir",
+								Loc:   testLoc(20, 23),
+							}),
+							Arguments: []*ast.Argument{},
+						}),
+					},
+					Types: []*ast.Named{},
+				}),
+			}),
+		},
+	})
+	if !reflect.DeepEqual(astDoc, expected) {
+		t.Fatalf("unexpected document, expected: %v, got: %v", expected, astDoc)
+	}
+}
+
+func TestSchemaParser_EnumExtension(t *testing.T) {
+	body := `extend enum Hello { WORLD }`
+	astDoc := parse(t, body)
+	expected := ast.NewDocument(&ast.Document{
+		Loc: testLoc(0, 27),
+		Definitions: []ast.Node{
+			ast.NewEnumExtensionDefinition(&ast.EnumExtensionDefinition{
+				Loc: testLoc(0, 27),
+				Definition: ast.NewEnumDefinition(&ast.EnumDefinition{
+					Loc: testLoc(7, 27),
+					Name: ast.NewName(&ast.Name{
+						Value: "Hello",
+						Loc:   testLoc(12, 17),
+					}),
+					Directives: []*ast.Directive{},
+					Values: []*ast.EnumValueDefinition{
+						ast.NewEnumValueDefinition(&ast.EnumValueDefinition{
+							Loc: testLoc(20, 25),
+							Name: ast.NewName(&ast.Name{
+								Value: "WORLD",
+								Loc:   testLoc(20, 25),
+							}),
+							Directives: []*ast.Directive{},
+						}),
+					},
+				}),
+			}),
+		},
+	})
+	if !reflect.DeepEqual(astDoc, expected) {
+		t.Fatalf("unexpected document, expected: %v, got: %v", expected, astDoc)
+	}
+}
+
+func TestSchemaParser_ExtensionsWithoutBodyShouldFail(t *testing.T) {
+	tests := map[string]string{
+		`extend scalar Hello`:    `Syntax Error GraphQL (1:20) Unexpected EOF`,
+		`extend type Hello`:      `Syntax Error GraphQL (1:18) Unexpected EOF`,
+		`extend interface Hello`: `Syntax Error GraphQL (1:23) Unexpected EOF`,
+		`extend union Hello`:     `Syntax Error GraphQL (1:19) Unexpected EOF`,
+		`extend enum Hello`:      `Syntax Error GraphQL (1:18) Unexpected EOF`,
+		`extend input Hello`:     `Syntax Error GraphQL (1:19) Unexpected EOF`,
+		`extend schema`:          `Syntax Error GraphQL (1:14) Unexpected EOF`,
+		`extend query Hello`:     `Syntax Error GraphQL (1:8) Unexpected Name "query"`,
+	}
+	for body, expected := range tests {
+		_, err := Parse(ParseParams{Source: body})
+		if err == nil {
+			t.Fatalf("expected error for %q", body)
+		}
+		if !strings.HasPrefix(err.Error(), expected) {
+			t.Fatalf("unexpected error for %q, expected: %v, got: %v", body, expected, err)
+		}
+	}
+}
+
+func TestSchemaParser_SimpleInterfaceInheritingInterface(t *testing.T) {
+	body := `interface Hello implements World { field: String }`
+	astDoc := parse(t, body)
+	expected := ast.NewDocument(&ast.Document{
+		Loc: testLoc(0, 50),
+		Definitions: []ast.Node{
+			ast.NewInterfaceDefinition(&ast.InterfaceDefinition{
+				Loc: testLoc(0, 50),
+				Name: ast.NewName(&ast.Name{
+					Value: "Hello",
+					Loc:   testLoc(10, 15),
+				}),
+				Interfaces: []*ast.Named{
+					ast.NewNamed(&ast.Named{
+						Name: ast.NewName(&ast.Name{
+							Value: "World",
+							Loc:   testLoc(27, 32),
+						}),
+						Loc: testLoc(27, 32),
+					}),
+				},
+				Directives: []*ast.Directive{},
+				Fields: []*ast.FieldDefinition{
+					ast.NewFieldDefinition(&ast.FieldDefinition{
+						Loc: testLoc(35, 48),
+						Name: ast.NewName(&ast.Name{
+							Value: "field",
+							Loc:   testLoc(35, 40),
+						}),
+						Directives: []*ast.Directive{},
+						Arguments:  []*ast.InputValueDefinition{},
+						Type: ast.NewNamed(&ast.Named{
+							Loc: testLoc(42, 48),
+							Name: ast.NewName(&ast.Name{
+								Value: "String",
+								Loc:   testLoc(42, 48),
+							}),
+						}),
+					}),
+				},
+			}),
+		},
+	})
+	if !reflect.DeepEqual(astDoc, expected) {
+		t.Fatalf("unexpected document, expected: %v, got: %v", expected, astDoc)
+	}
+}
+
+func TestSchemaParser_RepeatableDirectiveDefinition(t *testing.T) {
+	body := `directive @tag repeatable on FIELD`
+	astDoc := parse(t, body)
+	expected := ast.NewDocument(&ast.Document{
+		Loc: testLoc(0, 34),
+		Definitions: []ast.Node{
+			ast.NewDirectiveDefinition(&ast.DirectiveDefinition{
+				Loc: testLoc(0, 34),
+				Name: ast.NewName(&ast.Name{
+					Value: "tag",
+					Loc:   testLoc(11, 14),
+				}),
+				Arguments:  []*ast.InputValueDefinition{},
+				Repeatable: true,
+				Locations: []*ast.Name{
+					ast.NewName(&ast.Name{
+						Value: "FIELD",
+						Loc:   testLoc(29, 34),
+					}),
+				},
+			}),
+		},
+	})
+	if !reflect.DeepEqual(astDoc, expected) {
+		t.Fatalf("unexpected document, expected: %v, got: %v", expected, astDoc)
+	}
+}
+
+func TestSchemaParser_ExtensionsWithEmptyBodyShouldFail(t *testing.T) {
+	tests := map[string]string{
+		`extend type Hello {}`:      `Syntax Error GraphQL (1:19) Unexpected empty IN {}`,
+		`extend interface Hello {}`: `Syntax Error GraphQL (1:24) Unexpected empty IN {}`,
+		`extend enum Hello {}`:      `Syntax Error GraphQL (1:19) Unexpected empty IN {}`,
+		`extend input Hello {}`:     `Syntax Error GraphQL (1:20) Unexpected empty IN {}`,
+		`extend schema {}`:          `Syntax Error GraphQL (1:15) Unexpected empty IN {}`,
+	}
+	for body, expected := range tests {
+		_, err := Parse(ParseParams{Source: body})
+		if err == nil {
+			t.Fatalf("expected error for %q", body)
+		}
+		if !strings.HasPrefix(err.Error(), expected) {
+			t.Fatalf("unexpected error for %q, expected: %v, got: %v", body, expected, err)
+		}
+	}
+}
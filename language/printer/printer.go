@@ -141,6 +141,16 @@ func block(maybeArray interface{}) string {
 	return indent("{\n"+join(s, "\n")) + "\n}"
 }
 
+// definitionBlock returns the block of the fields, values or operation types
+// of a definition, which is omitted when empty from the definition of an
+// extension, as the extension may only add directives or interfaces.
+func definitionBlock(p visitor.VisitFuncParams, maybeArray interface{}) string {
+	if p.Key != "Definition" {
+		return block(maybeArray)
+	}
+	return wrap("{", indent(wrap("\n", join(toSliceString(maybeArray), "\n"), "")), "\n}")
+}
+
 func indent(maybeString interface{}) string {
 	if maybeString == nil {
 		return ""
@@ -375,7 +385,7 @@ var printDocASTReducer = map[string]visitor.VisitFunc{
 		case *ast.StringValue:
 			return visitor.ActionUpdate, strconv.Quote(node.Value)
 		case map[string]interface{}:
-			return visitor.ActionUpdate, `"` + getMapValueString(node, "Value") + `"`
+			return visitor.ActionUpdate, strconv.Quote(getMapValueString(node, "Value"))
 		}
 		return visitor.ActionNoChange, nil
 	},
@@ -484,7 +494,7 @@ var printDocASTReducer = map[string]visitor.VisitFunc{
 			str := join([]string{
 				"schema",
 				join(directives, " "),
-				block(node.OperationTypes),
+				definitionBlock(p, node.OperationTypes),
 			}, " ")
 			return visitor.ActionUpdate, str
 		case map[string]interface{}:
@@ -496,7 +506,7 @@ var printDocASTReducer = map[string]visitor.VisitFunc{
 			str := join([]string{
 				"schema",
 				join(directives, " "),
-				block(operationTypes),
+				definitionBlock(p, operationTypes),
 			}, " ")
 			return visitor.ActionUpdate, str
 		}
@@ -564,7 +574,7 @@ var printDocASTReducer = map[string]visitor.VisitFunc{
 				name,
 				wrap("implements ", join(interfaces, " & "), ""),
 				join(directives, " "),
-				block(fields),
+				definitionBlock(p, fields),
 			}, " ")
 			if desc := getDescription(node); desc != "" {
 				str = fmt.Sprintf("%s\n%s", desc, str)
@@ -583,7 +593,7 @@ var printDocASTReducer = map[string]visitor.VisitFunc{
 				name,
 				wrap("implements ", join(interfaces, " & "), ""),
 				join(directives, " "),
-				block(fields),
+				definitionBlock(p, fields),
 			}, " ")
 			if desc := getDescription(node); desc != "" {
 				str = fmt.Sprintf("%s\n%s", desc, str)
@@ -692,6 +702,7 @@ var printDocASTReducer = map[string]visitor.VisitFunc{
 		switch node := p.Node.(type) {
 		case *ast.InterfaceDefinition:
 			name := fmt.Sprintf("%v", node.Name)
+			interfaces := toSliceString(node.Interfaces)
 			fields := node.Fields
 			directives := []string{}
 			for _, directive := range node.Directives {
@@ -700,8 +711,9 @@ var printDocASTReducer = map[string]visitor.VisitFunc{
 			str := join([]string{
 				"interface",
 				name,
+				wrap("implements ", join(interfaces, " & "), ""),
 				join(directives, " "),
-				block(fields),
+				definitionBlock(p, fields),
 			}, " ")
 			if desc := getDescription(node); desc != "" {
 				str = fmt.Sprintf("%s\n%s", desc, str)
@@ -709,6 +721,7 @@ var printDocASTReducer = map[string]visitor.VisitFunc{
 			return visitor.ActionUpdate, str
 		case map[string]interface{}:
 			name := getMapValueString(node, "Name")
+			interfaces := toSliceString(getMapValue(node, "Interfaces"))
 			fields := getMapValue(node, "Fields")
 			directives := []string{}
 			for _, directive := range getMapSliceValue(node, "Directives") {
@@ -717,8 +730,9 @@ var printDocASTReducer = map[string]visitor.VisitFunc{
 			str := join([]string{
 				"interface",
 				name,
+				wrap("implements ", join(interfaces, " & "), ""),
 				join(directives, " "),
-				block(fields),
+				definitionBlock(p, fields),
 			}, " ")
 			if desc := getDescription(node); desc != "" {
 				str = fmt.Sprintf("%s\n%s", desc, str)
@@ -740,7 +754,7 @@ var printDocASTReducer = map[string]visitor.VisitFunc{
 				"union",
 				name,
 				join(directives, " "),
-				"= " + join(types, " | "),
+				wrap("= ", join(types, " | "), ""),
 			}, " ")
 			if desc := getDescription(node); desc != "" {
 				str = fmt.Sprintf("%s\n%s", desc, str)
@@ -757,7 +771,7 @@ var printDocASTReducer = map[string]visitor.VisitFunc{
 				"union",
 				name,
 				join(directives, " "),
-				"= " + join(types, " | "),
+				wrap("= ", join(types, " | "), ""),
 			}, " ")
 			if desc := getDescription(node); desc != "" {
 				str = fmt.Sprintf("%s\n%s", desc, str)
@@ -779,7 +793,7 @@ var printDocASTReducer = map[string]visitor.VisitFunc{
 				"enum",
 				name,
 				join(directives, " "),
-				block(values),
+				definitionBlock(p, values),
 			}, " ")
 			if desc := getDescription(node); desc != "" {
 				str = fmt.Sprintf("%s\n%s", desc, str)
@@ -796,7 +810,7 @@ var printDocASTReducer = map[string]visitor.VisitFunc{
 				"enum",
 				name,
 				join(directives, " "),
-				block(values),
+				definitionBlock(p, values),
 			}, " ")
 			if desc := getDescription(node); desc != "" {
 				str = fmt.Sprintf("%s\n%s", desc, str)
@@ -851,7 +865,7 @@ var printDocASTReducer = map[string]visitor.VisitFunc{
 				"input",
 				name,
 				join(directives, " "),
-				block(fields),
+				definitionBlock(p, fields),
 			}, " ")
 			if desc := getDescription(node); desc != "" {
 				str = fmt.Sprintf("%s\n%s", desc, str)
@@ -868,7 +882,7 @@ var printDocASTReducer = map[string]visitor.VisitFunc{
 				"input",
 				name,
 				join(directives, " "),
-				block(fields),
+				definitionBlock(p, fields),
 			}, " ")
 			if desc := getDescription(node); desc != "" {
 				str = fmt.Sprintf("%s\n%s", desc, str)
@@ -890,6 +904,84 @@ var printDocASTReducer = map[string]visitor.VisitFunc{
 		}
 		return visitor.ActionNoChange, nil
 	},
+	"SchemaExtensionDefinition": func(p visitor.VisitFuncParams) (string, interface{}) {
+		switch node := p.Node.(type) {
+		case *ast.SchemaExtensionDefinition:
+			definition := fmt.Sprintf("%v", node.Definition)
+			str := "extend " + definition
+			return visitor.ActionUpdate, str
+		case map[string]interface{}:
+			definition := getMapValueString(node, "Definition")
+			str := "extend " + definition
+			return visitor.ActionUpdate, str
+		}
+		return visitor.ActionNoChange, nil
+	},
+	"ScalarExtensionDefinition": func(p visitor.VisitFuncParams) (string, interface{}) {
+		switch node := p.Node.(type) {
+		case *ast.ScalarExtensionDefinition:
+			definition := fmt.Sprintf("%v", node.Definition)
+			str := "extend " + definition
+			return visitor.ActionUpdate, str
+		case map[string]interface{}:
+			definition := getMapValueString(node, "Definition")
+			str := "extend " + definition
+			return visitor.ActionUpdate, str
+		}
+		return visitor.ActionNoChange, nil
+	},
+	"InterfaceExtensionDefinition": func(p visitor.VisitFuncParams) (string, interface{}) {
+		switch node := p.Node.(type) {
+		case *ast.InterfaceExtensionDefinition:
+			definition := fmt.Sprintf("%v", node.Definition)
+			str := "extend " + definition
+			return visitor.ActionUpdate, str
+		case map[string]interface{}:
+			definition := getMapValueString(node, "Definition")
+			str := "extend " + definition
+			return visitor.ActionUpdate, str
+		}
+		return visitor.ActionNoChange, nil
+	},
+	"UnionExtensionDefinition": func(p visitor.VisitFuncParams) (string, interface{}) {
+		switch node := p.Node.(type) {
+		case *ast.UnionExtensionDefinition:
+			definition := fmt.Sprintf("%v", node.Definition)
+			str := "extend " + definition
+			return visitor.ActionUpdate, str
+		case map[string]interface{}:
+			definition := getMapValueString(node, "Definition")
+			str := "extend " + definition
+			return visitor.ActionUpdate, str
+		}
+		return visitor.ActionNoChange, nil
+	},
+	"EnumExtensionDefinition": func(p visitor.VisitFuncParams) (string, interface{}) {
+		switch node := p.Node.(type) {
+		case *ast.EnumExtensionDefinition:
+			definition := fmt.Sprintf("%v", node.Definition)
+			str := "extend " + definition
+			return visitor.ActionUpdate, str
+		case map[string]interface{}:
+			definition := getMapValueString(node, "Definition")
+			str := "extend " + definition
+			return visitor.ActionUpdate, str
+		}
+		return visitor.ActionNoChange, nil
+	},
+	"InputObjectExtensionDefinition": func(p visitor.VisitFuncParams) (string, interface{}) {
+		switch node := p.Node.(type) {
+		case *ast.InputObjectExtensionDefinition:
+			definition := fmt.Sprintf("%v", node.Definition)
+			str := "extend " + definition
+			return visitor.ActionUpdate, str
+		case map[string]interface{}:
+			definition := getMapValueString(node, "Definition")
+			str := "extend " + definition
+			return visitor.ActionUpdate, str
+		}
+		return visitor.ActionNoChange, nil
+	},
 	"DirectiveDefinition": func(p visitor.VisitFuncParams) (string, interface{}) {
 		switch node := p.Node.(type) {
 		case *ast.DirectiveDefinition:
@@ -907,7 +999,11 @@ var printDocASTReducer = map[string]visitor.VisitFunc{
 			} else {
 				argsStr = wrap("(", join(args, ", "), ")")
 			}
-			str := fmt.Sprintf("directive @%v%v on %v", node.Name, argsStr, join(toSliceString(node.Locations), " | "))
+			repeatable := ""
+			if node.Repeatable {
+				repeatable = " repeatable"
+			}
+			str := fmt.Sprintf("directive @%v%v%v on %v", node.Name, argsStr, repeatable, join(toSliceString(node.Locations), " | "))
 			if desc := getDescription(node); desc != "" {
 				str = fmt.Sprintf("%s\n%s", desc, str)
 			}
@@ -929,7 +1025,11 @@ var printDocASTReducer = map[string]visitor.VisitFunc{
 			} else {
 				argsStr = wrap("(", join(args, ", "), ")")
 			}
-			str := fmt.Sprintf("directive @%v%v on %v", name, argsStr, join(locations, " | "))
+			repeatable := ""
+			if isRepeatable, _ := getMapValue(node, "Repeatable").(bool); isRepeatable {
+				repeatable = " repeatable"
+			}
+			str := fmt.Sprintf("directive @%v%v%v on %v", name, argsStr, repeatable, join(locations, " | "))
 			if desc := getDescription(node); desc != "" {
 				str = fmt.Sprintf("%s\n%s", desc, str)
 			}
@@ -80,6 +80,11 @@ interface AnnotatedInterface @onInterface {
   annotatedField(arg: Type @onArg): Type @onField
 }
 
+interface Baz implements Bar {
+  one: Type
+  four(argument: String = "string"): String
+}
+
 union Feed = Story | Article | Advert
 
 union AnnotatedUnion @onUnion = A | B
@@ -111,13 +116,47 @@ extend type Foo {
   seven(argument: [String]): Type
 }
 
-extend type Foo @onType {}
+extend type Foo @onType
+
+extend interface Bar {
+  two(argument: InputType!): Type
+}
+
+extend interface Bar @onInterface
+
+extend interface Bar implements Node
+
+extend union Feed = Photo | Video
+
+extend union Feed @onUnion
+
+extend scalar CustomScalar @onScalar
+
+extend enum Site {
+  VR
+}
+
+extend enum Site @onEnum
+
+extend input InputType {
+  other: Float = 1.5
+}
+
+extend input InputType @onInputObject
+
+extend schema @onSchema
+
+extend schema @onSchema {
+  subscription: SubscriptionType
+}
 
 type NoFields {}
 
 directive @skip(if: Boolean!) on FIELD | FRAGMENT_SPREAD | INLINE_FRAGMENT
 
 directive @include(if: Boolean!) on FIELD | FRAGMENT_SPREAD | INLINE_FRAGMENT
+
+directive @repeatableDirective(value: String) repeatable on FIELD_DEFINITION | OBJECT
 `
 	results := printer.Print(astDoc)
 	if !reflect.DeepEqual(expected, results) {
@@ -0,0 +1,57 @@
+package graphql
+
+import "container/list"
+
+// lru is a map holding a bounded number of entries, evicting the least
+// recently used one when full. It is not safe for concurrent use.
+type lru struct {
+	size     int
+	order    *list.List
+	elements map[interface{}]*list.Element
+}
+
+type lruEntry struct {
+	key   interface{}
+	value interface{}
+}
+
+func newLRU(size int) *lru {
+	return &lru{
+		size:     size,
+		order:    list.New(),
+		elements: map[interface{}]*list.Element{},
+	}
+}
+
+// get returns the value of the given key, if any, marking it as used.
+func (c *lru) get(key interface{}) (interface{}, bool) {
+	element, ok := c.elements[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(*lruEntry).value, true
+}
+
+// add sets the value of the given key, evicting the least recently used entry
+// when full.
+func (c *lru) add(key interface{}, value interface{}) {
+	if c.size <= 0 {
+		return
+	}
+	if element, ok := c.elements[key]; ok {
+		element.Value.(*lruEntry).value = value
+		c.order.MoveToFront(element)
+		return
+	}
+	c.elements[key] = c.order.PushFront(&lruEntry{key: key, value: value})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.elements, oldest.Value.(*lruEntry).key)
+	}
+}
+
+func (c *lru) len() int {
+	return c.order.Len()
+}
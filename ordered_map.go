@@ -0,0 +1,106 @@
+package graphql
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// OrderedMap is a map of response names to values which keeps the order in
+// which they were set. Execution results use it for objects unless unordered
+// results are requested, see ExecuteParams.UnorderedResults, so that the
+// fields of a response appear in the order they were requested, including
+// when marshalled to JSON.
+type OrderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+// NewOrderedMap returns an empty OrderedMap.
+func NewOrderedMap() *OrderedMap {
+	return &OrderedMap{values: map[string]interface{}{}}
+}
+
+// Set sets the value of the given key, adding the key after the existing
+// ones unless it is already set.
+func (m *OrderedMap) Set(key string, value interface{}) {
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+// Get returns the value of the given key and whether it is set.
+func (m *OrderedMap) Get(key string) (interface{}, bool) {
+	value, ok := m.values[key]
+	return value, ok
+}
+
+// Keys returns the keys of the map in order.
+func (m *OrderedMap) Keys() []string {
+	return append([]string{}, m.keys...)
+}
+
+// Len returns the number of keys of the map.
+func (m *OrderedMap) Len() int {
+	return len(m.keys)
+}
+
+// Map returns the content of the map as a map[string]interface{}, converting
+// the nested ordered maps, including those inside lists, as well.
+func (m *OrderedMap) Map() map[string]interface{} {
+	result := make(map[string]interface{}, len(m.keys))
+	for _, key := range m.keys {
+		result[key] = unorderedValue(m.values[key])
+	}
+	return result
+}
+
+func unorderedValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case *OrderedMap:
+		if value == nil {
+			return value
+		}
+		return value.Map()
+	case []interface{}:
+		list := make([]interface{}, len(value))
+		for i, item := range value {
+			list[i] = unorderedValue(item)
+		}
+		return list
+	}
+	return value
+}
+
+// String formats the map as its Map would be.
+func (m *OrderedMap) String() string {
+	return fmt.Sprint(m.Map())
+}
+
+// MarshalJSON marshals the map to a JSON object with its keys in order.
+func (m *OrderedMap) MarshalJSON() ([]byte, error) {
+	if m == nil {
+		return []byte("null"), nil
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		v, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,303 @@
+package graphql
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// PersistedQueryVersion is the version of the automatic persisted queries
+// protocol supported by Do.
+const PersistedQueryVersion = 1
+
+// PersistedQueryError is an error looking the request string of a request up
+// by its hash, its code being reported in the extensions of the GraphQL error.
+type PersistedQueryError struct {
+	Message string
+	Code    string
+}
+
+func (e *PersistedQueryError) Error() string {
+	return e.Message
+}
+
+// Extensions implements gqlerrors.ExtendedError.
+func (e *PersistedQueryError) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code": e.Code,
+	}
+}
+
+var (
+	// ErrPersistedQueryNotFound is reported for requests sending the hash of
+	// a request string that is not stored, which clients retry sending the
+	// request string along with its hash.
+	ErrPersistedQueryNotFound = &PersistedQueryError{
+		Message: "PersistedQueryNotFound",
+		Code:    "PERSISTED_QUERY_NOT_FOUND",
+	}
+
+	// ErrPersistedQueryNotSupported is reported for requests sending the
+	// hash of their request string when neither a PersistedQueryStore nor an
+	// OperationRegistry is set.
+	ErrPersistedQueryNotSupported = &PersistedQueryError{
+		Message: "PersistedQueryNotSupported",
+		Code:    "PERSISTED_QUERY_NOT_SUPPORTED",
+	}
+
+	// ErrPersistedQueryHashMismatch is reported for requests sending a hash
+	// which is not the one of their request string.
+	ErrPersistedQueryHashMismatch = &PersistedQueryError{
+		Message: "provided sha does not match query",
+		Code:    "PERSISTED_QUERY_HASH_MISMATCH",
+	}
+
+	// ErrPersistedQueryInvalid is reported for requests whose persistedQuery
+	// extension is malformed or of an unsupported version.
+	ErrPersistedQueryInvalid = &PersistedQueryError{
+		Message: fmt.Sprintf("Persisted query extension must be of version %v, with a sha256Hash", PersistedQueryVersion),
+		Code:    "PERSISTED_QUERY_INVALID",
+	}
+
+	// ErrOperationNotRegistered is reported for requests whose request
+	// string is not held by the OperationRegistry of their Params.
+	ErrOperationNotRegistered = &PersistedQueryError{
+		Message: "Operation is not registered",
+		Code:    "OPERATION_NOT_REGISTERED",
+	}
+)
+
+// PersistedQueryHash returns the hash identifying the given request string in
+// persisted queries: its SHA-256 hash, hex encoded.
+func PersistedQueryHash(query string) string {
+	hash := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(hash[:])
+}
+
+// PersistedQueryStore stores request strings by their hash, see
+// PersistedQueryHash. Implementations must be safe for concurrent use.
+type PersistedQueryStore interface {
+	Get(hash string) (query string, ok bool)
+	Add(hash string, query string)
+}
+
+// LRUPersistedQueryStore is a PersistedQueryStore holding a bounded number of
+// request strings, evicting the least recently used one when full.
+type LRUPersistedQueryStore struct {
+	mu      sync.Mutex
+	queries *lru
+}
+
+// NewLRUPersistedQueryStore returns a LRUPersistedQueryStore holding at most
+// size request strings.
+func NewLRUPersistedQueryStore(size int) *LRUPersistedQueryStore {
+	return &LRUPersistedQueryStore{
+		queries: newLRU(size),
+	}
+}
+
+// Get returns the request string of the given hash, if stored.
+func (s *LRUPersistedQueryStore) Get(hash string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	query, ok := s.queries.get(hash)
+	if !ok {
+		return "", false
+	}
+	return query.(string), true
+}
+
+// Add stores the request string of the given hash.
+func (s *LRUPersistedQueryStore) Add(hash string, query string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queries.add(hash, query)
+}
+
+// Len returns the number of stored request strings.
+func (s *LRUPersistedQueryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.queries.len()
+}
+
+// OperationRegistry holds the documents a server allows requests to execute,
+// parsed and validated against a schema once registered. Requests select the
+// document either by sending its text or by sending its hash alone, see
+// PersistedQueryHash, and then an operation of it with their OperationName.
+//
+// Documents must not be registered concurrently with the requests using the
+// registry.
+type OperationRegistry struct {
+	schema    Schema
+	rules     []ValidationRuleFn
+	documents map[string]*CachedDocument
+}
+
+// NewOperationRegistry returns an empty OperationRegistry validating the
+// documents it registers against the given schema with the given rules, or
+// SpecifiedRules when empty.
+func NewOperationRegistry(schema Schema, rules []ValidationRuleFn) *OperationRegistry {
+	return &OperationRegistry{
+		schema:    schema,
+		rules:     rules,
+		documents: map[string]*CachedDocument{},
+	}
+}
+
+// Register parses and validates the given document, and registers it unless
+// invalid, returning its hash.
+func (r *OperationRegistry) Register(query string) (string, error) {
+	return r.register("GraphQL request", query)
+}
+
+// RegisterDir registers the documents of the .graphql files of the given
+// directory, see Register, failing on the first invalid one.
+func (r *OperationRegistry) RegisterDir(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.graphql"))
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		body, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if _, err := r.register(path, string(body)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *OperationRegistry) register(name string, query string) (string, error) {
+	document, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{
+			Body: []byte(query),
+			Name: name,
+		}),
+	})
+	if err != nil {
+		return "", err
+	}
+	validationResult := ValidateDocument(&r.schema, document, r.rules)
+	if !validationResult.IsValid {
+		messages := []string{}
+		for _, err := range validationResult.Errors {
+			messages = append(messages, err.Message)
+		}
+		return "", fmt.Errorf("%v: %v", name, strings.Join(messages, " "))
+	}
+	hash := PersistedQueryHash(query)
+	r.documents[hash] = &CachedDocument{
+		Document:         document,
+		ValidationResult: validationResult,
+	}
+	return hash, nil
+}
+
+// Document returns the registered document of the given hash, if any.
+func (r *OperationRegistry) Document(hash string) (*ast.Document, bool) {
+	document, ok := r.documents[strings.ToLower(hash)]
+	if !ok {
+		return nil, false
+	}
+	return document.Document, true
+}
+
+// Len returns the number of registered documents.
+func (r *OperationRegistry) Len() int {
+	return len(r.documents)
+}
+
+// persistedQueryHash returns the hash sent in the persistedQuery extension of
+// the request, if any.
+func persistedQueryHash(extensions map[string]interface{}) (string, bool, error) {
+	extension, ok := extensions["persistedQuery"]
+	if !ok || extension == nil {
+		return "", false, nil
+	}
+	persistedQuery, ok := extension.(map[string]interface{})
+	if !ok {
+		return "", false, ErrPersistedQueryInvalid
+	}
+	switch version := persistedQuery["version"].(type) {
+	case float64:
+		ok = version == PersistedQueryVersion
+	case int:
+		ok = version == PersistedQueryVersion
+	default:
+		ok = false
+	}
+	hash, isString := persistedQuery["sha256Hash"].(string)
+	if !ok || !isString || hash == "" {
+		return "", false, ErrPersistedQueryInvalid
+	}
+	return strings.ToLower(hash), true, nil
+}
+
+// resolvePersistedQuery sets the request string of the given params when
+// sent by hash, following the automatic persisted queries protocol, and
+// returns the registered document of the request when an OperationRegistry
+// is set.
+func resolvePersistedQuery(p *Params) (*CachedDocument, error) {
+	hash, sent, err := persistedQueryHash(p.Extensions)
+	if err != nil {
+		return nil, err
+	}
+	if sent && p.PersistedQueries == nil && p.OperationRegistry == nil {
+		return nil, ErrPersistedQueryNotSupported
+	}
+
+	switch {
+	case sent && p.RequestString == "":
+		if p.OperationRegistry != nil {
+			document, ok := p.OperationRegistry.documents[hash]
+			if !ok {
+				return nil, ErrPersistedQueryNotFound
+			}
+			return document, nil
+		}
+		query, ok := p.PersistedQueries.Get(hash)
+		if !ok {
+			return nil, ErrPersistedQueryNotFound
+		}
+		p.RequestString = query
+		return nil, nil
+	case sent:
+		if PersistedQueryHash(p.RequestString) != hash {
+			return nil, ErrPersistedQueryHashMismatch
+		}
+		if p.OperationRegistry == nil {
+			p.PersistedQueries.Add(hash, p.RequestString)
+			return nil, nil
+		}
+	case p.OperationRegistry != nil:
+		hash = PersistedQueryHash(p.RequestString)
+	default:
+		return nil, nil
+	}
+
+	document, ok := p.OperationRegistry.documents[hash]
+	if !ok {
+		return nil, ErrOperationNotRegistered
+	}
+	return document, nil
+}
+
+// formatPersistedQueryError formats the given error of resolvePersistedQuery,
+// keeping its extensions.
+func formatPersistedQueryError(err error) []gqlerrors.FormattedError {
+	return []gqlerrors.FormattedError{
+		gqlerrors.FormatError(gqlerrors.NewError(err.Error(), nil, "", nil, nil, err)),
+	}
+}
@@ -0,0 +1,211 @@
+package graphql_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+func persistedQueryExtensions(hash string) map[string]interface{} {
+	return map[string]interface{}{
+		"persistedQuery": map[string]interface{}{
+			"version":    float64(1),
+			"sha256Hash": hash,
+		},
+	}
+}
+
+func expectPersistedQueryError(t *testing.T, result *graphql.Result, expected *graphql.PersistedQueryError) {
+	if len(result.Errors) != 1 {
+		t.Fatalf("Expected a single error, got %v", result.Errors)
+	}
+	err := result.Errors[0]
+	if err.Message != expected.Message || err.Extensions["code"] != expected.Code {
+		t.Fatalf("Expected %v, got %v with extensions %v", expected.Message, err.Message, err.Extensions)
+	}
+}
+
+func TestPersistedQuery_StoresQueriesSentWithTheirHash(t *testing.T) {
+	store := graphql.NewLRUPersistedQueryStore(10)
+	query := `{ hero { name } }`
+	hash := graphql.PersistedQueryHash(query)
+	expected := &graphql.Result{
+		Data: map[string]interface{}{
+			"hero": map[string]interface{}{
+				"name": "R2-D2",
+			},
+		},
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:           testutil.StarWarsSchema,
+		Extensions:       persistedQueryExtensions(hash),
+		PersistedQueries: store,
+	})
+	expectPersistedQueryError(t, result, graphql.ErrPersistedQueryNotFound)
+
+	result = graphql.Do(graphql.Params{
+		Schema:           testutil.StarWarsSchema,
+		RequestString:    query,
+		Extensions:       persistedQueryExtensions(hash),
+		PersistedQueries: store,
+	})
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+
+	result = graphql.Do(graphql.Params{
+		Schema:           testutil.StarWarsSchema,
+		Extensions:       persistedQueryExtensions(hash),
+		PersistedQueries: store,
+	})
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+	if store.Len() != 1 {
+		t.Fatalf("Expected a stored query, got %v", store.Len())
+	}
+}
+
+func TestPersistedQuery_RejectsInvalidRequests(t *testing.T) {
+	store := graphql.NewLRUPersistedQueryStore(10)
+	query := `{ hero { name } }`
+	cases := []struct {
+		params   graphql.Params
+		expected *graphql.PersistedQueryError
+	}{
+		{
+			params: graphql.Params{
+				RequestString: query,
+				Extensions:    persistedQueryExtensions(graphql.PersistedQueryHash(query)),
+			},
+			expected: graphql.ErrPersistedQueryNotSupported,
+		},
+		{
+			params: graphql.Params{
+				RequestString:    query,
+				Extensions:       persistedQueryExtensions(graphql.PersistedQueryHash("{ hero { id } }")),
+				PersistedQueries: store,
+			},
+			expected: graphql.ErrPersistedQueryHashMismatch,
+		},
+		{
+			params: graphql.Params{
+				Extensions: map[string]interface{}{
+					"persistedQuery": map[string]interface{}{
+						"version":    float64(2),
+						"sha256Hash": graphql.PersistedQueryHash(query),
+					},
+				},
+				PersistedQueries: store,
+			},
+			expected: graphql.ErrPersistedQueryInvalid,
+		},
+	}
+	for _, c := range cases {
+		c.params.Schema = testutil.StarWarsSchema
+		expectPersistedQueryError(t, graphql.Do(c.params), c.expected)
+	}
+	if store.Len() != 0 {
+		t.Fatalf("Expected no stored query, got %v", store.Len())
+	}
+}
+
+func TestOperationRegistry_OnlyExecutesRegisteredOperations(t *testing.T) {
+	registry := graphql.NewOperationRegistry(testutil.StarWarsSchema, nil)
+	query := `
+		query Hero { hero { name } }
+		query Luke { human(id: "1000") { name } }
+	`
+	hash, err := registry.Register(query)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if hash != graphql.PersistedQueryHash(query) {
+		t.Fatalf("Unexpected hash %v", hash)
+	}
+	expected := &graphql.Result{
+		Data: map[string]interface{}{
+			"human": map[string]interface{}{
+				"name": "Luke Skywalker",
+			},
+		},
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:            testutil.StarWarsSchema,
+		RequestString:     query,
+		OperationName:     "Luke",
+		OperationRegistry: registry,
+	})
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+
+	result = graphql.Do(graphql.Params{
+		Schema:            testutil.StarWarsSchema,
+		OperationName:     "Luke",
+		Extensions:        persistedQueryExtensions(hash),
+		OperationRegistry: registry,
+	})
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+
+	result = graphql.Do(graphql.Params{
+		Schema:            testutil.StarWarsSchema,
+		RequestString:     `{ hero { name } }`,
+		OperationRegistry: registry,
+	})
+	expectPersistedQueryError(t, result, graphql.ErrOperationNotRegistered)
+
+	unregistered := `{ hero { id } }`
+	result = graphql.Do(graphql.Params{
+		Schema:            testutil.StarWarsSchema,
+		RequestString:     unregistered,
+		Extensions:        persistedQueryExtensions(graphql.PersistedQueryHash(unregistered)),
+		PersistedQueries:  graphql.NewLRUPersistedQueryStore(10),
+		OperationRegistry: registry,
+	})
+	expectPersistedQueryError(t, result, graphql.ErrOperationNotRegistered)
+}
+
+func TestOperationRegistry_RegistersTheDocumentsOfADirectory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "operations")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"hero.graphql":  `query Hero { hero { name } }`,
+		"droid.graphql": `query Droid { droid(id: "2001") { primaryFunction } }`,
+		"README.md":     `Not a document`,
+	}
+	for name, body := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(body), 0644); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	registry := graphql.NewOperationRegistry(testutil.StarWarsSchema, nil)
+	if err := registry.RegisterDir(dir); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if registry.Len() != 2 {
+		t.Fatalf("Expected 2 registered documents, got %v", registry.Len())
+	}
+
+	invalid := filepath.Join(dir, "invalid.graphql")
+	if err := ioutil.WriteFile(invalid, []byte(`{ hero { unknown } }`), 0644); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	err = graphql.NewOperationRegistry(testutil.StarWarsSchema, nil).RegisterDir(dir)
+	if err == nil || !strings.HasPrefix(err.Error(), invalid+": ") || !strings.Contains(err.Error(), `Cannot query field "unknown" on type "Character".`) {
+		t.Fatalf("Expected the invalid document to be reported, got %v", err)
+	}
+}
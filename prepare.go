@@ -0,0 +1,245 @@
+package graphql
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// PreparedOperation is an operation of a document prepared for execution
+// against a schema, see Prepare. It is safe for concurrent use.
+type PreparedOperation struct {
+	schema    Schema
+	operation *ast.OperationDefinition
+	fragments map[string]ast.Definition
+	rootType  *Object
+
+	mu     sync.RWMutex
+	fields map[preparedFieldsKey]*collectedFields
+	// stable holds the field ASTs of the prepared fields, whose own
+	// sub-fields can be prepared in turn.
+	stable map[**ast.Field]bool
+}
+
+// preparedFieldsKey identifies the fields collected for a runtime type, from
+// the selection set of an operation or from those of a group of fields, by
+// incremental executions or not.
+type preparedFieldsKey struct {
+	runtimeType  *Object
+	selectionSet *ast.SelectionSet
+	fieldASTs    **ast.Field
+	incremental  bool
+}
+
+// collectedFields are the fields to execute on a runtime type, in order.
+type collectedFields struct {
+	fields []*orderedField
+
+	// fragmentDirectives holds the directives of the fragments the fields
+	// were collected through.
+	fragmentDirectives map[*ast.Field][]*ast.Directive
+
+	// deferred holds the fragments deferred by @defer, whose fields were not
+	// collected.
+	deferred []*deferredFragment
+}
+
+// Prepare prepares the operation of the document with the given name, which
+// may be omitted when the document holds a single operation, for execution
+// against the given schema. Executing a PreparedOperation, see
+// ExecuteParams.PreparedOperation, skips looking the operation and its root
+// type up, and the fields of each selection set are collected, ordered and
+// looked up in the schema once, along with their arguments, when the first
+// execution reaches them, unless they depend on variables.
+//
+// As with Execute, the document should have been validated first.
+func Prepare(schema Schema, document *ast.Document, operationName string) (*PreparedOperation, error) {
+	if document == nil {
+		return nil, errors.New("Must provide document")
+	}
+	operation, fragments, err := getOperation(document, operationName)
+	if err != nil {
+		return nil, err
+	}
+	rootType, err := getOperationRootType(schema, operation)
+	if err != nil {
+		return nil, err
+	}
+	return &PreparedOperation{
+		schema:    schema,
+		operation: operation,
+		fragments: fragments,
+		rootType:  rootType,
+		fields:    map[preparedFieldsKey]*collectedFields{},
+		stable:    map[**ast.Field]bool{},
+	}, nil
+}
+
+// lookup returns the fields prepared for the given key, if any, and whether
+// fields can be prepared for it.
+func (op *PreparedOperation) lookup(key preparedFieldsKey) (*collectedFields, bool) {
+	op.mu.RLock()
+	defer op.mu.RUnlock()
+	if fields, ok := op.fields[key]; ok {
+		return fields, true
+	}
+	return nil, key.fieldASTs == nil || op.stable[key.fieldASTs]
+}
+
+// store prepares the given fields for the given key and returns the fields
+// prepared for it, which were possibly stored concurrently first.
+func (op *PreparedOperation) store(key preparedFieldsKey, fields *collectedFields) *collectedFields {
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	if fields, ok := op.fields[key]; ok {
+		return fields
+	}
+	op.fields[key] = fields
+	for _, field := range fields.fields {
+		op.stable[&field.fieldASTs[0]] = true
+	}
+	return fields
+}
+
+// collectSubFields returns the fields to execute on the given runtime type,
+// collected from the given selection set of an operation, or from the
+// selection sets of the given fields. The fields of prepared operations are
+// collected once.
+func collectSubFields(eCtx *executionContext, runtimeType *Object, selectionSet *ast.SelectionSet, fieldASTs []*ast.Field) *collectedFields {
+	var (
+		key       preparedFieldsKey
+		cacheable bool
+	)
+	if eCtx.prepared != nil {
+		key = preparedFieldsKey{
+			runtimeType:  runtimeType,
+			selectionSet: selectionSet,
+			incremental:  eCtx.incremental != nil,
+		}
+		if len(fieldASTs) > 0 {
+			key.fieldASTs = &fieldASTs[0]
+		}
+		var prepared *collectedFields
+		if prepared, cacheable = eCtx.prepared.lookup(key); prepared != nil {
+			return prepared
+		}
+	}
+
+	usesVariables := false
+	fields := map[string][]*ast.Field{}
+	responseNames := []string{}
+	collected := &collectedFields{
+		fragmentDirectives: newFragmentDirectives(eCtx),
+	}
+	params := collectFieldsParams{
+		ExeContext:           eCtx,
+		RuntimeType:          runtimeType,
+		Fields:               fields,
+		VisitedFragmentNames: map[string]bool{},
+		ResponseNames:        &responseNames,
+		FragmentDirectives:   collected.fragmentDirectives,
+	}
+	if eCtx.incremental != nil {
+		params.Deferred = &collected.deferred
+	}
+	if cacheable {
+		params.UsesVariables = &usesVariables
+	}
+	if selectionSet != nil {
+		params.SelectionSet = selectionSet
+		collectFields(params)
+	}
+	for _, fieldAST := range fieldASTs {
+		if fieldAST == nil || fieldAST.SelectionSet == nil {
+			continue
+		}
+		params.SelectionSet = fieldAST.SelectionSet
+		collectFields(params)
+	}
+	collected.fields = orderedFields(fields, responseNames)
+	if !cacheable || usesVariables {
+		return collected
+	}
+
+	for _, field := range collected.fields {
+		fieldAST := field.fieldASTs[0]
+		if fieldAST.Name == nil {
+			continue
+		}
+		field.fieldDef = getFieldDef(eCtx.Schema, runtimeType, fieldAST.Name.Value)
+		if field.fieldDef != nil && !argumentsUseVariables(fieldAST.Arguments) {
+			field.args = getArgumentValues(field.fieldDef.Args, fieldAST.Arguments, nil)
+		}
+	}
+	return eCtx.prepared.store(key, collected)
+}
+
+// copyArgumentValue returns a copy of the given argument value, copying the
+// input objects and lists it holds too, so that resolvers modifying the
+// arguments of a prepared field do not modify those of other executions.
+func copyArgumentValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(value))
+		for name, v := range value {
+			copied[name] = copyArgumentValue(v)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(value))
+		for i, v := range value {
+			copied[i] = copyArgumentValue(v)
+		}
+		return copied
+	}
+	return value
+}
+
+// inclusionUsesVariables reports whether the given @skip, @include or @defer
+// directives depend on variables.
+func inclusionUsesVariables(directives []*ast.Directive) bool {
+	for _, directive := range directives {
+		if directive == nil || directive.Name == nil {
+			continue
+		}
+		switch directive.Name.Value {
+		case SkipDirective.Name, IncludeDirective.Name, DeferDirective.Name:
+		default:
+			continue
+		}
+		if argumentsUseVariables(directive.Arguments) {
+			return true
+		}
+	}
+	return false
+}
+
+func argumentsUseVariables(arguments []*ast.Argument) bool {
+	for _, argument := range arguments {
+		if argument != nil && valueUsesVariables(argument.Value) {
+			return true
+		}
+	}
+	return false
+}
+
+func valueUsesVariables(value ast.Value) bool {
+	switch value := value.(type) {
+	case *ast.Variable:
+		return true
+	case *ast.ListValue:
+		for _, item := range value.Values {
+			if valueUsesVariables(item) {
+				return true
+			}
+		}
+	case *ast.ObjectValue:
+		for _, field := range value.Fields {
+			if field != nil && valueUsesVariables(field.Value) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,274 @@
+package graphql_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+func preparedTestSchema(t *testing.T) graphql.Schema {
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"greeting": &graphql.Field{
+					Type: graphql.String,
+					Args: graphql.FieldConfigArgument{
+						"name": &graphql.ArgumentConfig{
+							Type:         graphql.String,
+							DefaultValue: "World",
+						},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return fmt.Sprintf("Hello, %v!", p.Args["name"]), nil
+					},
+				},
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Error in schema %v", err.Error())
+	}
+	return schema
+}
+
+func TestPrepare_ExecutesWithDifferentVariables(t *testing.T) {
+	schema := preparedTestSchema(t)
+	operation, err := graphql.Prepare(schema, testutil.TestParse(t, `
+		query Greet($name: String, $formal: Boolean!) {
+			greeting(name: $name)
+			default: greeting
+			... @include(if: $formal) {
+				formal: greeting(name: "Sir")
+			}
+		}
+	`), "Greet")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	cases := []struct {
+		args     map[string]interface{}
+		expected map[string]interface{}
+	}{
+		{
+			args: map[string]interface{}{"name": "Luke", "formal": false},
+			expected: map[string]interface{}{
+				"greeting": "Hello, Luke!",
+				"default":  "Hello, World!",
+			},
+		},
+		{
+			args: map[string]interface{}{"name": "Leia", "formal": true},
+			expected: map[string]interface{}{
+				"greeting": "Hello, Leia!",
+				"default":  "Hello, World!",
+				"formal":   "Hello, Sir!",
+			},
+		},
+		{
+			args: map[string]interface{}{"name": "Han", "formal": false},
+			expected: map[string]interface{}{
+				"greeting": "Hello, Han!",
+				"default":  "Hello, World!",
+			},
+		},
+	}
+	for _, c := range cases {
+		result := graphql.Execute(graphql.ExecuteParams{
+			Schema:            schema,
+			PreparedOperation: operation,
+			Args:              c.args,
+		})
+		if len(result.Errors) > 0 {
+			t.Fatalf("Unexpected errors: %v", result.Errors)
+		}
+		if !testutil.EqualData(c.expected, result.Data) {
+			t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(c.expected, result.Data))
+		}
+	}
+}
+
+func TestPrepare_MatchesTheResultsOfUnpreparedExecutions(t *testing.T) {
+	query := `
+		query HeroFriends($episode: Episode) {
+			hero(episode: $episode) {
+				name
+				... on Droid {
+					primaryFunction
+				}
+				friends {
+					name
+					...HumanFields
+				}
+			}
+		}
+		fragment HumanFields on Human {
+			homePlanet
+		}
+	`
+	document := testutil.TestParse(t, query)
+	operation, err := graphql.Prepare(testutil.StarWarsSchema, document, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, episode := range []string{"NEWHOPE", "EMPIRE", "JEDI", "EMPIRE"} {
+		args := map[string]interface{}{"episode": episode}
+		expected := graphql.Execute(graphql.ExecuteParams{
+			Schema: testutil.StarWarsSchema,
+			AST:    document,
+			Args:   args,
+		})
+		result := graphql.Execute(graphql.ExecuteParams{
+			Schema:            testutil.StarWarsSchema,
+			PreparedOperation: operation,
+			Args:              args,
+		})
+		if !testutil.EqualResults(expected, result) {
+			t.Fatalf("Unexpected result for %v, Diff: %v", episode, testutil.Diff(expected, result))
+		}
+	}
+}
+
+func TestPrepare_ReportsUnknownOperations(t *testing.T) {
+	document := testutil.TestParse(t, `
+		query A { hero { name } }
+		mutation B { hero { name } }
+	`)
+	cases := map[string]string{
+		"":  "Must provide operation name if query contains multiple operations.",
+		"C": `Unknown operation named "C".`,
+		"B": "Schema is not configured for mutations",
+	}
+	for operationName, message := range cases {
+		_, err := graphql.Prepare(testutil.StarWarsSchema, document, operationName)
+		if err == nil || err.Error() != message {
+			t.Fatalf("Expected %q preparing %q, got %v", message, operationName, err)
+		}
+	}
+	if _, err := graphql.Prepare(testutil.StarWarsSchema, nil, ""); err == nil {
+		t.Fatalf("Expected an error preparing no document")
+	}
+}
+
+func TestPrepare_IsSafeForConcurrentExecutions(t *testing.T) {
+	document := testutil.TestParse(t, `
+		query HeroFriends($episode: Episode) {
+			hero(episode: $episode) {
+				name
+				friends {
+					name
+					... on Human { homePlanet }
+					... on Droid { primaryFunction }
+				}
+			}
+		}
+	`)
+	operation, err := graphql.Prepare(testutil.StarWarsSchema, document, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	episodes := []string{"NEWHOPE", "EMPIRE", "JEDI"}
+	expected := map[string]*graphql.Result{}
+	for _, episode := range episodes {
+		expected[episode] = graphql.Execute(graphql.ExecuteParams{
+			Schema: testutil.StarWarsSchema,
+			AST:    document,
+			Args:   map[string]interface{}{"episode": episode},
+		})
+	}
+
+	var wg sync.WaitGroup
+	results := make([]*graphql.Result, 30)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = graphql.Execute(graphql.ExecuteParams{
+				Schema:            testutil.StarWarsSchema,
+				PreparedOperation: operation,
+				Args:              map[string]interface{}{"episode": episodes[i%len(episodes)]},
+				Concurrency:       4,
+			})
+		}(i)
+	}
+	wg.Wait()
+	for i, result := range results {
+		episode := episodes[i%len(episodes)]
+		if !testutil.EqualResults(expected[episode], result) {
+			t.Fatalf("Unexpected result for %v, Diff: %v", episode, testutil.Diff(expected[episode], result))
+		}
+	}
+}
+
+func TestPrepare_RejectsExecutionsAgainstAnotherSchema(t *testing.T) {
+	operation, err := graphql.Prepare(preparedTestSchema(t), testutil.TestParse(t, `{ greeting }`), "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema:            preparedTestSchema(t),
+		PreparedOperation: operation,
+	})
+	if result.Data != nil || len(result.Errors) != 1 || result.Errors[0].Message != "PreparedOperation was prepared against another schema" {
+		t.Fatalf("Unexpected result: %v", result)
+	}
+}
+
+func TestPrepare_CopiesTheNestedArgumentsOfEachExecution(t *testing.T) {
+	input := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "Input",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"names": &graphql.InputObjectFieldConfig{
+				Type: graphql.NewList(graphql.String),
+			},
+		},
+	})
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"names": &graphql.Field{
+					Type: graphql.String,
+					Args: graphql.FieldConfigArgument{
+						"input": &graphql.ArgumentConfig{
+							Type: input,
+						},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						input := p.Args["input"].(map[string]interface{})
+						names := input["names"].([]interface{})
+						result := fmt.Sprint(names)
+						// modifies the arguments it was given
+						names[0] = "modified"
+						input["names"] = append(names, "added")
+						return result, nil
+					},
+				},
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Error in schema %v", err.Error())
+	}
+	operation, err := graphql.Prepare(schema, testutil.TestParse(t, `{ names(input: {names: ["a", "b"]}) }`), "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := &graphql.Result{
+		Data: map[string]interface{}{
+			"names": "[a b]",
+		},
+	}
+	for i := 0; i < 2; i++ {
+		result := graphql.Execute(graphql.ExecuteParams{
+			Schema:            schema,
+			PreparedOperation: operation,
+		})
+		if !testutil.EqualResults(expected, result) {
+			t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+		}
+	}
+}
@@ -9,14 +9,7 @@ import (
 )
 
 func TestRace(t *testing.T) {
-	tempdir, err := ioutil.TempDir("", "race")
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer os.RemoveAll(tempdir)
-
-	filename := filepath.Join(tempdir, "example.go")
-	err = ioutil.WriteFile(filename, []byte(`
+	testRace(t, `
 		package main
 
 		import (
@@ -51,7 +44,79 @@ func TestRace(t *testing.T) {
 
 			wg.Wait()
 		} 
-	`), 0755)
+	`)
+}
+
+func TestRace_CancelledExecution(t *testing.T) {
+	testRace(t, `
+		package main
+
+		import (
+			"context"
+			"time"
+
+			"github.com/graphql-go/graphql"
+		)
+
+		func main() {
+			itemType := graphql.NewObject(graphql.ObjectConfig{
+				Name: "Item",
+				Fields: graphql.Fields{
+					"slow": &graphql.Field{
+						Type: graphql.String,
+						Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+							time.Sleep(time.Millisecond)
+							return "slow", nil
+						},
+					},
+					"stuck": &graphql.Field{
+						Type:    graphql.String,
+						Timeout: time.Millisecond,
+						Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+							time.Sleep(time.Second)
+							return "stuck", nil
+						},
+					},
+				},
+			})
+			schema, _ := graphql.NewSchema(graphql.SchemaConfig{
+				Query: graphql.NewObject(graphql.ObjectConfig{
+					Name: "Query",
+					Fields: graphql.Fields{
+						"items": &graphql.Field{
+							Type: graphql.NewList(itemType),
+							Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+								return make([]int, 100), nil
+							},
+						},
+					},
+				}),
+			})
+			for _, concurrency := range []int{0, 4} {
+				ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+				graphql.Do(graphql.Params{
+					Schema:        schema,
+					RequestString: "{ items { slow stuck } }",
+					Context:       ctx,
+					Concurrency:   concurrency,
+				})
+				cancel()
+			}
+		}
+	`)
+}
+
+// testRace runs the given program with the race detector, which must not
+// report anything.
+func testRace(t *testing.T, program string) {
+	tempdir, err := ioutil.TempDir("", "race")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempdir)
+
+	filename := filepath.Join(tempdir, "example.go")
+	err = ioutil.WriteFile(filename, []byte(program), 0755)
 	if err != nil {
 		t.Fatal(err)
 	}
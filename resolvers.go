@@ -0,0 +1,295 @@
+package graphql
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// Resolvers maps type names to the functions bound to that type by BindResolvers.
+//
+// The value for a type is either a map[string]interface{} keyed by field name,
+// or any other Go value whose methods are bound by name.
+//
+// In a map, a field is bound to a FieldResolveFn, a
+// func(ResolveParams) (interface{}, error) or a *FieldResolvers, while the
+// following keys bind type level functions:
+//
+//	"__resolveType"  (Interface and Union)   ResolveTypeFn
+//	"__isTypeOf"     (Object)                IsTypeOfFn
+//	"__serialize"    (Scalar)                SerializeFn
+//	"__parseValue"   (Scalar)                ParseValueFn
+//	"__parseLiteral" (Scalar)                ParseLiteralFn
+//
+// For any other Go value, a method named after a field with its first letter
+// upper-cased (e.g. `User` for the field `user`) is bound as the field's
+// resolver and must have the signature of a FieldResolveFn. Methods named
+// ResolveType, IsTypeOf, Serialize, ParseValue and ParseLiteral with the
+// signatures above bind the type level functions.
+type Resolvers map[string]interface{}
+
+// FieldResolvers binds both the resolve and subscribe functions of a field.
+type FieldResolvers struct {
+	Resolve   FieldResolveFn
+	Subscribe FieldResolveFn
+}
+
+// BindOptions options for BindResolvers.
+type BindOptions struct {
+	// RequireResolvers makes BindResolvers fail when a field of an Object type
+	// is left on the DefaultResolveFn, see DefaultResolvedFields.
+	RequireResolvers bool
+}
+
+// BindResolvers binds the given resolvers to the types of the schema, which is
+// typically built with BuildSchema. It fails for types and fields which are
+// not in the schema and for values which cannot be bound to their slot.
+func BindResolvers(schema *Schema, resolvers Resolvers, opts BindOptions) error {
+	if err := invariant(schema != nil, "Must provide a schema to bind resolvers to."); err != nil {
+		return err
+	}
+
+	typeNames := make([]string, 0, len(resolvers))
+	for typeName := range resolvers {
+		typeNames = append(typeNames, typeName)
+	}
+	sort.Strings(typeNames)
+
+	for _, typeName := range typeNames {
+		ttype := schema.Type(typeName)
+		if ttype == nil {
+			return fmt.Errorf(`Type "%v" defined in resolvers, but not in schema.`, typeName)
+		}
+		if strings.HasPrefix(typeName, "__") {
+			return fmt.Errorf(`Cannot bind resolvers to introspection type "%v".`, typeName)
+		}
+		for _, scalar := range specifiedScalarTypes {
+			if ttype == scalar {
+				return fmt.Errorf(`Cannot bind resolvers to built-in scalar "%v".`, typeName)
+			}
+		}
+
+		var err error
+		switch value := resolvers[typeName].(type) {
+		case nil:
+			err = fmt.Errorf(`Resolvers for type "%v" must not be nil.`, typeName)
+		case map[string]interface{}:
+			err = bindResolverMap(ttype, value)
+		default:
+			err = bindResolverMethods(ttype, value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if opts.RequireResolvers {
+		if fields := DefaultResolvedFields(schema); len(fields) > 0 {
+			return fmt.Errorf("Resolvers missing for fields: %v.", strings.Join(fields, ", "))
+		}
+	}
+	return nil
+}
+
+// DefaultResolvedFields returns the sorted coordinates (`Type.field`) of the
+// fields of Object types in the schema which have no resolver and so resolve
+// with the DefaultResolveFn.
+func DefaultResolvedFields(schema *Schema) []string {
+	fields := []string{}
+	for typeName, ttype := range schema.TypeMap() {
+		object, ok := ttype.(*Object)
+		if !ok || strings.HasPrefix(typeName, "__") {
+			continue
+		}
+		for fieldName, field := range object.Fields() {
+			if field.Resolve == nil {
+				fields = append(fields, typeName+"."+fieldName)
+			}
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func bindResolverMap(ttype Type, resolvers map[string]interface{}) error {
+	names := make([]string, 0, len(resolvers))
+	for name := range resolvers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value := resolvers[name]
+		if strings.HasPrefix(name, "__") {
+			if err := bindTypeResolver(ttype, name, value); err != nil {
+				return err
+			}
+			continue
+		}
+
+		object, ok := ttype.(*Object)
+		if !ok {
+			return fmt.Errorf(`%v.%v defined in resolvers, but %v is not an Object type.`, ttype, name, ttype)
+		}
+		field, ok := object.Fields()[name]
+		if !ok {
+			return fmt.Errorf(`%v.%v defined in resolvers, but not in schema.`, ttype, name)
+		}
+		switch value := value.(type) {
+		case FieldResolveFn:
+			field.Resolve = value
+		case func(ResolveParams) (interface{}, error):
+			field.Resolve = value
+		case *FieldResolvers:
+			if value != nil {
+				field.Resolve = value.Resolve
+				field.Subscribe = value.Subscribe
+			}
+		case FieldResolvers:
+			field.Resolve = value.Resolve
+			field.Subscribe = value.Subscribe
+		default:
+			return fmt.Errorf(`Resolver for %v.%v must be a FieldResolveFn or *FieldResolvers, got %T.`, ttype, name, value)
+		}
+	}
+	return nil
+}
+
+func bindTypeResolver(ttype Type, name string, value interface{}) error {
+	unexpected := func() error {
+		return fmt.Errorf(`Cannot bind %v of %v, unexpected function type %T.`, name, ttype, value)
+	}
+	ok := false
+	switch name {
+	case "__resolveType":
+		var fn ResolveTypeFn
+		switch value := value.(type) {
+		case ResolveTypeFn:
+			fn, ok = value, true
+		case func(ResolveTypeParams) *Object:
+			fn, ok = value, true
+		}
+		switch ttype := ttype.(type) {
+		case *Interface:
+			if !ok {
+				return unexpected()
+			}
+			ttype.ResolveType = fn
+		case *Union:
+			if !ok {
+				return unexpected()
+			}
+			ttype.ResolveType = fn
+		default:
+			return fmt.Errorf(`Cannot bind %v to %v, it is not an Interface or Union type.`, name, ttype)
+		}
+	case "__isTypeOf":
+		var fn IsTypeOfFn
+		switch value := value.(type) {
+		case IsTypeOfFn:
+			fn, ok = value, true
+		case func(IsTypeOfParams) bool:
+			fn, ok = value, true
+		}
+		object, isObject := ttype.(*Object)
+		if !isObject {
+			return fmt.Errorf(`Cannot bind %v to %v, it is not an Object type.`, name, ttype)
+		}
+		if !ok {
+			return unexpected()
+		}
+		object.IsTypeOf = fn
+	case "__serialize", "__parseValue":
+		var fn func(interface{}) interface{}
+		switch value := value.(type) {
+		case SerializeFn:
+			fn, ok = value, true
+		case ParseValueFn:
+			fn, ok = value, true
+		case func(interface{}) interface{}:
+			fn, ok = value, true
+		}
+		scalar, isScalar := ttype.(*Scalar)
+		if !isScalar {
+			return fmt.Errorf(`Cannot bind %v to %v, it is not a Scalar type.`, name, ttype)
+		}
+		if !ok {
+			return unexpected()
+		}
+		if name == "__serialize" {
+			scalar.scalarConfig.Serialize = fn
+		} else {
+			scalar.scalarConfig.ParseValue = fn
+		}
+	case "__parseLiteral":
+		var fn ParseLiteralFn
+		switch value := value.(type) {
+		case ParseLiteralFn:
+			fn, ok = value, true
+		case func(ast.Value) interface{}:
+			fn, ok = value, true
+		}
+		scalar, isScalar := ttype.(*Scalar)
+		if !isScalar {
+			return fmt.Errorf(`Cannot bind %v to %v, it is not a Scalar type.`, name, ttype)
+		}
+		if !ok {
+			return unexpected()
+		}
+		scalar.scalarConfig.ParseLiteral = fn
+	default:
+		return fmt.Errorf(`Unknown type resolver %v defined in resolvers for %v.`, name, ttype)
+	}
+	return nil
+}
+
+// bindResolverMethods binds the methods of the given Go value to the type.
+func bindResolverMethods(ttype Type, value interface{}) error {
+	v := reflect.ValueOf(value)
+	resolvers := map[string]interface{}{}
+
+	typeResolvers := map[string]string{
+		"ResolveType":  "__resolveType",
+		"IsTypeOf":     "__isTypeOf",
+		"Serialize":    "__serialize",
+		"ParseValue":   "__parseValue",
+		"ParseLiteral": "__parseLiteral",
+	}
+	for methodName, name := range typeResolvers {
+		if method := v.MethodByName(methodName); method.IsValid() {
+			if err := bindTypeResolver(ttype, name, method.Interface()); err != nil {
+				return err
+			}
+		}
+	}
+
+	if object, ok := ttype.(*Object); ok {
+		for fieldName := range object.Fields() {
+			methodName := upperFirst(fieldName)
+			if _, ok := typeResolvers[methodName]; ok {
+				continue
+			}
+			method := v.MethodByName(methodName)
+			if !method.IsValid() {
+				continue
+			}
+			fn, ok := method.Interface().(func(ResolveParams) (interface{}, error))
+			if !ok {
+				return fmt.Errorf(`Method %v of %T must be a FieldResolveFn to resolve %v.%v, got %v.`,
+					methodName, value, ttype, fieldName, method.Type())
+			}
+			resolvers[fieldName] = fn
+		}
+	}
+	return bindResolverMap(ttype, resolvers)
+}
+
+func upperFirst(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + s[size:]
+}
@@ -0,0 +1,237 @@
+package graphql_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+const resolversTestSDL = `
+	type Query {
+		user(id: ID!): User
+		greeting: String
+		pet: Pet
+	}
+
+	type Subscription {
+		counter: Int
+	}
+
+	type User {
+		id: ID!
+		name: String
+		born: Date
+	}
+
+	type Cat {
+		meows: Boolean
+	}
+
+	type Dog {
+		barks: Boolean
+	}
+
+	union Pet = Cat | Dog
+
+	scalar Date
+`
+
+type testQueryResolver struct {
+	greeting string
+}
+
+func (r *testQueryResolver) Greeting(p graphql.ResolveParams) (interface{}, error) {
+	return r.greeting, nil
+}
+
+func (r *testQueryResolver) Pet(p graphql.ResolveParams) (interface{}, error) {
+	return map[string]interface{}{"kind": "dog", "barks": true}, nil
+}
+
+func TestBindResolvers_BindsMapAndMethods(t *testing.T) {
+	schema := buildSchemaOrFail(t, resolversTestSDL)
+	err := graphql.BindResolvers(&schema, graphql.Resolvers{
+		"Query": &testQueryResolver{greeting: "hi"},
+		"User": map[string]interface{}{
+			"name": func(p graphql.ResolveParams) (interface{}, error) {
+				return strings.ToUpper(p.Source.(map[string]interface{})["name"].(string)), nil
+			},
+		},
+		"Pet": map[string]interface{}{
+			"__resolveType": func(p graphql.ResolveTypeParams) *graphql.Object {
+				if p.Value.(map[string]interface{})["kind"] == "dog" {
+					return p.Info.Schema.Type("Dog").(*graphql.Object)
+				}
+				return p.Info.Schema.Type("Cat").(*graphql.Object)
+			},
+		},
+		"Date": map[string]interface{}{
+			"__serialize": func(value interface{}) interface{} {
+				return "1970-01-01"
+			},
+		},
+	}, graphql.BindOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error binding resolvers: %v", err)
+	}
+
+	schema.QueryType().Fields()["user"].Resolve = func(p graphql.ResolveParams) (interface{}, error) {
+		return map[string]interface{}{"id": p.Args["id"], "name": "jane", "born": 0}, nil
+	}
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ greeting user(id: "1") { id name born } pet { ... on Dog { barks } } }`,
+	})
+	expected := &graphql.Result{
+		Data: map[string]interface{}{
+			"greeting": "hi",
+			"user": map[string]interface{}{
+				"id":   "1",
+				"name": "JANE",
+				"born": "1970-01-01",
+			},
+			"pet": map[string]interface{}{
+				"barks": true,
+			},
+		},
+	}
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
+
+func TestBindResolvers_BindsSubscribe(t *testing.T) {
+	schema := buildSchemaOrFail(t, resolversTestSDL)
+	err := graphql.BindResolvers(&schema, graphql.Resolvers{
+		"Subscription": map[string]interface{}{
+			"counter": &graphql.FieldResolvers{
+				Subscribe: func(p graphql.ResolveParams) (interface{}, error) {
+					c := make(chan interface{})
+					go func() {
+						defer close(c)
+						for i := 1; i <= 2; i++ {
+							c <- i
+						}
+					}()
+					return c, nil
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(int) * 10, nil
+				},
+			},
+		},
+	}, graphql.BindOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error binding resolvers: %v", err)
+	}
+
+	results := []interface{}{}
+	for result := range graphql.Subscribe(graphql.Params{
+		Context:       context.Background(),
+		Schema:        schema,
+		RequestString: `subscription { counter }`,
+	}) {
+		if len(result.Errors) > 0 {
+			t.Fatalf("unexpected errors: %v", result.Errors)
+		}
+		results = append(results, result.Data.(*graphql.OrderedMap).Map())
+	}
+	expected := []interface{}{
+		map[string]interface{}{"counter": 10},
+		map[string]interface{}{"counter": 20},
+	}
+	if !reflect.DeepEqual(expected, results) {
+		t.Fatalf("Unexpected results, Diff: %v", testutil.Diff(expected, results))
+	}
+}
+
+func TestBindResolvers_FailsLoudly(t *testing.T) {
+	resolve := func(p graphql.ResolveParams) (interface{}, error) { return nil, nil }
+	tests := []struct {
+		resolvers graphql.Resolvers
+		expected  string
+	}{
+		{
+			resolvers: graphql.Resolvers{"Nope": map[string]interface{}{}},
+			expected:  `Type "Nope" defined in resolvers, but not in schema.`,
+		},
+		{
+			resolvers: graphql.Resolvers{"Query": map[string]interface{}{"nope": resolve}},
+			expected:  `Query.nope defined in resolvers, but not in schema.`,
+		},
+		{
+			resolvers: graphql.Resolvers{"Query": map[string]interface{}{"greeting": "hello"}},
+			expected:  `Resolver for Query.greeting must be a FieldResolveFn or *FieldResolvers, got string.`,
+		},
+		{
+			resolvers: graphql.Resolvers{"Pet": map[string]interface{}{"cat": resolve}},
+			expected:  `Pet.cat defined in resolvers, but Pet is not an Object type.`,
+		},
+		{
+			resolvers: graphql.Resolvers{"User": map[string]interface{}{"__resolveType": resolve}},
+			expected:  `Cannot bind __resolveType to User, it is not an Interface or Union type.`,
+		},
+		{
+			resolvers: graphql.Resolvers{"String": map[string]interface{}{"__serialize": resolve}},
+			expected:  `Cannot bind resolvers to built-in scalar "String".`,
+		},
+	}
+	for _, test := range tests {
+		schema := buildSchemaOrFail(t, resolversTestSDL)
+		err := graphql.BindResolvers(&schema, test.resolvers, graphql.BindOptions{})
+		if err == nil || err.Error() != test.expected {
+			t.Fatalf("expected error %q, got %v", test.expected, err)
+		}
+	}
+}
+
+func TestBindResolvers_KeepsResolversOfFailedBindings(t *testing.T) {
+	schema := buildSchemaOrFail(t, resolversTestSDL)
+	resolveType := func(p graphql.ResolveTypeParams) *graphql.Object { return nil }
+	err := graphql.BindResolvers(&schema, graphql.Resolvers{
+		"Pet": map[string]interface{}{"__resolveType": resolveType},
+	}, graphql.BindOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error binding resolvers: %v", err)
+	}
+	err = graphql.BindResolvers(&schema, graphql.Resolvers{
+		"Pet": map[string]interface{}{"__resolveType": "nope"},
+	}, graphql.BindOptions{})
+	if expected := `Cannot bind __resolveType of Pet, unexpected function type string.`; err == nil || err.Error() != expected {
+		t.Fatalf("expected error %q, got %v", expected, err)
+	}
+	if schema.Type("Pet").(*graphql.Union).ResolveType == nil {
+		t.Fatalf("expected the resolver of Pet to be kept")
+	}
+}
+
+func TestBindResolvers_ReportsDefaultResolvedFields(t *testing.T) {
+	schema := buildSchemaOrFail(t, resolversTestSDL)
+	err := graphql.BindResolvers(&schema, graphql.Resolvers{
+		"Query": &testQueryResolver{},
+	}, graphql.BindOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error binding resolvers: %v", err)
+	}
+	expected := []string{
+		"Cat.meows",
+		"Dog.barks",
+		"Query.user",
+		"Subscription.counter",
+		"User.born",
+		"User.id",
+		"User.name",
+	}
+	if fields := graphql.DefaultResolvedFields(&schema); !reflect.DeepEqual(expected, fields) {
+		t.Fatalf("Unexpected fields, Diff: %v", testutil.Diff(expected, fields))
+	}
+
+	err = graphql.BindResolvers(&schema, graphql.Resolvers{}, graphql.BindOptions{RequireResolvers: true})
+	if err == nil || !strings.HasPrefix(err.Error(), "Resolvers missing for fields: Cat.meows, Dog.barks,") {
+		t.Fatalf("expected missing resolvers error, got %v", err)
+	}
+}
@@ -34,6 +34,7 @@ var SpecifiedRules = []ValidationRuleFn{
 	ProvidedNonNullArgumentsRule,
 	ScalarLeafsRule,
 	UniqueArgumentNamesRule,
+	UniqueDirectivesPerLocationRule,
 	UniqueFragmentNamesRule,
 	UniqueInputFieldNamesRule,
 	UniqueOperationNamesRule,
@@ -1429,6 +1430,53 @@ func UniqueArgumentNamesRule(context *ValidationContext) *ValidationRuleInstance
 	}
 }
 
+// UniqueDirectivesPerLocationRule Unique directive names per location
+//
+// A GraphQL document is only valid if all non-repeatable directives at
+// a given location are uniquely named.
+func UniqueDirectivesPerLocationRule(context *ValidationContext) *ValidationRuleInstance {
+	visitorOpts := &visitor.VisitorOptions{
+		Enter: func(p visitor.VisitFuncParams) (string, interface{}) {
+			var directives []*ast.Directive
+			switch node := p.Node.(type) {
+			case *ast.OperationDefinition:
+				directives = node.Directives
+			case *ast.FragmentDefinition:
+				directives = node.Directives
+			case *ast.Field:
+				directives = node.Directives
+			case *ast.FragmentSpread:
+				directives = node.Directives
+			case *ast.InlineFragment:
+				directives = node.Directives
+			}
+			knownDirectives := map[string]*ast.Directive{}
+			for _, directive := range directives {
+				if directive == nil || directive.Name == nil {
+					continue
+				}
+				directiveName := directive.Name.Value
+				if schemaDirective := context.Schema().Directive(directiveName); schemaDirective == nil || schemaDirective.IsRepeatable {
+					continue
+				}
+				if seenDirective, ok := knownDirectives[directiveName]; ok {
+					reportError(
+						context,
+						fmt.Sprintf(`The directive "%v" can only be used once at this location.`, directiveName),
+						[]ast.Node{seenDirective, directive},
+					)
+				} else {
+					knownDirectives[directiveName] = directive
+				}
+			}
+			return visitor.ActionNoChange, nil
+		},
+	}
+	return &ValidationRuleInstance{
+		VisitorOpts: visitorOpts,
+	}
+}
+
 // UniqueFragmentNamesRule Unique fragment names
 //
 // A GraphQL document is only valid if all defined fragments have unique names.
@@ -0,0 +1,157 @@
+package graphql
+
+import (
+	"fmt"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/kinds"
+	"github.com/graphql-go/graphql/language/visitor"
+)
+
+// IncrementalRules are the validation rules of the documents using
+// IncrementalDirectives, which are validated along with SpecifiedRules. The
+// locations of the directives, @defer being used on fragments only and @stream
+// on fields, are checked by KnownDirectivesRule.
+var IncrementalRules = []ValidationRuleFn{
+	DeferStreamDirectiveOnRootFieldRule,
+	DeferStreamDirectiveLabelRule,
+	StreamDirectiveOnListFieldRule,
+}
+
+func DeferOnRootTypeMessage(operation string, typeName string) string {
+	return fmt.Sprintf(`Defer directive cannot be used on root %v type "%v".`, operation, typeName)
+}
+
+func StreamOnRootTypeMessage(operation string, typeName string) string {
+	return fmt.Sprintf(`Stream directive cannot be used on root %v type "%v".`, operation, typeName)
+}
+
+func StaticDeferStreamLabelMessage(directiveName string) string {
+	return fmt.Sprintf(`Directive "%v"'s label argument must be a static string.`, directiveName)
+}
+
+func DuplicateDeferStreamLabelMessage(label string) string {
+	return fmt.Sprintf(`Defer/Stream directive label "%v" must be unique.`, label)
+}
+
+func StreamOnNonListFieldMessage(typeName string, fieldName string) string {
+	return fmt.Sprintf(`Stream directive cannot be used on non-list field "%v.%v".`, typeName, fieldName)
+}
+
+// DeferStreamDirectiveOnRootFieldRule Defer and stream directives on root fields
+//
+// A GraphQL document is only valid if @defer is not used on the root fields of
+// mutations and subscriptions, whose fields are executed serially or once per
+// event, nor @stream on the root fields of subscriptions.
+func DeferStreamDirectiveOnRootFieldRule(context *ValidationContext) *ValidationRuleInstance {
+	visitorOpts := &visitor.VisitorOptions{
+		KindFuncMap: map[string]visitor.NamedVisitFuncs{
+			kinds.Directive: {
+				Kind: func(p visitor.VisitFuncParams) (string, interface{}) {
+					node, ok := p.Node.(*ast.Directive)
+					if !ok || node == nil || node.Name == nil {
+						return visitor.ActionNoChange, nil
+					}
+					parentType, ok := context.ParentType().(*Object)
+					if !ok || parentType == nil {
+						return visitor.ActionNoChange, nil
+					}
+					schema := context.Schema()
+					isMutation := schema.MutationType() != nil && schema.MutationType() == parentType
+					isSubscription := schema.SubscriptionType() != nil && schema.SubscriptionType() == parentType
+					switch node.Name.Value {
+					case DeferDirective.Name:
+						if isMutation {
+							return reportError(context, DeferOnRootTypeMessage(ast.OperationTypeMutation, parentType.Name()), []ast.Node{node})
+						}
+						if isSubscription {
+							return reportError(context, DeferOnRootTypeMessage(ast.OperationTypeSubscription, parentType.Name()), []ast.Node{node})
+						}
+					case StreamDirective.Name:
+						if isSubscription {
+							return reportError(context, StreamOnRootTypeMessage(ast.OperationTypeSubscription, parentType.Name()), []ast.Node{node})
+						}
+					}
+					return visitor.ActionNoChange, nil
+				},
+			},
+		},
+	}
+	return &ValidationRuleInstance{
+		VisitorOpts: visitorOpts,
+	}
+}
+
+// DeferStreamDirectiveLabelRule Defer and stream directive labels
+//
+// A GraphQL document is only valid if the labels of its @defer and @stream
+// directives are static strings, unique in the document.
+func DeferStreamDirectiveLabelRule(context *ValidationContext) *ValidationRuleInstance {
+	knownLabels := map[string]bool{}
+	visitorOpts := &visitor.VisitorOptions{
+		KindFuncMap: map[string]visitor.NamedVisitFuncs{
+			kinds.Directive: {
+				Kind: func(p visitor.VisitFuncParams) (string, interface{}) {
+					node, ok := p.Node.(*ast.Directive)
+					if !ok || node == nil || node.Name == nil {
+						return visitor.ActionNoChange, nil
+					}
+					if node.Name.Value != DeferDirective.Name && node.Name.Value != StreamDirective.Name {
+						return visitor.ActionNoChange, nil
+					}
+					for _, argument := range node.Arguments {
+						if argument == nil || argument.Name == nil || argument.Name.Value != "label" {
+							continue
+						}
+						label, ok := argument.Value.(*ast.StringValue)
+						if !ok {
+							return reportError(context, StaticDeferStreamLabelMessage(node.Name.Value), []ast.Node{argument})
+						}
+						if knownLabels[label.Value] {
+							return reportError(context, DuplicateDeferStreamLabelMessage(label.Value), []ast.Node{argument})
+						}
+						knownLabels[label.Value] = true
+					}
+					return visitor.ActionNoChange, nil
+				},
+			},
+		},
+	}
+	return &ValidationRuleInstance{
+		VisitorOpts: visitorOpts,
+	}
+}
+
+// StreamDirectiveOnListFieldRule Stream directive on list fields
+//
+// A GraphQL document is only valid if @stream is used on list fields only.
+func StreamDirectiveOnListFieldRule(context *ValidationContext) *ValidationRuleInstance {
+	visitorOpts := &visitor.VisitorOptions{
+		KindFuncMap: map[string]visitor.NamedVisitFuncs{
+			kinds.Directive: {
+				Kind: func(p visitor.VisitFuncParams) (string, interface{}) {
+					node, ok := p.Node.(*ast.Directive)
+					if !ok || node == nil || node.Name == nil || node.Name.Value != StreamDirective.Name {
+						return visitor.ActionNoChange, nil
+					}
+					fieldDef := context.FieldDef()
+					parentType := context.ParentType()
+					if fieldDef == nil || parentType == nil {
+						return visitor.ActionNoChange, nil
+					}
+					fieldType := fieldDef.Type
+					if nonNull, ok := fieldType.(*NonNull); ok {
+						fieldType = nonNull.OfType
+					}
+					if _, ok := fieldType.(*List); !ok {
+						return reportError(context, StreamOnNonListFieldMessage(parentType.Name(), fieldDef.Name), []ast.Node{node})
+					}
+					return visitor.ActionNoChange, nil
+				},
+			},
+		},
+	}
+	return &ValidationRuleInstance{
+		VisitorOpts: visitorOpts,
+	}
+}
@@ -0,0 +1,132 @@
+package graphql_test
+
+import (
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+var deferStreamRootSchema = func() graphql.Schema {
+	rootFields := graphql.Fields{
+		"name": &graphql.Field{
+			Type: graphql.String,
+		},
+		"names": &graphql.Field{
+			Type: graphql.NewList(graphql.String),
+		},
+	}
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name:   "Query",
+			Fields: rootFields,
+		}),
+		Mutation: graphql.NewObject(graphql.ObjectConfig{
+			Name:   "Mutation",
+			Fields: rootFields,
+		}),
+		Subscription: graphql.NewObject(graphql.ObjectConfig{
+			Name:   "Subscription",
+			Fields: rootFields,
+		}),
+		Directives: append(append([]*graphql.Directive{}, graphql.SpecifiedDirectives...), graphql.IncrementalDirectives...),
+	})
+	if err != nil {
+		panic(err)
+	}
+	return schema
+}()
+
+func TestValidate_DeferStreamDirectiveOnRootField_DeferOnQueryRootIsValid(t *testing.T) {
+	testutil.ExpectPassesRuleWithSchema(t, &deferStreamRootSchema, graphql.DeferStreamDirectiveOnRootFieldRule, `
+      query {
+        ... @defer {
+          name
+        }
+        names @stream
+      }
+      mutation {
+        names @stream
+      }
+    `)
+}
+func TestValidate_DeferStreamDirectiveOnRootField_DeferOnMutationOrSubscriptionRootIsInvalid(t *testing.T) {
+	testutil.ExpectFailsRuleWithSchema(t, &deferStreamRootSchema, graphql.DeferStreamDirectiveOnRootFieldRule, `
+      mutation {
+        ...MutationFields @defer
+      }
+      subscription {
+        ... @defer {
+          name
+        }
+        names @stream
+      }
+      fragment MutationFields on Mutation {
+        name
+      }
+    `, []gqlerrors.FormattedError{
+		testutil.RuleError(`Defer directive cannot be used on root mutation type "Mutation".`, 3, 27),
+		testutil.RuleError(`Defer directive cannot be used on root subscription type "Subscription".`, 6, 13),
+		testutil.RuleError(`Stream directive cannot be used on root subscription type "Subscription".`, 9, 15),
+	})
+}
+func TestValidate_DeferStreamDirectiveLabel_UniqueStaticLabelsAreValid(t *testing.T) {
+	testutil.ExpectPassesRule(t, graphql.DeferStreamDirectiveLabelRule, `
+      {
+        human {
+          ... @defer(label: "a") {
+            name
+          }
+          relatives @stream(label: "b") {
+            name
+          }
+          ... @defer {
+            iq
+          }
+        }
+      }
+    `)
+}
+func TestValidate_DeferStreamDirectiveLabel_DuplicateOrDynamicLabelsAreInvalid(t *testing.T) {
+	testutil.ExpectFailsRule(t, graphql.DeferStreamDirectiveLabelRule, `
+      query ($label: String) {
+        human {
+          ... @defer(label: "a") {
+            name
+          }
+          relatives @stream(label: "a") {
+            name
+          }
+          ... @defer(label: $label) {
+            iq
+          }
+        }
+      }
+    `, []gqlerrors.FormattedError{
+		testutil.RuleError(`Defer/Stream directive label "a" must be unique.`, 7, 29),
+		testutil.RuleError(`Directive "defer"'s label argument must be a static string.`, 10, 22),
+	})
+}
+func TestValidate_StreamDirectiveOnListField_StreamOnListFieldsIsValid(t *testing.T) {
+	testutil.ExpectPassesRule(t, graphql.StreamDirectiveOnListFieldRule, `
+      {
+        human {
+          relatives @stream(initialCount: 1) {
+            name
+          }
+        }
+      }
+    `)
+}
+func TestValidate_StreamDirectiveOnListField_StreamOnNonListFieldsIsInvalid(t *testing.T) {
+	testutil.ExpectFailsRule(t, graphql.StreamDirectiveOnListFieldRule, `
+      {
+        human {
+          name @stream
+        }
+      }
+    `, []gqlerrors.FormattedError{
+		testutil.RuleError(`Stream directive cannot be used on non-list field "Human.name".`, 4, 16),
+	})
+}
@@ -0,0 +1,264 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/kinds"
+	"github.com/graphql-go/graphql/language/visitor"
+)
+
+// ComplexityParams Params for ComplexityFn()
+type ComplexityParams struct {
+	// Field is the definition of the field whose cost is computed.
+	Field *FieldDefinition
+
+	// ParentType is the type the field is selected on.
+	ParentType Composite
+
+	// Args is a map of the arguments of the field. Arguments given by
+	// variables are not known during validation, where they take their
+	// default value, so the cost of operations with variables is computed
+	// again with the values of the variables of the request before its
+	// execution.
+	Args map[string]interface{}
+
+	// ChildComplexity is the cost of the selection set of the field.
+	ChildComplexity int
+}
+
+// ComplexityFn computes the cost of a field.
+type ComplexityFn func(p ComplexityParams) int
+
+// DefaultComplexity is the ComplexityFn used by QueryComplexityRule when
+// given none: a field costs 1, plus the cost of its selection set multiplied
+// by its `first` or `limit` argument, if any. The cost saturates at the
+// largest int rather than overflowing.
+func DefaultComplexity(p ComplexityParams) int {
+	multiplier := 1
+	for _, name := range []string{"first", "limit"} {
+		if n, ok := p.Args[name].(int); ok && n > 0 {
+			multiplier = n
+			break
+		}
+	}
+	return saturatingAdd(1, saturatingMultiply(p.ChildComplexity, multiplier))
+}
+
+// maxInt is the largest int.
+const maxInt = int(^uint(0) >> 1)
+
+// saturatingAdd returns the sum of the given non-negative ints, or the
+// largest int if it overflows.
+func saturatingAdd(a, b int) int {
+	if a > maxInt-b {
+		return maxInt
+	}
+	return a + b
+}
+
+// saturatingMultiply returns the product of the given non-negative ints, or
+// the largest int if it overflows.
+func saturatingMultiply(a, b int) int {
+	if b > 0 && a > maxInt/b {
+		return maxInt
+	}
+	return a * b
+}
+
+func QueryComplexityMessage(opName string, cost int, maxCost int) string {
+	if opName != "" {
+		return fmt.Sprintf(`Operation "%v" has a cost of %v, which exceeds the maximum cost of %v.`, opName, cost, maxCost)
+	}
+	return fmt.Sprintf(`Operation has a cost of %v, which exceeds the maximum cost of %v.`, cost, maxCost)
+}
+
+type complexityKey struct{}
+
+// withComplexity returns a copy of the given context holding the cost of the
+// operation executed with it.
+func withComplexity(ctx context.Context, cost int) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, complexityKey{}, cost)
+}
+
+// ComplexityFromContext returns the cost of the executed operation, as
+// computed by QueryComplexityRule, e.g. for extensions to log it or to rate
+// limit clients.
+func ComplexityFromContext(ctx context.Context) (int, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	cost, ok := ctx.Value(complexityKey{}).(int)
+	return cost, ok
+}
+
+// complexityNode is a field, or the root of an operation or a fragment,
+// whose cost is computed once the whole document, with the fragments it
+// spreads, has been visited.
+type complexityNode struct {
+	field      *FieldDefinition
+	parentType Composite
+	arguments  []*ast.Argument
+	children   []*complexityNode
+	spreads    []string
+}
+
+// complexityCheck computes again the cost of an operation with the values of
+// its variables, which are unknown during validation.
+type complexityCheck struct {
+	operation *ast.OperationDefinition
+	maxCost   int
+	cost      func(variables map[string]interface{}) int
+}
+
+// QueryComplexityRule Query complexity
+//
+// A GraphQL operation is only valid if its cost, the sum of the costs of the
+// fields it selects, does not exceed maxCost. The cost of a field is computed
+// by its Complexity function when it has one, else by the given estimator,
+// which defaults to DefaultComplexity.
+//
+// The cost of each operation is available from the Complexity of the
+// ValidationResult and, for the executed operation, from
+// ComplexityFromContext in the context given to extensions and resolvers.
+func QueryComplexityRule(maxCost int, estimator ComplexityFn) ValidationRuleFn {
+	if estimator == nil {
+		estimator = DefaultComplexity
+	}
+	return func(context *ValidationContext) *ValidationRuleInstance {
+		var stack []*complexityNode
+		operations := map[*ast.OperationDefinition]*complexityNode{}
+		operationDefs := []*ast.OperationDefinition{}
+		fragments := map[string]*complexityNode{}
+
+		// evaluate returns the cost of the given node, the cost of each
+		// fragment being computed once per evaluation of an operation.
+		var evaluate func(node *complexityNode, variables map[string]interface{}, costs map[string]int) int
+		evaluate = func(node *complexityNode, variables map[string]interface{}, costs map[string]int) int {
+			childComplexity := 0
+			for _, child := range node.children {
+				childComplexity = saturatingAdd(childComplexity, evaluate(child, variables, costs))
+			}
+			for _, name := range node.spreads {
+				cost, ok := costs[name]
+				if !ok {
+					fragment, ok := fragments[name]
+					if !ok {
+						continue
+					}
+					// cycles are reported by NoFragmentCyclesRule, and
+					// cost nothing meanwhile
+					costs[name] = 0
+					cost = evaluate(fragment, variables, costs)
+					costs[name] = cost
+				}
+				childComplexity = saturatingAdd(childComplexity, cost)
+			}
+			if node.field == nil {
+				return childComplexity
+			}
+			params := ComplexityParams{
+				Field:           node.field,
+				ParentType:      node.parentType,
+				Args:            getArgumentValues(node.field.Args, node.arguments, variables),
+				ChildComplexity: childComplexity,
+			}
+			if node.field.Complexity != nil {
+				return node.field.Complexity(params)
+			}
+			return estimator(params)
+		}
+
+		visitorOpts := &visitor.VisitorOptions{
+			KindFuncMap: map[string]visitor.NamedVisitFuncs{
+				kinds.OperationDefinition: {
+					Kind: func(p visitor.VisitFuncParams) (string, interface{}) {
+						if node, ok := p.Node.(*ast.OperationDefinition); ok && node != nil {
+							root := &complexityNode{}
+							operations[node] = root
+							operationDefs = append(operationDefs, node)
+							stack = []*complexityNode{root}
+						}
+						return visitor.ActionNoChange, nil
+					},
+				},
+				kinds.FragmentDefinition: {
+					Kind: func(p visitor.VisitFuncParams) (string, interface{}) {
+						if node, ok := p.Node.(*ast.FragmentDefinition); ok && node != nil && node.Name != nil {
+							root := &complexityNode{}
+							fragments[node.Name.Value] = root
+							stack = []*complexityNode{root}
+						}
+						return visitor.ActionNoChange, nil
+					},
+				},
+				kinds.Field: {
+					Enter: func(p visitor.VisitFuncParams) (string, interface{}) {
+						if node, ok := p.Node.(*ast.Field); ok && node != nil && len(stack) > 0 {
+							child := &complexityNode{
+								field:      context.FieldDef(),
+								parentType: context.ParentType(),
+							}
+							if child.field != nil {
+								child.arguments = node.Arguments
+							}
+							parent := stack[len(stack)-1]
+							parent.children = append(parent.children, child)
+							stack = append(stack, child)
+						}
+						return visitor.ActionNoChange, nil
+					},
+					Leave: func(p visitor.VisitFuncParams) (string, interface{}) {
+						if len(stack) > 1 {
+							stack = stack[:len(stack)-1]
+						}
+						return visitor.ActionNoChange, nil
+					},
+				},
+				kinds.FragmentSpread: {
+					Kind: func(p visitor.VisitFuncParams) (string, interface{}) {
+						if node, ok := p.Node.(*ast.FragmentSpread); ok && node != nil && node.Name != nil && len(stack) > 0 {
+							parent := stack[len(stack)-1]
+							parent.spreads = append(parent.spreads, node.Name.Value)
+						}
+						return visitor.ActionNoChange, nil
+					},
+				},
+				kinds.Document: {
+					Leave: func(p visitor.VisitFuncParams) (string, interface{}) {
+						for _, operation := range operationDefs {
+							opName := ""
+							if operation.Name != nil {
+								opName = operation.Name.Value
+							}
+							root := operations[operation]
+							cost := evaluate(root, nil, map[string]int{})
+							context.setComplexity(opName, cost, &complexityCheck{
+								operation: operation,
+								maxCost:   maxCost,
+								cost: func(variables map[string]interface{}) int {
+									return evaluate(root, variables, map[string]int{})
+								},
+							})
+							if cost > maxCost {
+								reportError(
+									context,
+									QueryComplexityMessage(opName, cost, maxCost),
+									[]ast.Node{operation},
+								)
+							}
+						}
+						return visitor.ActionNoChange, nil
+					},
+				},
+			},
+		}
+		return &ValidationRuleInstance{
+			VisitorOpts: visitorOpts,
+		}
+	}
+}
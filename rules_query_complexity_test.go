@@ -0,0 +1,238 @@
+package graphql_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+func complexityTestSchema(t *testing.T) *graphql.Schema {
+	var userType *graphql.Object
+	userType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "User",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"name": &graphql.Field{Type: graphql.String},
+				"friends": &graphql.Field{
+					Type: graphql.NewList(userType),
+					Args: graphql.FieldConfigArgument{
+						"first": &graphql.ArgumentConfig{Type: graphql.Int},
+					},
+				},
+				"posts": &graphql.Field{
+					Type: graphql.NewList(graphql.String),
+					Args: graphql.FieldConfigArgument{
+						"limit": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 10},
+					},
+				},
+				"search": &graphql.Field{
+					Type: graphql.NewList(userType),
+					Args: graphql.FieldConfigArgument{
+						"count": &graphql.ArgumentConfig{Type: graphql.Int},
+					},
+					Complexity: func(p graphql.ComplexityParams) int {
+						count, _ := p.Args["count"].(int)
+						return 10 + count*p.ChildComplexity
+					},
+				},
+			}
+		}),
+	})
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"me": &graphql.Field{
+					Type: userType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return map[string]interface{}{"name": "me"}, nil
+					},
+				},
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Error in schema %v", err.Error())
+	}
+	return &schema
+}
+
+func TestValidate_QueryComplexity_OperationsWithinTheMaximumCostAreValid(t *testing.T) {
+	testutil.ExpectPassesRuleWithSchema(t, complexityTestSchema(t), graphql.QueryComplexityRule(4, nil), `
+      {
+        me {
+          name
+          friends {
+            name
+          }
+        }
+      }
+    `)
+}
+func TestValidate_QueryComplexity_OperationsExceedingTheMaximumCostAreInvalid(t *testing.T) {
+	testutil.ExpectFailsRuleWithSchema(t, complexityTestSchema(t), graphql.QueryComplexityRule(3, nil), `
+      query Me {
+        me {
+          name
+          friends {
+            name
+          }
+          posts
+        }
+      }
+    `, []gqlerrors.FormattedError{
+		testutil.RuleError(`Operation "Me" has a cost of 5, which exceeds the maximum cost of 3.`, 2, 7),
+	})
+}
+func TestValidate_QueryComplexity_MultipliesTheCostOfListsByTheirFirstOrLimitArgument(t *testing.T) {
+	testutil.ExpectFailsRuleWithSchema(t, complexityTestSchema(t), graphql.QueryComplexityRule(30, nil), `
+      {
+        me {
+          friends(first: 5) {
+            friends(first: 5) {
+              name
+            }
+            posts(limit: 2)
+          }
+        }
+      }
+    `, []gqlerrors.FormattedError{
+		testutil.RuleError(`Operation has a cost of 37, which exceeds the maximum cost of 30.`, 2, 7),
+	})
+}
+func TestValidate_QueryComplexity_CountsTheFieldsOfFragments(t *testing.T) {
+	testutil.ExpectFailsRuleWithSchema(t, complexityTestSchema(t), graphql.QueryComplexityRule(10, nil), `
+      {
+        me {
+          friends(first: 3) {
+            ...UserFields
+            ... on User {
+              name
+            }
+          }
+        }
+      }
+      fragment UserFields on User {
+        name
+        friends(first: 2) {
+          name
+        }
+      }
+    `, []gqlerrors.FormattedError{
+		testutil.RuleError(`Operation has a cost of 17, which exceeds the maximum cost of 10.`, 2, 7),
+	})
+}
+func TestValidate_QueryComplexity_UsesTheComplexityFunctionOfFields(t *testing.T) {
+	testutil.ExpectFailsRuleWithSchema(t, complexityTestSchema(t), graphql.QueryComplexityRule(15, nil), `
+      {
+        me {
+          search(count: 5) {
+            name
+          }
+        }
+      }
+    `, []gqlerrors.FormattedError{
+		testutil.RuleError(`Operation has a cost of 16, which exceeds the maximum cost of 15.`, 2, 7),
+	})
+}
+func TestValidate_QueryComplexity_UsesTheGivenEstimator(t *testing.T) {
+	estimator := func(p graphql.ComplexityParams) int {
+		return 2 + p.ChildComplexity
+	}
+	testutil.ExpectFailsRuleWithSchema(t, complexityTestSchema(t), graphql.QueryComplexityRule(5, estimator), `
+      {
+        me {
+          name
+          friends {
+            name
+          }
+        }
+      }
+    `, []gqlerrors.FormattedError{
+		testutil.RuleError(`Operation has a cost of 8, which exceeds the maximum cost of 5.`, 2, 7),
+	})
+}
+func TestValidate_QueryComplexity_ReportsTheCostOfEachOperation(t *testing.T) {
+	schema := complexityTestSchema(t)
+	AST, err := parser.Parse(parser.ParseParams{Source: `
+      query A { me { name } }
+      query B { me { ...UserFields } }
+      fragment UserFields on User { name posts }
+    `})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := graphql.ValidateDocument(schema, AST, []graphql.ValidationRuleFn{graphql.QueryComplexityRule(100, nil)})
+	if !result.IsValid {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	if result.Complexity["A"] != 2 || result.Complexity["B"] != 3 {
+		t.Fatalf("Unexpected complexity: %v", result.Complexity)
+	}
+}
+func TestValidate_QueryComplexity_ExposesTheCostOfTheExecutedOperation(t *testing.T) {
+	schema := complexityTestSchema(t)
+	ext := newtestExt("complexityExt")
+	var cost int
+	var ok bool
+	ext.executionDidStartFn = func(ctx context.Context) (context.Context, graphql.ExecutionFinishFunc) {
+		cost, ok = graphql.ComplexityFromContext(ctx)
+		return ctx, func(*graphql.Result) {}
+	}
+	schema.AddExtensions(ext)
+	rules := append([]graphql.ValidationRuleFn{graphql.QueryComplexityRule(100, nil)}, graphql.SpecifiedRules...)
+	result := graphql.Do(graphql.Params{
+		Schema:          *schema,
+		RequestString:   `{ me { name friends { name } } }`,
+		ValidationRules: rules,
+	})
+	if len(result.Errors) > 0 {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	if !ok || cost != 4 {
+		t.Fatalf("Expected a cost of 4 in the context, got %v, %v", cost, ok)
+	}
+}
+func TestValidate_QueryComplexity_ComputesTheCostOfFragmentsOnce(t *testing.T) {
+	maxInt := int(^uint(0) >> 1)
+	doc := "{ me { ...F0 } }\n"
+	for i := 0; i < 70; i++ {
+		doc += fmt.Sprintf("fragment F%v on User { a: friends { ...F%v } b: friends { ...F%v } }\n", i, i+1, i+1)
+	}
+	doc += "fragment F70 on User { name }\n"
+	testutil.ExpectFailsRuleWithSchema(t, complexityTestSchema(t), graphql.QueryComplexityRule(1000, nil), doc, []gqlerrors.FormattedError{
+		testutil.RuleError(fmt.Sprintf(`Operation has a cost of %v, which exceeds the maximum cost of 1000.`, maxInt), 1, 1),
+	})
+}
+func TestValidate_QueryComplexity_SaturatesTheDefaultComplexity(t *testing.T) {
+	maxInt := int(^uint(0) >> 1)
+	cost := graphql.DefaultComplexity(graphql.ComplexityParams{
+		Args:            map[string]interface{}{"first": maxInt},
+		ChildComplexity: 2,
+	})
+	if cost != maxInt {
+		t.Fatalf("Expected the cost to saturate at %v, got %v", maxInt, cost)
+	}
+}
+func TestValidate_QueryComplexity_ComputesTheCostWithTheVariablesOfTheRequest(t *testing.T) {
+	rules := append([]graphql.ValidationRuleFn{graphql.QueryComplexityRule(50, nil)}, graphql.SpecifiedRules...)
+	result := graphql.Do(graphql.Params{
+		Schema:          *complexityTestSchema(t),
+		RequestString:   `query Friends($first: Int) { me { friends(first: $first) { name } } }`,
+		VariableValues:  map[string]interface{}{"first": 100},
+		ValidationRules: rules,
+	})
+	expected := &graphql.Result{
+		Errors: []gqlerrors.FormattedError{
+			testutil.RuleError(`Operation "Friends" has a cost of 102, which exceeds the maximum cost of 50.`, 1, 1),
+		},
+	}
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
@@ -0,0 +1,319 @@
+package graphql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/kinds"
+	"github.com/graphql-go/graphql/language/visitor"
+)
+
+func MaxDepthMessage(fieldName string, maxDepth int) string {
+	return fmt.Sprintf(`Field "%v" exceeds the maximum depth of %v.`, fieldName, maxDepth)
+}
+
+func MaxAliasesMessage(alias string, maxAliases int) string {
+	return fmt.Sprintf(`Alias "%v" exceeds the maximum of %v aliases.`, alias, maxAliases)
+}
+
+func MaxRootFieldsMessage(fieldName string, maxRootFields int) string {
+	return fmt.Sprintf(`Field "%v" exceeds the maximum of %v root fields.`, fieldName, maxRootFields)
+}
+
+func MaxFieldsMessage(fieldName string, maxFields int) string {
+	return fmt.Sprintf(`Field "%v" exceeds the maximum of %v fields.`, fieldName, maxFields)
+}
+
+// fieldCounts are the numbers of fields a selection set selects once its
+// fragments are expanded, of those that are aliased and of those it selects
+// directly rather than through the selection sets of its fields, and the
+// depth of the deepest of them, the fields selected directly being at depth
+// 1. Counts saturate at the largest int.
+type fieldCounts struct {
+	fields       int
+	aliases      int
+	directFields int
+	depth        int
+}
+
+func (c *fieldCounts) add(counts fieldCounts) {
+	c.fields = saturatingAdd(c.fields, counts.fields)
+	c.aliases = saturatingAdd(c.aliases, counts.aliases)
+	c.directFields = saturatingAdd(c.directFields, counts.directFields)
+	if counts.depth > c.depth {
+		c.depth = counts.depth
+	}
+}
+
+// fieldLimit is a limit on the fields of an operation, checked by walkFields.
+type fieldLimit struct {
+	// field is called with each field walked and its depth, the fields of the
+	// operation's selection set being at depth 1. The walk stops when it
+	// returns false.
+	field func(field *ast.Field, depth int) bool
+
+	// fragment is called with the counts of each fragment spread at the given
+	// depth, and returns true when its fields are within the limit, which
+	// accounts for them without their being walked.
+	fragment func(counts fieldCounts, depth int) bool
+}
+
+// fieldWalker walks the fields of the operations of a document, computing
+// the counts of each fragment once, so that fragments spread many times are
+// not expanded again each time.
+type fieldWalker struct {
+	context *ValidationContext
+	counts  map[string]*fieldCounts
+}
+
+// fragmentCounts returns the counts of the fragment with the given name.
+func (w *fieldWalker) fragmentCounts(name string) fieldCounts {
+	if counts, ok := w.counts[name]; ok {
+		return *counts
+	}
+	// cycles are reported by NoFragmentCyclesRule, and count nothing
+	// meanwhile
+	counts := &fieldCounts{}
+	w.counts[name] = counts
+	if fragment := w.context.Fragment(name); fragment != nil {
+		*counts = w.selectionSetCounts(fragment.SelectionSet)
+	}
+	return *counts
+}
+
+// selectionSetCounts returns the counts of the given selection set.
+// Introspection fields, and the fields they select, are exempt from the
+// limits and are not counted.
+func (w *fieldWalker) selectionSetCounts(selectionSet *ast.SelectionSet) fieldCounts {
+	counts := fieldCounts{}
+	if selectionSet == nil {
+		return counts
+	}
+	for _, selection := range selectionSet.Selections {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			if selection.Name == nil || strings.HasPrefix(selection.Name.Value, "__") {
+				continue
+			}
+			field := fieldCounts{fields: 1, directFields: 1}
+			if selection.Alias != nil {
+				field.aliases = 1
+			}
+			children := w.selectionSetCounts(selection.SelectionSet)
+			field.fields = saturatingAdd(field.fields, children.fields)
+			field.aliases = saturatingAdd(field.aliases, children.aliases)
+			field.depth = children.depth + 1
+			counts.add(field)
+		case *ast.InlineFragment:
+			counts.add(w.selectionSetCounts(selection.SelectionSet))
+		case *ast.FragmentSpread:
+			if selection.Name != nil {
+				counts.add(w.fragmentCounts(selection.Name.Value))
+			}
+		}
+	}
+	return counts
+}
+
+// walkFields calls the field function of the limit with the fields selected
+// by the given selection set, depth first, following fragment spreads as if
+// their fields were selected in place, unless the limit accounts for them as
+// a whole. A spread of a fragment already being walked is skipped, as the
+// cycle is reported by NoFragmentCyclesRule. Introspection fields, and the
+// fields they select, are exempt from the limits and are not walked.
+func (w *fieldWalker) walkFields(selectionSet *ast.SelectionSet, depth int, spreadPath map[string]bool, limit fieldLimit) bool {
+	if selectionSet == nil {
+		return true
+	}
+	for _, selection := range selectionSet.Selections {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			if selection.Name == nil || strings.HasPrefix(selection.Name.Value, "__") {
+				continue
+			}
+			if !limit.field(selection, depth) {
+				return false
+			}
+			if !w.walkFields(selection.SelectionSet, depth+1, spreadPath, limit) {
+				return false
+			}
+		case *ast.InlineFragment:
+			if !w.walkFields(selection.SelectionSet, depth, spreadPath, limit) {
+				return false
+			}
+		case *ast.FragmentSpread:
+			if selection.Name == nil || spreadPath[selection.Name.Value] {
+				continue
+			}
+			fragment := w.context.Fragment(selection.Name.Value)
+			if fragment == nil || limit.fragment(w.fragmentCounts(selection.Name.Value), depth) {
+				continue
+			}
+			spreadPath[selection.Name.Value] = true
+			ok := w.walkFields(fragment.SelectionSet, depth, spreadPath, limit)
+			delete(spreadPath, selection.Name.Value)
+			if !ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// operationLimitRule returns a rule walking the fields of each operation of
+// the document with the fieldLimit returned by newLimit for the operation.
+func operationLimitRule(newLimit func(context *ValidationContext) fieldLimit) ValidationRuleFn {
+	return func(context *ValidationContext) *ValidationRuleInstance {
+		walker := &fieldWalker{
+			context: context,
+			counts:  map[string]*fieldCounts{},
+		}
+		visitorOpts := &visitor.VisitorOptions{
+			KindFuncMap: map[string]visitor.NamedVisitFuncs{
+				kinds.OperationDefinition: {
+					Kind: func(p visitor.VisitFuncParams) (string, interface{}) {
+						if node, ok := p.Node.(*ast.OperationDefinition); ok && node != nil {
+							walker.walkFields(node.SelectionSet, 1, map[string]bool{}, newLimit(context))
+						}
+						return visitor.ActionSkip, nil
+					},
+				},
+			},
+		}
+		return &ValidationRuleInstance{
+			VisitorOpts: visitorOpts,
+		}
+	}
+}
+
+// countLimit returns a fieldLimit allowing at most max of the fields that
+// countField counts, countFragment counting those of fragments, and calling
+// report with the first field beyond the limit.
+func countLimit(max int, countField func(field *ast.Field, depth int) bool, countFragment func(counts fieldCounts, depth int) int, report func(field *ast.Field)) fieldLimit {
+	count := 0
+	return fieldLimit{
+		field: func(field *ast.Field, depth int) bool {
+			if !countField(field, depth) {
+				return true
+			}
+			if count++; count > max {
+				report(field)
+				return false
+			}
+			return true
+		},
+		fragment: func(counts fieldCounts, depth int) bool {
+			n := countFragment(counts, depth)
+			if n > max-count {
+				return false
+			}
+			count += n
+			return true
+		},
+	}
+}
+
+// MaxDepthRule Max depth
+//
+// A GraphQL operation is only valid if its fields, including the fields of
+// the fragments it spreads, are nested at most maxDepth levels deep.
+// Introspection fields are exempt.
+func MaxDepthRule(maxDepth int) ValidationRuleFn {
+	return operationLimitRule(func(context *ValidationContext) fieldLimit {
+		return fieldLimit{
+			field: func(field *ast.Field, depth int) bool {
+				if depth > maxDepth {
+					reportError(
+						context,
+						MaxDepthMessage(field.Name.Value, maxDepth),
+						[]ast.Node{field},
+					)
+					return false
+				}
+				return true
+			},
+			fragment: func(counts fieldCounts, depth int) bool {
+				return depth-1+counts.depth <= maxDepth
+			},
+		}
+	})
+}
+
+// MaxAliasesRule Max aliases
+//
+// A GraphQL operation is only valid if it selects at most maxAliases aliased
+// fields, counting the fields of a fragment each time it is spread.
+// Introspection fields are exempt.
+func MaxAliasesRule(maxAliases int) ValidationRuleFn {
+	return operationLimitRule(func(context *ValidationContext) fieldLimit {
+		return countLimit(maxAliases,
+			func(field *ast.Field, depth int) bool {
+				return field.Alias != nil
+			},
+			func(counts fieldCounts, depth int) int {
+				return counts.aliases
+			},
+			func(field *ast.Field) {
+				reportError(
+					context,
+					MaxAliasesMessage(field.Alias.Value, maxAliases),
+					[]ast.Node{field},
+				)
+			},
+		)
+	})
+}
+
+// MaxRootFieldsRule Max root fields
+//
+// A GraphQL operation is only valid if it selects at most maxRootFields fields
+// of the root type, including the fields of the fragments it spreads.
+// Introspection fields are exempt.
+func MaxRootFieldsRule(maxRootFields int) ValidationRuleFn {
+	return operationLimitRule(func(context *ValidationContext) fieldLimit {
+		return countLimit(maxRootFields,
+			func(field *ast.Field, depth int) bool {
+				return depth == 1
+			},
+			func(counts fieldCounts, depth int) int {
+				if depth > 1 {
+					return 0
+				}
+				return counts.directFields
+			},
+			func(field *ast.Field) {
+				reportError(
+					context,
+					MaxRootFieldsMessage(field.Name.Value, maxRootFields),
+					[]ast.Node{field},
+				)
+			},
+		)
+	})
+}
+
+// MaxFieldsRule Max fields
+//
+// A GraphQL operation is only valid if it selects at most maxFields fields once
+// its fragments are expanded, counting the fields of a fragment each time it
+// is spread. Introspection fields are exempt.
+func MaxFieldsRule(maxFields int) ValidationRuleFn {
+	return operationLimitRule(func(context *ValidationContext) fieldLimit {
+		return countLimit(maxFields,
+			func(field *ast.Field, depth int) bool {
+				return true
+			},
+			func(counts fieldCounts, depth int) int {
+				return counts.fields
+			},
+			func(field *ast.Field) {
+				reportError(
+					context,
+					MaxFieldsMessage(field.Name.Value, maxFields),
+					[]ast.Node{field},
+				)
+			},
+		)
+	})
+}
@@ -0,0 +1,186 @@
+package graphql_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+func TestValidate_MaxDepth_OperationsWithinTheMaximumDepthAreValid(t *testing.T) {
+	testutil.ExpectPassesRule(t, graphql.MaxDepthRule(3), `
+      {
+        human {
+          relatives {
+            name
+          }
+        }
+      }
+    `)
+}
+func TestValidate_MaxDepth_FieldsBeyondTheMaximumDepthAreInvalid(t *testing.T) {
+	testutil.ExpectFailsRule(t, graphql.MaxDepthRule(3), `
+      {
+        human {
+          relatives {
+            relatives {
+              name
+            }
+          }
+        }
+      }
+    `, []gqlerrors.FormattedError{
+		testutil.RuleError(`Field "name" exceeds the maximum depth of 3.`, 6, 15),
+	})
+}
+func TestValidate_MaxDepth_FollowsFragmentSpreads(t *testing.T) {
+	testutil.ExpectFailsRule(t, graphql.MaxDepthRule(2), `
+      {
+        human {
+          ...HumanFields
+        }
+      }
+      fragment HumanFields on Human {
+        ... on Human {
+          relatives {
+            name
+          }
+        }
+      }
+    `, []gqlerrors.FormattedError{
+		testutil.RuleError(`Field "name" exceeds the maximum depth of 2.`, 10, 13),
+	})
+}
+func TestValidate_MaxDepth_SkipsFragmentCycles(t *testing.T) {
+	testutil.ExpectPassesRule(t, graphql.MaxDepthRule(2), `
+      {
+        human {
+          ...HumanFields
+        }
+      }
+      fragment HumanFields on Human {
+        name
+        ...HumanFields
+      }
+    `)
+}
+func TestValidate_MaxDepth_ExemptsIntrospectionFields(t *testing.T) {
+	testutil.ExpectPassesRule(t, graphql.MaxDepthRule(1), `
+      {
+        __schema {
+          types {
+            fields {
+              type {
+                name
+              }
+            }
+          }
+        }
+        dog {
+          __typename
+        }
+      }
+    `)
+}
+func TestValidate_MaxAliases_FieldsBeyondTheMaximumNumberOfAliasesAreInvalid(t *testing.T) {
+	testutil.ExpectFailsRule(t, graphql.MaxAliasesRule(2), `
+      {
+        a: dog {
+          name
+        }
+        ...DogFields
+      }
+      fragment DogFields on QueryRoot {
+        b: dog {
+          c: name
+        }
+      }
+    `, []gqlerrors.FormattedError{
+		testutil.RuleError(`Alias "c" exceeds the maximum of 2 aliases.`, 10, 11),
+	})
+}
+func TestValidate_MaxAliases_OperationsWithinTheMaximumNumberOfAliasesAreValid(t *testing.T) {
+	testutil.ExpectPassesRule(t, graphql.MaxAliasesRule(2), `
+      {
+        a: dog {
+          b: name
+          __typename
+        }
+      }
+    `)
+}
+func TestValidate_MaxRootFields_FieldsBeyondTheMaximumNumberOfRootFieldsAreInvalid(t *testing.T) {
+	testutil.ExpectFailsRule(t, graphql.MaxRootFieldsRule(2), `
+      {
+        __typename
+        dog {
+          name
+          barks
+        }
+        ... on QueryRoot {
+          human {
+            name
+          }
+        }
+        cat: dog {
+          name
+        }
+      }
+    `, []gqlerrors.FormattedError{
+		testutil.RuleError(`Field "dog" exceeds the maximum of 2 root fields.`, 13, 9),
+	})
+}
+func TestValidate_MaxFields_CountsTheFieldsOfEachSpreadFragment(t *testing.T) {
+	testutil.ExpectFailsRule(t, graphql.MaxFieldsRule(5), `
+      {
+        dog {
+          ...DogFields
+        }
+        other: dog {
+          ...DogFields
+        }
+      }
+      fragment DogFields on Dog {
+        name
+        barks
+      }
+    `, []gqlerrors.FormattedError{
+		testutil.RuleError(`Field "barks" exceeds the maximum of 5 fields.`, 12, 9),
+	})
+}
+func TestValidate_MaxFields_OperationsWithinTheMaximumNumberOfFieldsAreValid(t *testing.T) {
+	testutil.ExpectPassesRule(t, graphql.MaxFieldsRule(3), `
+      query A {
+        dog {
+          name
+          barks
+        }
+      }
+      query B {
+        human {
+          name
+        }
+        __schema {
+          queryType {
+            name
+          }
+        }
+      }
+    `)
+}
+func TestValidate_MaxFields_CountsTheFieldsOfFragmentsSpreadManyTimesOnce(t *testing.T) {
+	doc := "{ human { ...F0 } }\n"
+	for i := 0; i < 70; i++ {
+		doc += fmt.Sprintf("fragment F%v on Human { a: relatives { ...F%v } b: relatives { ...F%v } }\n", i, i+1, i+1)
+	}
+	doc += "fragment F70 on Human { name }\n"
+	testutil.ExpectPassesRule(t, graphql.MaxDepthRule(100), doc)
+	testutil.ExpectFailsRule(t, graphql.MaxFieldsRule(100), doc, []gqlerrors.FormattedError{
+		testutil.RuleError(`Field "relatives" exceeds the maximum of 100 fields.`, 71, 49),
+	})
+	testutil.ExpectFailsRule(t, graphql.MaxAliasesRule(100), doc, []gqlerrors.FormattedError{
+		testutil.RuleError(`Alias "a" exceeds the maximum of 100 aliases.`, 71, 25),
+	})
+}
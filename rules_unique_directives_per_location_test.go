@@ -0,0 +1,86 @@
+package graphql_test
+
+import (
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+func TestValidate_UniqueDirectivesPerLocation_NoDirectives(t *testing.T) {
+	testutil.ExpectPassesRule(t, graphql.UniqueDirectivesPerLocationRule, `
+      fragment Test on Type {
+        field
+      }
+    `)
+}
+func TestValidate_UniqueDirectivesPerLocation_UniqueDirectivesInDifferentLocations(t *testing.T) {
+	testutil.ExpectPassesRule(t, graphql.UniqueDirectivesPerLocationRule, `
+      fragment Test on Type @onFragmentDefinition {
+        field @onField
+      }
+    `)
+}
+func TestValidate_UniqueDirectivesPerLocation_UniqueDirectivesInSameLocations(t *testing.T) {
+	testutil.ExpectPassesRule(t, graphql.UniqueDirectivesPerLocationRule, `
+      {
+        field @include(if: true) @skip(if: false)
+      }
+    `)
+}
+func TestValidate_UniqueDirectivesPerLocation_SameDirectivesInDifferentLocations(t *testing.T) {
+	testutil.ExpectPassesRule(t, graphql.UniqueDirectivesPerLocationRule, `
+      {
+        field @include(if: true) {
+          subfield @include(if: true)
+        }
+      }
+    `)
+}
+func TestValidate_UniqueDirectivesPerLocation_RepeatableDirectivesInSameLocation(t *testing.T) {
+	testutil.ExpectPassesRule(t, graphql.UniqueDirectivesPerLocationRule, `
+      query @repeatable @repeatable {
+        field @repeatable @repeatable
+        ... on Type @repeatable @repeatable {
+          field
+        }
+      }
+    `)
+}
+func TestValidate_UniqueDirectivesPerLocation_UnknownDirectivesInSameLocation(t *testing.T) {
+	testutil.ExpectPassesRule(t, graphql.UniqueDirectivesPerLocationRule, `
+      {
+        field @unknown @unknown
+      }
+    `)
+}
+func TestValidate_UniqueDirectivesPerLocation_DuplicateDirectivesInOneLocation(t *testing.T) {
+	testutil.ExpectFailsRule(t, graphql.UniqueDirectivesPerLocationRule, `
+      {
+        field @include(if: true) @include(if: false)
+      }
+    `, []gqlerrors.FormattedError{
+		testutil.RuleError(`The directive "include" can only be used once at this location.`, 3, 15, 3, 34),
+	})
+}
+func TestValidate_UniqueDirectivesPerLocation_ManyDuplicateDirectivesInOneLocation(t *testing.T) {
+	testutil.ExpectFailsRule(t, graphql.UniqueDirectivesPerLocationRule, `
+      {
+        field @skip(if: true) @skip(if: true) @skip(if: true)
+      }
+    `, []gqlerrors.FormattedError{
+		testutil.RuleError(`The directive "skip" can only be used once at this location.`, 3, 15, 3, 31),
+		testutil.RuleError(`The directive "skip" can only be used once at this location.`, 3, 15, 3, 47),
+	})
+}
+func TestValidate_UniqueDirectivesPerLocation_DifferentDuplicateDirectivesInOneLocation(t *testing.T) {
+	testutil.ExpectFailsRule(t, graphql.UniqueDirectivesPerLocationRule, `
+      fragment Test on Type @onFragmentDefinition @onFragmentDefinition {
+        ...Frag @onFragmentSpread @onFragmentSpread
+      }
+    `, []gqlerrors.FormattedError{
+		testutil.RuleError(`The directive "onFragmentDefinition" can only be used once at this location.`, 2, 29, 2, 51),
+		testutil.RuleError(`The directive "onFragmentSpread" can only be used once at this location.`, 3, 17, 3, 35),
+	})
+}
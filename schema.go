@@ -1,5 +1,11 @@
 package graphql
 
+import (
+	"fmt"
+	"sort"
+	"sync/atomic"
+)
+
 type SchemaConfig struct {
 	Query        *Object
 	Mutation     *Object
@@ -7,6 +13,9 @@ type SchemaConfig struct {
 	Types        []Type
 	Directives   []*Directive
 	Extensions   []Extension
+
+	// AppliedDirectives are the directives used on the schema itself.
+	AppliedDirectives AppliedDirectives
 }
 
 type TypeMap map[string]Type
@@ -41,12 +50,21 @@ type Schema struct {
 	implementations  map[string][]*Object
 	possibleTypeMap  map[string]map[string]bool
 	extensions       []Extension
+
+	appliedDirectives AppliedDirectives
+
+	// id identifies the types of the schema, e.g. for a DocumentCache, and
+	// changes whenever types are added.
+	id uint64
 }
 
+// schemaIDs is the last id given to a schema.
+var schemaIDs uint64
+
 func NewSchema(config SchemaConfig) (Schema, error) {
 	var err error
 
-	schema := Schema{}
+	schema := Schema{id: atomic.AddUint64(&schemaIDs, 1)}
 
 	if err = invariant(config.Query != nil, "Schema query must be Object Type but got: nil."); err != nil {
 		return schema, err
@@ -127,7 +145,7 @@ func NewSchema(config SchemaConfig) (Schema, error) {
 
 	// Enforce correct interface implementations
 	for _, ttype := range schema.typeMap {
-		if ttype, ok := ttype.(*Object); ok {
+		if ttype, ok := ttype.(implementingType); ok {
 			for _, iface := range ttype.Interfaces() {
 				err := assertObjectImplementsInterface(&schema, ttype, iface)
 				if err != nil {
@@ -137,6 +155,12 @@ func NewSchema(config SchemaConfig) (Schema, error) {
 		}
 	}
 
+	// Ensure the applied directives are defined for their location
+	schema.appliedDirectives = config.AppliedDirectives
+	if err = assertAppliedDirectives(&schema); err != nil {
+		return schema, err
+	}
+
 	// Add extensions from config
 	if len(config.Extensions) != 0 {
 		schema.extensions = config.Extensions
@@ -148,6 +172,7 @@ func NewSchema(config SchemaConfig) (Schema, error) {
 //Added Check implementation of interfaces at runtime..
 //Add Implementations at Runtime..
 func (gq *Schema) AddImplementation() error {
+	gq.id = atomic.AddUint64(&schemaIDs, 1)
 
 	// Keep track of all implementations by interface name.
 	if gq.implementations == nil {
@@ -168,7 +193,7 @@ func (gq *Schema) AddImplementation() error {
 
 	// Enforce correct interface implementations
 	for _, ttype := range gq.typeMap {
-		if ttype, ok := ttype.(*Object); ok {
+		if ttype, ok := ttype.(implementingType); ok {
 			for _, iface := range ttype.Interfaces() {
 				err := assertObjectImplementsInterface(gq, ttype, iface)
 				if err != nil {
@@ -221,6 +246,11 @@ func (gq *Schema) Directive(name string) *Directive {
 	return nil
 }
 
+// AppliedDirectives returns the directives used on the schema itself.
+func (gq *Schema) AppliedDirectives() AppliedDirectives {
+	return gq.appliedDirectives
+}
+
 func (gq *Schema) TypeMap() TypeMap {
 	return gq.typeMap
 }
@@ -313,6 +343,20 @@ func typeMapReducer(schema *Schema, typeMap TypeMap, objectType Type) (TypeMap,
 				return typeMap, err
 			}
 		}
+		if objectType, ok := objectType.(*Interface); ok {
+			interfaces := objectType.Interfaces()
+			if objectType.err != nil {
+				return typeMap, objectType.err
+			}
+			for _, innerObjectType := range interfaces {
+				if innerObjectType.err != nil {
+					return typeMap, innerObjectType.err
+				}
+				if typeMap, err = typeMapReducer(schema, typeMap, innerObjectType); err != nil {
+					return typeMap, err
+				}
+			}
+		}
 	case *Object:
 		interfaces := objectType.Interfaces()
 		if objectType.err != nil {
@@ -378,7 +422,46 @@ func typeMapReducer(schema *Schema, typeMap TypeMap, objectType Type) (TypeMap,
 	return typeMap, nil
 }
 
-func assertObjectImplementsInterface(schema *Schema, object *Object, iface *Interface) error {
+// implementingType is implemented by the types which may implement interfaces,
+// Objects and Interfaces.
+type implementingType interface {
+	Composite
+	Fields() FieldDefinitionMap
+	Interfaces() []*Interface
+}
+
+var _ implementingType = (*Object)(nil)
+var _ implementingType = (*Interface)(nil)
+
+// assertObjectImplementsInterface asserts that the given Object, or Interface,
+// correctly implements the interface, including the interfaces the interface
+// itself implements.
+func assertObjectImplementsInterface(schema *Schema, object implementingType, iface *Interface) error {
+	// Assert the interfaces implemented by the interface are also implemented.
+	for _, transitive := range iface.Interfaces() {
+		err := invariantf(
+			transitive.Name() != object.Name(),
+			`Type %v cannot implement %v because it would create a circular reference.`, object, iface,
+		)
+		if err != nil {
+			return err
+		}
+		implemented := false
+		for _, objectIface := range object.Interfaces() {
+			if objectIface.Name() == transitive.Name() {
+				implemented = true
+				break
+			}
+		}
+		err = invariantf(
+			implemented,
+			`Type %v must implement %v because it is implemented by %v.`, object, transitive, iface,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
 	objectFieldMap := object.Fields()
 	ifaceFieldMap := iface.Fields()
 
@@ -528,11 +611,18 @@ func isTypeSubTypeOf(schema *Schema, maybeSubType Type, superType Type) bool {
 	}
 
 	// If superType type is an abstract type, maybeSubType type may be a currently
-	// possible object type.
+	// possible object type or an interface implementing it.
 	if superType, ok := superType.(*Interface); ok {
 		if maybeSubType, ok := maybeSubType.(*Object); ok && schema.IsPossibleType(superType, maybeSubType) {
 			return true
 		}
+		if maybeSubType, ok := maybeSubType.(*Interface); ok {
+			for _, iface := range maybeSubType.Interfaces() {
+				if iface.Name() == superType.Name() {
+					return true
+				}
+			}
+		}
 	}
 	if superType, ok := superType.(*Union); ok {
 		if maybeSubType, ok := maybeSubType.(*Object); ok && schema.IsPossibleType(superType, maybeSubType) {
@@ -543,3 +633,128 @@ func isTypeSubTypeOf(schema *Schema, maybeSubType Type, superType Type) bool {
 	// Otherwise, the child type is not a valid subtype of the parent type.
 	return false
 }
+
+// assertAppliedDirectives checks that the directives applied to the elements
+// of the schema are defined by the schema for the location of the elements.
+func assertAppliedDirectives(schema *Schema) error {
+	if err := assertAppliedDirectivesAt(schema, schema.AppliedDirectives(), DirectiveLocationSchema, "schema"); err != nil {
+		return err
+	}
+	for _, directive := range schema.Directives() {
+		for _, arg := range directive.Args {
+			coordinate := fmt.Sprintf("@%v(%v:)", directive.Name, arg.Name())
+			if err := assertAppliedDirectivesAt(schema, arg.AppliedDirectives, DirectiveLocationArgumentDefinition, coordinate); err != nil {
+				return err
+			}
+		}
+	}
+
+	typeNames := []string{}
+	for name := range schema.TypeMap() {
+		typeNames = append(typeNames, name)
+	}
+	sort.Strings(typeNames)
+	for _, name := range typeNames {
+		var err error
+		switch ttype := schema.Type(name).(type) {
+		case *Scalar:
+			err = assertAppliedDirectivesAt(schema, ttype.AppliedDirectives(), DirectiveLocationScalar, name)
+		case *Object:
+			if err = assertAppliedDirectivesAt(schema, ttype.AppliedDirectives(), DirectiveLocationObject, name); err == nil {
+				err = assertFieldsAppliedDirectives(schema, name, ttype.Fields())
+			}
+		case *Interface:
+			if err = assertAppliedDirectivesAt(schema, ttype.AppliedDirectives(), DirectiveLocationInterface, name); err == nil {
+				err = assertFieldsAppliedDirectives(schema, name, ttype.Fields())
+			}
+		case *Union:
+			err = assertAppliedDirectivesAt(schema, ttype.AppliedDirectives(), DirectiveLocationUnion, name)
+		case *Enum:
+			err = assertAppliedDirectivesAt(schema, ttype.AppliedDirectives(), DirectiveLocationEnum, name)
+			for _, value := range ttype.Values() {
+				if err != nil {
+					break
+				}
+				err = assertAppliedDirectivesAt(schema, value.AppliedDirectives, DirectiveLocationEnumValue, name+"."+value.Name)
+			}
+		case *InputObject:
+			err = assertAppliedDirectivesAt(schema, ttype.AppliedDirectives(), DirectiveLocationInputObject, name)
+			fields := ttype.Fields()
+			for _, fieldName := range sortedInputFieldNames(fields) {
+				if err != nil {
+					break
+				}
+				err = assertAppliedDirectivesAt(schema, fields[fieldName].AppliedDirectives, DirectiveLocationInputFieldDefinition, name+"."+fieldName)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func assertFieldsAppliedDirectives(schema *Schema, typeName string, fields FieldDefinitionMap) error {
+	fieldNames := []string{}
+	for fieldName := range fields {
+		fieldNames = append(fieldNames, fieldName)
+	}
+	sort.Strings(fieldNames)
+	for _, fieldName := range fieldNames {
+		field := fields[fieldName]
+		coordinate := typeName + "." + fieldName
+		if err := assertAppliedDirectivesAt(schema, field.AppliedDirectives, DirectiveLocationFieldDefinition, coordinate); err != nil {
+			return err
+		}
+		for _, arg := range field.Args {
+			argCoordinate := fmt.Sprintf("%v(%v:)", coordinate, arg.Name())
+			if err := assertAppliedDirectivesAt(schema, arg.AppliedDirectives, DirectiveLocationArgumentDefinition, argCoordinate); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func sortedInputFieldNames(fields InputObjectFieldMap) []string {
+	names := []string{}
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// assertAppliedDirectivesAt checks the directives applied to the element of
+// the schema at the given coordinate, e.g. "Query.user(id:)".
+func assertAppliedDirectivesAt(schema *Schema, directives AppliedDirectives, location string, coordinate string) error {
+	used := map[string]bool{}
+	for _, applied := range directives {
+		if err := invariantf(applied != nil && applied.Name != "",
+			`%v applied directives must be named.`, coordinate); err != nil {
+			return err
+		}
+		directive := schema.Directive(applied.Name)
+		if err := invariantf(directive != nil,
+			`Unknown directive "@%v" used on %v.`, applied.Name, coordinate); err != nil {
+			return err
+		}
+		allowed := false
+		for _, directiveLocation := range directive.Locations {
+			if directiveLocation == location {
+				allowed = true
+				break
+			}
+		}
+		if err := invariantf(allowed,
+			`Directive "@%v" may not be used on %v, but is used on %v.`, applied.Name, location, coordinate); err != nil {
+			return err
+		}
+		if err := invariantf(directive.IsRepeatable || !used[applied.Name],
+			`Directive "@%v" can only be used once on %v.`, applied.Name, coordinate); err != nil {
+			return err
+		}
+		used[applied.Name] = true
+	}
+	return nil
+}
@@ -0,0 +1,350 @@
+package graphql
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/printer"
+)
+
+// PrintSchema prints the given schema in the schema definition language (SDL).
+//
+// The introspection types, the specified scalars and the specified directives
+// are left out. Directives, types, fields, arguments and enum values are sorted
+// by name so the output is deterministic and suitable for snapshots.
+func PrintSchema(schema Schema) string {
+	return printFilteredSchema(&schema, isDefinedDirective, isDefinedType)
+}
+
+// PrintIntrospectionSchema prints the introspection types and the specified
+// directives of the given schema in the schema definition language (SDL).
+func PrintIntrospectionSchema(schema Schema) string {
+	return printFilteredSchema(&schema, isSpecifiedDirective, isIntrospectionType)
+}
+
+func isSpecifiedDirective(directive *Directive) bool {
+	for _, specified := range SpecifiedDirectives {
+		if specified.Name == directive.Name {
+			return true
+		}
+	}
+	return false
+}
+
+func isDefinedDirective(directive *Directive) bool {
+	return !isSpecifiedDirective(directive)
+}
+
+func isIntrospectionType(ttype Type) bool {
+	return strings.HasPrefix(ttype.Name(), "__")
+}
+
+func isSpecifiedScalarType(ttype Type) bool {
+	for _, scalar := range specifiedScalarTypes {
+		if ttype.Name() == scalar.Name() {
+			return true
+		}
+	}
+	return false
+}
+
+func isDefinedType(ttype Type) bool {
+	return !isIntrospectionType(ttype) && !isSpecifiedScalarType(ttype)
+}
+
+func printFilteredSchema(schema *Schema, directiveFilter func(*Directive) bool, typeFilter func(Type) bool) string {
+	directives := []*Directive{}
+	for _, directive := range schema.Directives() {
+		if directiveFilter(directive) {
+			directives = append(directives, directive)
+		}
+	}
+	sort.Slice(directives, func(i, j int) bool {
+		return directives[i].Name < directives[j].Name
+	})
+
+	typeNames := []string{}
+	for typeName, ttype := range schema.TypeMap() {
+		if typeFilter(ttype) {
+			typeNames = append(typeNames, typeName)
+		}
+	}
+	sort.Strings(typeNames)
+
+	definitions := []string{}
+	if def := printSchemaDefinition(schema); def != "" {
+		definitions = append(definitions, def)
+	}
+	for _, directive := range directives {
+		definitions = append(definitions, printDirective(schema, directive))
+	}
+	for _, typeName := range typeNames {
+		definitions = append(definitions, printType(schema, schema.Type(typeName)))
+	}
+	return strings.Join(definitions, "\n\n") + "\n"
+}
+
+// printSchemaDefinition prints the schema definition, which is only needed
+// when the root types are not named after their operation or directives are
+// applied to the schema.
+func printSchemaDefinition(schema *Schema) string {
+	if isSchemaOfCommonNames(schema) && len(schema.AppliedDirectives()) == 0 {
+		return ""
+	}
+	operationTypes := []string{}
+	if query := schema.QueryType(); query != nil {
+		operationTypes = append(operationTypes, fmt.Sprintf("  query: %v", query.Name()))
+	}
+	if mutation := schema.MutationType(); mutation != nil {
+		operationTypes = append(operationTypes, fmt.Sprintf("  mutation: %v", mutation.Name()))
+	}
+	if subscription := schema.SubscriptionType(); subscription != nil {
+		operationTypes = append(operationTypes, fmt.Sprintf("  subscription: %v", subscription.Name()))
+	}
+	return fmt.Sprintf("schema%v {\n%v\n}", printAppliedDirectives(schema, schema.AppliedDirectives()), strings.Join(operationTypes, "\n"))
+}
+
+func isSchemaOfCommonNames(schema *Schema) bool {
+	if query := schema.QueryType(); query != nil && query.Name() != "Query" {
+		return false
+	}
+	if mutation := schema.MutationType(); mutation != nil && mutation.Name() != "Mutation" {
+		return false
+	}
+	if subscription := schema.SubscriptionType(); subscription != nil && subscription.Name() != "Subscription" {
+		return false
+	}
+	return true
+}
+
+func printType(schema *Schema, ttype Type) string {
+	switch ttype := ttype.(type) {
+	case *Scalar:
+		return printDescription(ttype.Description(), "", true) +
+			"scalar " + ttype.Name() + printAppliedDirectives(schema, ttype.AppliedDirectives())
+	case *Object:
+		return printObject(schema, ttype)
+	case *Interface:
+		return printDescription(ttype.Description(), "", true) +
+			"interface " + ttype.Name() + printImplementedInterfaces(ttype.Interfaces()) +
+			printAppliedDirectives(schema, ttype.AppliedDirectives()) + printFields(schema, ttype.Fields())
+	case *Union:
+		return printUnion(schema, ttype)
+	case *Enum:
+		return printEnum(schema, ttype)
+	case *InputObject:
+		return printInputObject(schema, ttype)
+	}
+	return ""
+}
+
+func printObject(schema *Schema, object *Object) string {
+	return printDescription(object.PrivateDescription, "", true) +
+		"type " + object.Name() + printImplementedInterfaces(object.Interfaces()) +
+		printAppliedDirectives(schema, object.AppliedDirectives()) + printFields(schema, object.Fields())
+}
+
+func printImplementedInterfaces(interfaces []*Interface) string {
+	if len(interfaces) == 0 {
+		return ""
+	}
+	names := []string{}
+	for _, iface := range interfaces {
+		names = append(names, iface.Name())
+	}
+	return " implements " + strings.Join(names, " & ")
+}
+
+func printUnion(schema *Schema, union *Union) string {
+	possibleTypes := ""
+	if types := union.Types(); len(types) > 0 {
+		names := []string{}
+		for _, object := range types {
+			names = append(names, object.Name())
+		}
+		possibleTypes = " = " + strings.Join(names, " | ")
+	}
+	return printDescription(union.Description(), "", true) +
+		"union " + union.Name() + printAppliedDirectives(schema, union.AppliedDirectives()) + possibleTypes
+}
+
+func printEnum(schema *Schema, enum *Enum) string {
+	values := append([]*EnumValueDefinition{}, enum.Values()...)
+	sort.Slice(values, func(i, j int) bool {
+		return values[i].Name < values[j].Name
+	})
+	lines := []string{}
+	for i, value := range values {
+		lines = append(lines, printDescription(value.Description, "  ", i == 0)+
+			"  "+value.Name+printDeprecated(value.DeprecationReason)+
+			printAppliedDirectives(schema, value.AppliedDirectives))
+	}
+	return printDescription(enum.Description(), "", true) +
+		"enum " + enum.Name() + printAppliedDirectives(schema, enum.AppliedDirectives()) + printBlock(lines)
+}
+
+func printInputObject(schema *Schema, inputObject *InputObject) string {
+	fieldMap := inputObject.Fields()
+	fieldNames := []string{}
+	for fieldName := range fieldMap {
+		fieldNames = append(fieldNames, fieldName)
+	}
+	sort.Strings(fieldNames)
+	lines := []string{}
+	for i, fieldName := range fieldNames {
+		field := fieldMap[fieldName]
+		lines = append(lines, printDescription(field.Description(), "  ", i == 0)+
+			"  "+printInputValue(field.Name(), field.Type, field.DefaultValue)+
+			printAppliedDirectives(schema, field.AppliedDirectives))
+	}
+	return printDescription(inputObject.Description(), "", true) +
+		"input " + inputObject.Name() + printAppliedDirectives(schema, inputObject.AppliedDirectives()) + printBlock(lines)
+}
+
+func printFields(schema *Schema, fieldMap FieldDefinitionMap) string {
+	fieldNames := []string{}
+	for fieldName := range fieldMap {
+		fieldNames = append(fieldNames, fieldName)
+	}
+	sort.Strings(fieldNames)
+	lines := []string{}
+	for i, fieldName := range fieldNames {
+		field := fieldMap[fieldName]
+		lines = append(lines, printDescription(field.Description, "  ", i == 0)+
+			"  "+field.Name+printArgs(schema, field.Args, "  ")+": "+field.Type.String()+
+			printDeprecated(field.DeprecationReason)+printAppliedDirectives(schema, field.AppliedDirectives))
+	}
+	return printBlock(lines)
+}
+
+func printBlock(lines []string) string {
+	if len(lines) == 0 {
+		return ""
+	}
+	return " {\n" + strings.Join(lines, "\n") + "\n}"
+}
+
+func printArgs(schema *Schema, args []*Argument, indentation string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	args = append([]*Argument{}, args...)
+	sort.Slice(args, func(i, j int) bool {
+		return args[i].Name() < args[j].Name()
+	})
+
+	// If every arg does not have a description, print them on one line.
+	described := false
+	for _, arg := range args {
+		if arg.Description() != "" {
+			described = true
+			break
+		}
+	}
+	if !described {
+		values := []string{}
+		for _, arg := range args {
+			values = append(values, printInputValue(arg.Name(), arg.Type, arg.DefaultValue)+
+				printAppliedDirectives(schema, arg.AppliedDirectives))
+		}
+		return "(" + strings.Join(values, ", ") + ")"
+	}
+
+	lines := []string{}
+	for i, arg := range args {
+		lines = append(lines, printDescription(arg.Description(), "  "+indentation, i == 0)+
+			"  "+indentation+printInputValue(arg.Name(), arg.Type, arg.DefaultValue)+
+			printAppliedDirectives(schema, arg.AppliedDirectives))
+	}
+	return "(\n" + strings.Join(lines, "\n") + "\n" + indentation + ")"
+}
+
+func printInputValue(name string, ttype Input, defaultValue interface{}) string {
+	value := name + ": " + ttype.String()
+	if defaultValue != nil && !isNullish(defaultValue) {
+		if valueAST := astFromValue(defaultValue, ttype); valueAST != nil {
+			value += fmt.Sprintf(" = %v", printer.Print(valueAST))
+		}
+	}
+	return value
+}
+
+func printDirective(schema *Schema, directive *Directive) string {
+	repeatable := ""
+	if directive.IsRepeatable {
+		repeatable = " repeatable"
+	}
+	return printDescription(directive.Description, "", true) +
+		"directive @" + directive.Name + printArgs(schema, directive.Args, "") +
+		repeatable + " on " + strings.Join(directive.Locations, " | ")
+}
+
+// printAppliedDirectives prints the directives applied to an element of the
+// schema, with their arguments sorted by name.
+func printAppliedDirectives(schema *Schema, directives AppliedDirectives) string {
+	printed := ""
+	for _, applied := range directives {
+		printed += " @" + applied.Name
+		directive := schema.Directive(applied.Name)
+		if directive == nil {
+			continue
+		}
+		args := []string{}
+		for _, arg := range sortedArgs(directive.Args) {
+			value, ok := applied.Args[arg.Name()]
+			if !ok || isNullish(value) {
+				continue
+			}
+			if valueAST := astFromValue(value, arg.Type); valueAST != nil {
+				args = append(args, fmt.Sprintf("%v: %v", arg.Name(), printer.Print(valueAST)))
+			}
+		}
+		if len(args) > 0 {
+			printed += "(" + strings.Join(args, ", ") + ")"
+		}
+	}
+	return printed
+}
+
+func printDeprecated(reason string) string {
+	if reason == "" {
+		return ""
+	}
+	if reason == DefaultDeprecationReason {
+		return " @deprecated"
+	}
+	reasonAST := ast.NewStringValue(&ast.StringValue{
+		Value: reason,
+	})
+	return fmt.Sprintf(" @deprecated(reason: %v)", printer.Print(reasonAST))
+}
+
+// printDescription prints a description as a block string on the lines
+// preceding the definition it describes.
+func printDescription(description string, indentation string, firstInBlock bool) string {
+	if description == "" {
+		return ""
+	}
+	prefix := indentation
+	if !firstInBlock && indentation != "" {
+		prefix = "\n" + indentation
+	}
+
+	escaped := strings.Replace(description, `"""`, `\"""`, -1)
+	lines := strings.Split(escaped, "\n")
+	if len(lines) == 1 && len(escaped) < 70 && !strings.HasSuffix(escaped, `"`) && !strings.HasPrefix(escaped, " ") {
+		return prefix + `"""` + escaped + `"""` + "\n"
+	}
+
+	block := prefix + `"""` + "\n"
+	for _, line := range lines {
+		if line == "" {
+			block += "\n"
+			continue
+		}
+		block += indentation + line + "\n"
+	}
+	return block + indentation + `"""` + "\n"
+}
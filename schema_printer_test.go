@@ -0,0 +1,236 @@
+package graphql_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/testutil"
+)
+
+func TestPrintSchema_PrintsGoDefinedSchema(t *testing.T) {
+	episodeEnum := graphql.NewEnum(graphql.EnumConfig{
+		Name:        "Episode",
+		Description: "One of the films in the Star Wars Trilogy",
+		Values: graphql.EnumValueConfigMap{
+			"NEWHOPE": &graphql.EnumValueConfig{
+				Value: 4,
+			},
+			"EMPIRE": &graphql.EnumValueConfig{
+				Value:             5,
+				DeprecationReason: "Use NEWHOPE",
+			},
+			"JEDI": &graphql.EnumValueConfig{
+				Value:             6,
+				DeprecationReason: graphql.DefaultDeprecationReason,
+			},
+		},
+	})
+	filterInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "Filter",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"episode": &graphql.InputObjectFieldConfig{
+				Type:         episodeEnum,
+				DefaultValue: 4,
+			},
+			"names": &graphql.InputObjectFieldConfig{
+				Type:         graphql.NewList(graphql.NewNonNull(graphql.String)),
+				Description:  "Names to match",
+				DefaultValue: []interface{}{"Luke", `Han "Solo"`},
+			},
+		},
+	})
+	characterInterface := graphql.NewInterface(graphql.InterfaceConfig{
+		Name: "Character",
+		Fields: graphql.Fields{
+			"name": &graphql.Field{
+				Type: graphql.String,
+			},
+		},
+	})
+	humanType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Human",
+		Description: "A humanoid creature.\n\nFrom a galaxy far, far away.",
+		Interfaces:  []*graphql.Interface{characterInterface},
+		Fields: graphql.Fields{
+			"name": &graphql.Field{
+				Type: graphql.String,
+			},
+			"height": &graphql.Field{
+				Type:              graphql.Float,
+				DeprecationReason: "Use size",
+			},
+		},
+	})
+	rootType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Root",
+		Fields: graphql.Fields{
+			"hero": &graphql.Field{
+				Type:        characterInterface,
+				Description: "The hero",
+				Args: graphql.FieldConfigArgument{
+					"filter": &graphql.ArgumentConfig{
+						Type:         filterInput,
+						DefaultValue: map[string]interface{}{"episode": 5},
+					},
+					"limit": &graphql.ArgumentConfig{
+						Type:         graphql.NewNonNull(graphql.Int),
+						DefaultValue: 10,
+					},
+				},
+			},
+			"search": &graphql.Field{
+				Type: graphql.NewList(humanType),
+				Args: graphql.FieldConfigArgument{
+					"text": &graphql.ArgumentConfig{
+						Type:        graphql.String,
+						Description: "Text to search for",
+					},
+				},
+			},
+		},
+	})
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: rootType,
+		Types: []graphql.Type{humanType},
+		Directives: append([]*graphql.Directive{
+			graphql.NewDirective(graphql.DirectiveConfig{
+				Name:        "cached",
+				Description: "Caches the field",
+				Locations:   []string{graphql.DirectiveLocationField, graphql.DirectiveLocationFragmentSpread},
+				Args: graphql.FieldConfigArgument{
+					"ttl": &graphql.ArgumentConfig{
+						Type:         graphql.Int,
+						DefaultValue: 60,
+					},
+				},
+			}),
+		}, graphql.SpecifiedDirectives...),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error creating schema: %v", err)
+	}
+
+	expected := `schema {
+  query: Root
+}
+
+"""Caches the field"""
+directive @cached(ttl: Int = 60) on FIELD | FRAGMENT_SPREAD
+
+interface Character {
+  name: String
+}
+
+"""One of the films in the Star Wars Trilogy"""
+enum Episode {
+  EMPIRE @deprecated(reason: "Use NEWHOPE")
+  JEDI @deprecated
+  NEWHOPE
+}
+
+input Filter {
+  episode: Episode = NEWHOPE
+
+  """Names to match"""
+  names: [String!] = ["Luke", "Han \"Solo\""]
+}
+
+"""
+A humanoid creature.
+
+From a galaxy far, far away.
+"""
+type Human implements Character {
+  height: Float @deprecated(reason: "Use size")
+  name: String
+}
+
+type Root {
+  """The hero"""
+  hero(filter: Filter = {episode: EMPIRE}, limit: Int! = 10): Character
+  search(
+    """Text to search for"""
+    text: String
+  ): [Human]
+}
+`
+	if printed := graphql.PrintSchema(schema); printed != expected {
+		t.Fatalf("Unexpected schema, Diff: %v", testutil.Diff(expected, printed))
+	}
+}
+
+func TestPrintSchema_RoundTripsBuiltSchema(t *testing.T) {
+	sdl := `directive @auth(requires: Role = ADMIN) on FIELD_DEFINITION | OBJECT
+
+scalar Date
+
+input NewPost {
+  body: String!
+  tags: [String] = ["news"]
+  title: String = "Untitled"
+}
+
+interface Node {
+  id: ID!
+}
+
+type Post implements Node {
+  author: User
+  body: String
+  id: ID!
+  published: Date
+}
+
+type Query {
+  node(id: ID!): Node
+  posts(first: Int = 10, offset: Int = 0): [Post!]!
+  search(term: String!): [SearchResult]
+}
+
+enum Role {
+  ADMIN
+  USER
+}
+
+union SearchResult = Post | User
+
+type Subscription {
+  postAdded: Post
+}
+
+type User implements Node {
+  id: ID!
+  name: String @deprecated(reason: "Use displayName")
+  role: Role
+}
+`
+	schema := buildSchemaOrFail(t, sdl)
+	printed := graphql.PrintSchema(schema)
+	if printed != sdl {
+		t.Fatalf("Unexpected schema, Diff: %v", testutil.Diff(sdl, printed))
+	}
+	if reprinted := graphql.PrintSchema(buildSchemaOrFail(t, printed)); reprinted != printed {
+		t.Fatalf("Unexpected schema, Diff: %v", testutil.Diff(printed, reprinted))
+	}
+}
+
+func TestPrintIntrospectionSchema_PrintsSpecifiedDirectivesAndIntrospectionTypes(t *testing.T) {
+	schema := buildSchemaOrFail(t, `type Query { a: String }`)
+	printed := graphql.PrintIntrospectionSchema(schema)
+
+	expected := []string{
+		"  reason: String = \"No longer supported\"\n) on FIELD_DEFINITION | ENUM_VALUE",
+		"directive @include(\n  \"\"\"Included when true.\"\"\"\n  if: Boolean!\n) on FIELD | FRAGMENT_SPREAD | INLINE_FRAGMENT",
+		"type __Schema {",
+		"enum __TypeKind {",
+	}
+	for _, part := range expected {
+		if !strings.Contains(printed, part) {
+			t.Fatalf("expected introspection schema to contain %q, got:\n%v", part, printed)
+		}
+	}
+	if strings.Contains(printed, "type Query") {
+		t.Fatalf("expected introspection schema to leave out defined types, got:\n%v", printed)
+	}
+}
@@ -3,10 +3,11 @@ package graphql
 import (
 	"context"
 	"fmt"
+	"io"
+	"reflect"
 
 	"github.com/graphql-go/graphql/gqlerrors"
-	"github.com/graphql-go/graphql/language/parser"
-	"github.com/graphql-go/graphql/language/source"
+	"github.com/graphql-go/graphql/language/ast"
 )
 
 // SubscribeParams parameters for subscribing
@@ -23,46 +24,88 @@ type SubscribeParams struct {
 
 // Subscribe performs a subscribe operation on the given query and schema
 // To finish a subscription you can simply close the channel from inside the `Subscribe` function
-// currently does not support extensions hooks
+//
+// The request is resolved, parsed, validated and checked as by Do, so that
+// persisted queries, registered operations, DocumentCache, ValidationRules,
+// the complexity of the operation and the extensions apply to it too. Queries
+// and mutations are executed once, their result being the only one sent.
 func Subscribe(p Params) chan *Result {
-
-	source := source.NewSource(&source.Source{
-		Body: []byte(p.RequestString),
-		Name: "GraphQL request",
-	})
-
-	// TODO run extensions hooks
-
-	// parse the source
-	AST, err := parser.Parse(parser.ParseParams{Source: source})
-	if err != nil {
-
-		// merge the errors from extensions and the original error from parser
+	AST, prepared, errs := prepareRequest(&p)
+	if len(errs) != 0 {
 		return sendOneResultAndClose(&Result{
-			Errors: gqlerrors.FormatErrors(err),
+			Errors: errs,
 		})
 	}
+	params := ExecuteParams{
+		Schema:            p.Schema,
+		Root:              p.RootObject,
+		AST:               AST,
+		OperationName:     p.OperationName,
+		Args:              p.VariableValues,
+		Context:           p.Context,
+		Concurrency:       p.Concurrency,
+		PreparedOperation: prepared,
+		UnorderedResults:  p.UnorderedResults,
+	}
+	// documents without the operation are left to ExecuteSubscription to report
+	if operation, _, err := getOperation(AST, p.OperationName); err == nil && operation.Operation != ast.OperationTypeSubscription {
+		resultChannel := make(chan *Result, 1)
+		go func() {
+			defer close(resultChannel)
+			resultChannel <- Execute(params)
+		}()
+		return resultChannel
+	}
+	return ExecuteSubscription(params)
+}
 
-	// validate document
-	validationResult := ValidateDocument(&p.Schema, AST, nil)
+// SourceStream is a source stream of subscription events, which the Subscribe
+// function of a subscription field may return, see ExecuteSubscription.
+type SourceStream interface {
+	// Next returns the next event of the stream, blocking until there is one
+	// or the given context is done. It returns io.EOF once the stream ends,
+	// other errors being reported as the errors of the result of the event.
+	Next(ctx context.Context) (interface{}, error)
 
-	if !validationResult.IsValid {
-		// run validation finish functions for extensions
-		return sendOneResultAndClose(&Result{
-			Errors: validationResult.Errors,
-		})
+	// Close releases the resources of the stream once the subscription ends.
+	Close()
+}
 
+// channelSourceStream is the SourceStream of a channel, of any type.
+type channelSourceStream struct {
+	channel reflect.Value
+}
+
+// newSourceStream returns the SourceStream of the given Subscribe result, if
+// it is one or a channel.
+func newSourceStream(source interface{}) (SourceStream, bool) {
+	if stream, ok := source.(SourceStream); ok {
+		return stream, true
+	}
+	channel := reflect.ValueOf(source)
+	if channel.Kind() != reflect.Chan || channel.Type().ChanDir()&reflect.RecvDir == 0 {
+		return nil, false
 	}
-	return ExecuteSubscription(ExecuteParams{
-		Schema:        p.Schema,
-		Root:          p.RootObject,
-		AST:           AST,
-		OperationName: p.OperationName,
-		Args:          p.VariableValues,
-		Context:       p.Context,
+	return &channelSourceStream{channel: channel}, true
+}
+
+func (s *channelSourceStream) Next(ctx context.Context) (interface{}, error) {
+	chosen, event, ok := reflect.Select([]reflect.SelectCase{
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())},
+		{Dir: reflect.SelectRecv, Chan: s.channel},
 	})
+	if chosen == 0 {
+		return nil, ctx.Err()
+	}
+	if !ok {
+		return nil, io.EOF
+	}
+	return event.Interface(), nil
 }
 
+// Close does not close the channel, which its sender does.
+func (s *channelSourceStream) Close() {}
+
 func sendOneResultAndClose(res *Result) chan *Result {
 	resultChannel := make(chan *Result, 1)
 	resultChannel <- res
@@ -72,6 +115,12 @@ func sendOneResultAndClose(res *Result) chan *Result {
 
 // ExecuteSubscription is similar to graphql.Execute but returns a channel instead of a Result
 // currently does not support extensions
+//
+// The Subscribe function of the subscription field returns the source stream
+// of the subscription: either a SourceStream, or a channel of any type, each
+// of its events being executed into a result. Any other value is the only
+// event of the subscription. The subscription ends once the source stream
+// does or the context is done, which closes SourceStreams.
 func ExecuteSubscription(p ExecuteParams) chan *Result {
 
 	if p.Context == nil {
@@ -80,12 +129,15 @@ func ExecuteSubscription(p ExecuteParams) chan *Result {
 
 	var mapSourceToResponse = func(payload interface{}) *Result {
 		return Execute(ExecuteParams{
-			Schema:        p.Schema,
-			Root:          payload,
-			AST:           p.AST,
-			OperationName: p.OperationName,
-			Args:          p.Args,
-			Context:       p.Context,
+			Schema:            p.Schema,
+			Root:              payload,
+			AST:               p.AST,
+			OperationName:     p.OperationName,
+			Args:              p.Args,
+			Context:           p.Context,
+			Concurrency:       p.Concurrency,
+			PreparedOperation: p.PreparedOperation,
+			UnorderedResults:  p.UnorderedResults,
 		})
 	}
 	var resultChannel = make(chan *Result)
@@ -111,6 +163,7 @@ func ExecuteSubscription(p ExecuteParams) chan *Result {
 			OperationName: p.OperationName,
 			Args:          p.Args,
 			Context:       p.Context,
+			Prepared:      p.PreparedOperation,
 		})
 
 		if err != nil {
@@ -130,16 +183,14 @@ func ExecuteSubscription(p ExecuteParams) chan *Result {
 			return
 		}
 
+		responseNames := []string{}
 		fields := collectFields(collectFieldsParams{
-			ExeContext:   exeContext,
-			RuntimeType:  operationType,
-			SelectionSet: exeContext.Operation.GetSelectionSet(),
+			ExeContext:    exeContext,
+			RuntimeType:   operationType,
+			SelectionSet:  exeContext.Operation.GetSelectionSet(),
+			ResponseNames: &responseNames,
 		})
 
-		responseNames := []string{}
-		for name := range fields {
-			responseNames = append(responseNames, name)
-		}
 		responseName := responseNames[0]
 		fieldNodes := fields[responseName]
 		fieldNode := fieldNodes[0]
@@ -202,25 +253,33 @@ func ExecuteSubscription(p ExecuteParams) chan *Result {
 			return
 		}
 
-		switch fieldResult.(type) {
-		case chan interface{}:
-			sub := fieldResult.(chan interface{})
-			for {
-				select {
-				case <-p.Context.Done():
-					return
-
-				case res, more := <-sub:
-					if !more {
-						return
-					}
-					resultChannel <- mapSourceToResponse(res)
-				}
-			}
-		default:
+		stream, ok := newSourceStream(fieldResult)
+		if !ok {
 			resultChannel <- mapSourceToResponse(fieldResult)
 			return
 		}
+		defer stream.Close()
+		for {
+			event, err := stream.Next(p.Context)
+			if p.Context.Err() != nil || err == io.EOF {
+				return
+			}
+			var result *Result
+			if err != nil {
+				result = &Result{
+					Errors: []gqlerrors.FormattedError{
+						gqlerrors.FormatError(NewLocatedErrorWithPath(err, FieldASTsToNodeASTs(fieldNodes), fieldPath.AsArray())),
+					},
+				}
+			} else {
+				result = mapSourceToResponse(event)
+			}
+			select {
+			case <-p.Context.Done():
+				return
+			case resultChannel <- result:
+			}
+		}
 	}()
 
 	// return a result channel
@@ -1,9 +1,13 @@
 package graphql_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/graphql-go/graphql"
 	"github.com/graphql-go/graphql/testutil"
@@ -183,6 +187,68 @@ func TestSchemaSubscribe(t *testing.T) {
 			},
 		},
 
+		{
+			Name: "subscribe to a typed channel",
+			Schema: makeSubscriptionSchema(t, graphql.ObjectConfig{
+				Name: "Subscription",
+				Fields: graphql.Fields{
+					"sub_typed_channel": &graphql.Field{
+						Type: graphql.String,
+						Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+							return p.Source, nil
+						},
+						Subscribe: func(p graphql.ResolveParams) (interface{}, error) {
+							c := make(chan string)
+							go func() {
+								defer close(c)
+								for _, r := range []string{"a", "b"} {
+									c <- r
+								}
+							}()
+							return (<-chan string)(c), nil
+						},
+					},
+				},
+			}),
+			Query: `
+				subscription {
+					sub_typed_channel
+				}
+			`,
+			ExpectedResults: []testutil.TestResponse{
+				{Data: `{ "sub_typed_channel": "a" }`},
+				{Data: `{ "sub_typed_channel": "b" }`},
+			},
+		},
+		{
+			Name: "subscribe to a source stream reporting errors",
+			Schema: makeSubscriptionSchema(t, graphql.ObjectConfig{
+				Name: "Subscription",
+				Fields: graphql.Fields{
+					"sub_source_stream": &graphql.Field{
+						Type: graphql.String,
+						Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+							return p.Source, nil
+						},
+						Subscribe: func(p graphql.ResolveParams) (interface{}, error) {
+							return &testSourceStream{
+								events: []interface{}{"a", errors.New("got a stream error"), "b"},
+							}, nil
+						},
+					},
+				},
+			}),
+			Query: `
+				subscription {
+					sub_source_stream
+				}
+			`,
+			ExpectedResults: []testutil.TestResponse{
+				{Data: `{ "sub_source_stream": "a" }`},
+				{Errors: []string{"got a stream error"}},
+				{Data: `{ "sub_source_stream": "b" }`},
+			},
+		},
 		{
 			Name: "subscription_resolver_can_error",
 			Schema: makeSubscriptionSchema(t, graphql.ObjectConfig{
@@ -231,6 +297,162 @@ func TestSchemaSubscribe(t *testing.T) {
 	})
 }
 
+func TestSchemaSubscribe_ClosesSourceStreamsOfCancelledSubscriptions(t *testing.T) {
+	stream := &testSourceStream{
+		events: []interface{}{errors.New("got a stream error")},
+		closed: make(chan struct{}),
+	}
+	schema := makeSubscriptionSchema(t, graphql.ObjectConfig{
+		Name: "Subscription",
+		Fields: graphql.Fields{
+			"sub_source_stream": &graphql.Field{
+				Type: graphql.String,
+				Subscribe: func(p graphql.ResolveParams) (interface{}, error) {
+					return stream, nil
+				},
+			},
+		},
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	c := graphql.Subscribe(graphql.Params{
+		Context:       ctx,
+		RequestString: `subscription { sub: sub_source_stream }`,
+		Schema:        schema,
+	})
+
+	result := <-c
+	if len(result.Errors) != 1 || !reflect.DeepEqual(result.Errors[0].Path, []interface{}{"sub"}) {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+	// the subscriber goes away while the stream waits for its next event
+	cancel()
+	select {
+	case <-stream.closed:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected the source stream to be closed")
+	}
+	if _, ok := <-c; ok {
+		t.Fatalf("Expected the results to end")
+	}
+}
+
+func TestSchemaSubscribe_PreparesRequestsAsDo(t *testing.T) {
+	schema := makeSubscriptionSchema(t, graphql.ObjectConfig{
+		Name: "Subscription",
+		Fields: graphql.Fields{
+			"sub": &graphql.Field{
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source, nil
+				},
+				Subscribe: makeSubscribeToStringFunction([]string{"a", "b"}),
+			},
+		},
+	})
+	subscribe := func(p graphql.Params) []*graphql.Result {
+		p.Schema = schema
+		results := []*graphql.Result{}
+		for result := range graphql.Subscribe(p) {
+			results = append(results, result)
+		}
+		return results
+	}
+	expected := []*graphql.Result{
+		{Data: map[string]interface{}{"sub": "a"}},
+		{Data: map[string]interface{}{"sub": "b"}},
+	}
+
+	results := subscribe(graphql.Params{
+		RequestString:   `subscription { sub }`,
+		ValidationRules: append([]graphql.ValidationRuleFn{graphql.MaxFieldsRule(0)}, graphql.SpecifiedRules...),
+	})
+	if len(results) != 1 || len(results[0].Errors) != 1 || results[0].Errors[0].Message != graphql.MaxFieldsMessage("sub", 0) {
+		t.Fatalf("Expected the validation rules to apply, got %v", results)
+	}
+
+	store := graphql.NewLRUPersistedQueryStore(10)
+	hash := graphql.PersistedQueryHash(`subscription { sub }`)
+	results = subscribe(graphql.Params{
+		Extensions:       persistedQueryExtensions(hash),
+		PersistedQueries: store,
+	})
+	if len(results) != 1 {
+		t.Fatalf("Expected a single result, got %v", results)
+	}
+	expectPersistedQueryError(t, results[0], graphql.ErrPersistedQueryNotFound)
+	for _, query := range []string{`subscription { sub }`, ""} {
+		results = subscribe(graphql.Params{
+			RequestString:    query,
+			Extensions:       persistedQueryExtensions(hash),
+			PersistedQueries: store,
+		})
+		if len(results) != len(expected) {
+			t.Fatalf("Unexpected results, Diff: %v", testutil.Diff(expected, results))
+		}
+		for i := range expected {
+			if !testutil.EqualResults(expected[i], results[i]) {
+				t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected[i], results[i]))
+			}
+		}
+	}
+}
+
+func TestSchemaSubscribe_ExecutesQueriesOnce(t *testing.T) {
+	schema := makeSubscriptionSchema(t, graphql.ObjectConfig{
+		Name: "Subscription",
+		Fields: graphql.Fields{
+			"sub": &graphql.Field{
+				Type:      graphql.String,
+				Subscribe: makeSubscribeToStringFunction([]string{"a"}),
+			},
+		},
+	})
+	results := []*graphql.Result{}
+	for result := range graphql.Subscribe(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ hello }`,
+		RootObject:    map[string]interface{}{"hello": "world"},
+	}) {
+		results = append(results, result)
+	}
+	expected := &graphql.Result{
+		Data: map[string]interface{}{"hello": "world"},
+	}
+	if len(results) != 1 || !testutil.EqualResults(expected, results[0]) {
+		t.Fatalf("Unexpected results, Diff: %v", testutil.Diff(expected, results))
+	}
+}
+
+// testSourceStream is a graphql.SourceStream of the given events, the errors
+// of which are returned as such, waiting for its context once they are
+// consumed unless it has no closed channel.
+type testSourceStream struct {
+	events []interface{}
+	closed chan struct{}
+}
+
+func (s *testSourceStream) Next(ctx context.Context) (interface{}, error) {
+	if len(s.events) == 0 {
+		if s.closed == nil {
+			return nil, io.EOF
+		}
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	event := s.events[0]
+	s.events = s.events[1:]
+	if err, ok := event.(error); ok {
+		return nil, err
+	}
+	return event, nil
+}
+
+func (s *testSourceStream) Close() {
+	if s.closed != nil {
+		close(s.closed)
+	}
+}
+
 func makeSubscribeToStringFunction(elements []string) func(p graphql.ResolveParams) (interface{}, error) {
 	return func(p graphql.ResolveParams) (interface{}, error) {
 		c := make(chan interface{})
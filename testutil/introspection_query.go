@@ -16,6 +16,7 @@ var IntrospectionQuery = `
         args {
           ...InputValue
         }
+        isRepeatable
         # deprecated, but included for coverage till removed
 		onOperation
         onFragment
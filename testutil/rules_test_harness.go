@@ -531,6 +531,16 @@ func init() {
 				Name:      "onInputFieldDefinition",
 				Locations: []string{graphql.DirectiveLocationInputFieldDefinition},
 			}),
+			graphql.NewDirective(graphql.DirectiveConfig{
+				Name: "repeatable",
+				Locations: []string{
+					graphql.DirectiveLocationQuery,
+					graphql.DirectiveLocationField,
+					graphql.DirectiveLocationFragmentSpread,
+					graphql.DirectiveLocationInlineFragment,
+				},
+				IsRepeatable: true,
+			}),
 		},
 		Types: []graphql.Type{
 			catType,
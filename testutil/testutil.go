@@ -496,8 +496,23 @@ func EqualFormattedErrors(expected, actual []gqlerrors.FormattedError) bool {
 	return true
 }
 
+// unorderedData converts the ordered maps of result data to plain maps, so
+// that results compare equal to expectations written as map literals.
+func unorderedData(data interface{}) interface{} {
+	if data, ok := data.(*graphql.OrderedMap); ok && data != nil {
+		return data.Map()
+	}
+	return data
+}
+
+// EqualData reports whether the given result data are deeply equal, ignoring
+// the order of the fields of ordered maps.
+func EqualData(expected, data interface{}) bool {
+	return reflect.DeepEqual(unorderedData(expected), unorderedData(data))
+}
+
 func EqualResults(expected, result *graphql.Result) bool {
-	if !reflect.DeepEqual(expected.Data, result.Data) {
+	if !EqualData(expected.Data, result.Data) {
 		return false
 	}
 	return EqualFormattedErrors(expected.Errors, result.Errors)
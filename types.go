@@ -6,7 +6,11 @@ import (
 
 // type Schema interface{}
 
-// Result has the response, errors and extensions from the resolved schema
+// Result has the response, errors and extensions from the resolved schema.
+//
+// The Data of an executed operation is an *OrderedMap, or a
+// map[string]interface{} when unordered results were requested, as are the
+// objects it contains.
 type Result struct {
 	Data       interface{}                `json:"data"`
 	Errors     []gqlerrors.FormattedError `json:"errors,omitempty"`
@@ -1,10 +1,12 @@
 package graphql_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/graphql-go/graphql"
 	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/testutil"
 )
 
 var someScalarType = graphql.NewScalar(graphql.ScalarConfig{
@@ -1644,3 +1646,185 @@ func TestTypeSystem_ObjectsMustAdhereToInterfaceTheyImplement_RejectsAnObjectWit
 		t.Fatalf("Expected error: %v, got %v", expectedError, err)
 	}
 }
+
+func TestTypeSystem_InterfacesMayImplementInterfaces_AcceptsAnInterfaceImplementingAnInterface(t *testing.T) {
+	nodeInterface := graphql.NewInterface(graphql.InterfaceConfig{
+		Name: "Node",
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type: graphql.ID,
+			},
+		},
+	})
+	entityInterface := graphql.NewInterface(graphql.InterfaceConfig{
+		Name:       "Entity",
+		Interfaces: []*graphql.Interface{nodeInterface},
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.ID),
+			},
+			"owner": &graphql.Field{
+				Type: nodeInterface,
+			},
+		},
+	})
+	anotherObject := graphql.NewObject(graphql.ObjectConfig{
+		Name:       "AnotherObject",
+		Interfaces: []*graphql.Interface{nodeInterface, entityInterface},
+		IsTypeOf: func(p graphql.IsTypeOfParams) bool {
+			return true
+		},
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.ID),
+			},
+			"owner": &graphql.Field{
+				Type: entityInterface,
+			},
+		},
+	})
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"node": &graphql.Field{
+					Type: nodeInterface,
+				},
+			},
+		}),
+		Types: []graphql.Type{anotherObject},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !schema.IsPossibleType(nodeInterface, anotherObject) || !schema.IsPossibleType(entityInterface, anotherObject) {
+		t.Fatalf("expected AnotherObject to be a possible type of Node and Entity")
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ __type(name: "Entity") { interfaces { name } possibleTypes { name } } }`,
+	})
+	expected := &graphql.Result{
+		Data: map[string]interface{}{
+			"__type": map[string]interface{}{
+				"interfaces": []interface{}{
+					map[string]interface{}{"name": "Node"},
+				},
+				"possibleTypes": []interface{}{
+					map[string]interface{}{"name": "AnotherObject"},
+				},
+			},
+		},
+	}
+	if !testutil.EqualResults(expected, result) {
+		t.Fatalf("Unexpected result, Diff: %v", testutil.Diff(expected, result))
+	}
+}
+
+func TestTypeSystem_InterfacesMayImplementInterfaces_RejectsAnObjectNotImplementingTransitiveInterfaces(t *testing.T) {
+	nodeInterface := graphql.NewInterface(graphql.InterfaceConfig{
+		Name: "Node",
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type: graphql.ID,
+			},
+		},
+	})
+	entityInterface := graphql.NewInterface(graphql.InterfaceConfig{
+		Name:       "Entity",
+		Interfaces: []*graphql.Interface{nodeInterface},
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type: graphql.ID,
+			},
+		},
+	})
+	anotherObject := graphql.NewObject(graphql.ObjectConfig{
+		Name:       "AnotherObject",
+		Interfaces: []*graphql.Interface{entityInterface},
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type: graphql.ID,
+			},
+		},
+	})
+	_, err := schemaWithFieldType(anotherObject)
+	expectedError := `Type AnotherObject must implement Node because it is implemented by Entity.`
+	if err == nil || err.Error() != expectedError {
+		t.Fatalf("Expected error: %v, got %v", expectedError, err)
+	}
+}
+
+func TestTypeSystem_InterfacesMayImplementInterfaces_RejectsAnInterfaceMissingAnInterfaceField(t *testing.T) {
+	nodeInterface := graphql.NewInterface(graphql.InterfaceConfig{
+		Name: "Node",
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type: graphql.ID,
+			},
+		},
+	})
+	entityInterface := graphql.NewInterface(graphql.InterfaceConfig{
+		Name:       "Entity",
+		Interfaces: []*graphql.Interface{nodeInterface},
+		Fields: graphql.Fields{
+			"name": &graphql.Field{
+				Type: graphql.String,
+			},
+		},
+	})
+	_, err := schemaWithFieldType(entityInterface)
+	expectedError := `"Node" expects field "id" but "Entity" does not provide it.`
+	if err == nil || err.Error() != expectedError {
+		t.Fatalf("Expected error: %v, got %v", expectedError, err)
+	}
+}
+
+func TestTypeSystem_InterfacesMayImplementInterfaces_RejectsCircularInterfaces(t *testing.T) {
+	var aInterface, bInterface *graphql.Interface
+	aInterface = graphql.NewInterface(graphql.InterfaceConfig{
+		Name: "A",
+		Interfaces: graphql.InterfacesThunk(func() []*graphql.Interface {
+			return []*graphql.Interface{bInterface}
+		}),
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type: graphql.ID,
+			},
+		},
+	})
+	bInterface = graphql.NewInterface(graphql.InterfaceConfig{
+		Name:       "B",
+		Interfaces: []*graphql.Interface{aInterface},
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type: graphql.ID,
+			},
+		},
+	})
+	_, err := schemaWithFieldType(aInterface)
+	if err == nil || !strings.Contains(err.Error(), "because it would create a circular reference.") {
+		t.Fatalf("Expected circular reference error, got %v", err)
+	}
+}
+
+func TestTypeSystem_InterfacesMayImplementInterfaces_RejectsAnInterfaceImplementingItself(t *testing.T) {
+	var selfInterface *graphql.Interface
+	selfInterface = graphql.NewInterface(graphql.InterfaceConfig{
+		Name: "Self",
+		Interfaces: graphql.InterfacesThunk(func() []*graphql.Interface {
+			return []*graphql.Interface{selfInterface}
+		}),
+		Fields: graphql.Fields{
+			"id": &graphql.Field{
+				Type: graphql.ID,
+			},
+		},
+	})
+	_, err := schemaWithFieldType(selfInterface)
+	expectedError := `Type Self cannot implement itself.`
+	if err == nil || err.Error() != expectedError {
+		t.Fatalf("Expected error: %v, got %v", expectedError, err)
+	}
+}
@@ -10,6 +10,14 @@ import (
 type ValidationResult struct {
 	IsValid bool
 	Errors  []gqlerrors.FormattedError
+
+	// Complexity is the cost of each operation of the document by operation
+	// name, as computed by QueryComplexityRule when among the rules.
+	Complexity map[string]int
+
+	// complexityChecks compute again the cost of each operation by name with
+	// the values of its variables.
+	complexityChecks map[string]*complexityCheck
 }
 
 /**
@@ -43,7 +51,10 @@ func ValidateDocument(schema *Schema, astDoc *ast.Document, rules []ValidationRu
 	typeInfo := NewTypeInfo(&TypeInfoConfig{
 		Schema: schema,
 	})
-	vr.Errors = VisitUsingRules(schema, typeInfo, astDoc, rules)
+	context := visitUsingRules(schema, typeInfo, astDoc, rules)
+	vr.Errors = context.Errors()
+	vr.Complexity = context.complexity
+	vr.complexityChecks = context.complexityChecks
 	if len(vr.Errors) == 0 {
 		vr.IsValid = true
 	}
@@ -57,7 +68,10 @@ func ValidateDocument(schema *Schema, astDoc *ast.Document, rules []ValidationRu
 // Had to expose it to unit test experimental customizable validation feature,
 // but not meant for public consumption
 func VisitUsingRules(schema *Schema, typeInfo *TypeInfo, astDoc *ast.Document, rules []ValidationRuleFn) []gqlerrors.FormattedError {
+	return visitUsingRules(schema, typeInfo, astDoc, rules).Errors()
+}
 
+func visitUsingRules(schema *Schema, typeInfo *TypeInfo, astDoc *ast.Document, rules []ValidationRuleFn) *ValidationContext {
 	context := NewValidationContext(schema, astDoc, typeInfo)
 	visitors := []*visitor.VisitorOptions{}
 
@@ -68,7 +82,7 @@ func VisitUsingRules(schema *Schema, typeInfo *TypeInfo, astDoc *ast.Document, r
 
 	// Visit the whole document with each instance of all provided rules.
 	visitor.Visit(astDoc, visitor.VisitWithTypeInfo(typeInfo, visitor.VisitInParallel(visitors...)), nil)
-	return context.Errors()
+	return context
 }
 
 type HasSelectionSet interface {
@@ -95,6 +109,8 @@ type ValidationContext struct {
 	recursiveVariableUsages        map[*ast.OperationDefinition][]*VariableUsage
 	recursivelyReferencedFragments map[*ast.OperationDefinition][]*ast.FragmentDefinition
 	fragmentSpreads                map[*ast.SelectionSet][]*ast.FragmentSpread
+	complexity                     map[string]int
+	complexityChecks               map[string]*complexityCheck
 }
 
 func NewValidationContext(schema *Schema, astDoc *ast.Document, typeInfo *TypeInfo) *ValidationContext {
@@ -118,6 +134,15 @@ func (ctx *ValidationContext) Errors() []gqlerrors.FormattedError {
 	return ctx.errors
 }
 
+func (ctx *ValidationContext) setComplexity(opName string, cost int, check *complexityCheck) {
+	if ctx.complexity == nil {
+		ctx.complexity = map[string]int{}
+		ctx.complexityChecks = map[string]*complexityCheck{}
+	}
+	ctx.complexity[opName] = cost
+	ctx.complexityChecks[opName] = check
+}
+
 func (ctx *ValidationContext) Schema() *Schema {
 	return ctx.schema
 }
@@ -6,6 +6,7 @@ import (
 	"math"
 	"reflect"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/graphql-go/graphql/gqlerrors"
@@ -412,6 +413,52 @@ func valueFromAST(valueAST ast.Value, ttype Input, variables map[string]interfac
 	return nil
 }
 
+// valueFromASTUntyped produces a Golang value given a GraphQL Value AST
+// without consulting any type. Lists become []interface{}, input objects
+// become map[string]interface{} and enum values become their name.
+func valueFromASTUntyped(valueAST ast.Value, variables map[string]interface{}) interface{} {
+	switch valueAST := valueAST.(type) {
+	case *ast.IntValue:
+		if intValue, err := strconv.Atoi(valueAST.Value); err == nil {
+			return intValue
+		}
+		if floatValue, err := strconv.ParseFloat(valueAST.Value, 64); err == nil {
+			return floatValue
+		}
+	case *ast.FloatValue:
+		if floatValue, err := strconv.ParseFloat(valueAST.Value, 64); err == nil {
+			return floatValue
+		}
+	case *ast.StringValue:
+		return valueAST.Value
+	case *ast.BooleanValue:
+		return valueAST.Value
+	case *ast.EnumValue:
+		return valueAST.Value
+	case *ast.ListValue:
+		values := []interface{}{}
+		for _, itemAST := range valueAST.Values {
+			values = append(values, valueFromASTUntyped(itemAST, variables))
+		}
+		return values
+	case *ast.ObjectValue:
+		obj := map[string]interface{}{}
+		for _, field := range valueAST.Fields {
+			if field == nil || field.Name == nil {
+				continue
+			}
+			obj[field.Name.Value] = valueFromASTUntyped(field.Value, variables)
+		}
+		return obj
+	case *ast.Variable:
+		if valueAST.Name == nil || variables == nil {
+			return nil
+		}
+		return variables[valueAST.Name.Value]
+	}
+	return nil
+}
+
 func invariant(condition bool, message string) error {
 	if !condition {
 		return gqlerrors.NewFormattedError(message)